@@ -0,0 +1,324 @@
+// Package apptest boots the sales service in-process and provides support
+// for issuing requests against it and asserting the responses.
+package apptest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/apis/services/sales/mux"
+	"github.com/AlmirSai/service/business/sdk/dbtest"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/testutil"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// Issuer is the issuer of the tokens minted for the seeded users.
+const Issuer = "service project"
+
+// The users the seed data owns customers and orders for, see
+// business/sdk/migrate/sql/seed.sql.
+var (
+	AdminID = uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7")
+	UserID  = uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+)
+
+// Config holds the optional settings for booting the service.
+type Config struct {
+	Build string
+	Ready func(ctx context.Context) error
+}
+
+// User is a seeded user with a token signed for it.
+type User struct {
+	ID    uuid.UUID
+	Roles []string
+	Token string
+}
+
+// Test contains the running service, its database and the captured logs.
+type Test struct {
+	DB       *dbtest.Database
+	Server   *httptest.Server
+	Log      *logger.Logger
+	KeyStore *keystore.KeyStore
+	Admin    User // Seeded user with the ADMIN role
+	User     User // Seeded user with the USER role
+	logs     *syncBuffer
+}
+
+// New creates a migrated and seeded database for the test and boots the
+// sales service mux against it behind an httptest server. The server is
+// closed when the test finishes and the captured logs are written to the
+// test output if the test failed.
+func New(t *testing.T, testName string, cfg Config) *Test {
+	t.Helper()
+
+	if cfg.Build == "" {
+		cfg.Build = "test"
+	}
+
+	db := dbtest.New(t, testName)
+
+	ks, err := newKeyStore()
+	if err != nil {
+		t.Fatalf("should be able to construct the keystore: %s", err)
+	}
+
+	logs := syncBuffer{}
+	log := logger.New(&logs, logger.LevelDebug, "TEST", web.GetTraceID)
+
+	shutdown := make(chan os.Signal, 1)
+
+	app := mux.WebAPI(mux.Config{
+		Build:      cfg.Build,
+		Shutdown:   shutdown,
+		Log:        log,
+		DB:         db.DB,
+		Ready:      cfg.Ready,
+		Encryption: db.Encryption,
+		Pricing:    pricing.New(nil),
+		KeyStore:   ks,
+		Issuer:     Issuer,
+		Business: []bmid.Middleware{
+			bmid.Authorize(bmid.NewRoleAuthorizer(mux.Roles)),
+		},
+		Inventory: mux.InventoryConfig{
+			HoldFor: time.Hour,
+		},
+	})
+
+	srv := httptest.NewServer(app)
+
+	t.Cleanup(func() {
+		srv.Close()
+
+		if t.Failed() {
+			t.Logf("******************** LOGS ********************\n%s", logs.String())
+		}
+	})
+
+	at := Test{
+		DB:       db,
+		Server:   srv,
+		Log:      log,
+		KeyStore: ks,
+		logs:     &logs,
+	}
+
+	at.Admin = User{ID: AdminID, Roles: []string{"ADMIN"}}
+	at.Admin.Token = at.Token(t, at.Admin.ID, at.Admin.Roles...)
+
+	at.User = User{ID: UserID, Roles: []string{"USER"}}
+	at.User.Token = at.Token(t, at.User.ID, at.User.Roles...)
+
+	return &at
+}
+
+// Token mints a token for the user with the roles, signed with the active
+// key of the service's keystore.
+func (at *Test) Token(t *testing.T, userID uuid.UUID, roles ...string) string {
+	t.Helper()
+
+	kid := at.KeyStore.ActiveKID()
+
+	privatePEM, err := at.KeyStore.PrivateKey(kid)
+	if err != nil {
+		t.Fatalf("should be able to get the private key: %s", err)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privatePEM))
+	if err != nil {
+		t.Fatalf("should be able to parse the private key: %s", err)
+	}
+
+	now := time.Now()
+
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			Issuer:    Issuer,
+			IssuedAt:  jwt.NewNumericDate(now.UTC()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour).UTC()),
+		},
+		Roles: roles,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(privateKey)
+	if err != nil {
+		t.Fatalf("should be able to sign the token: %s", err)
+	}
+
+	return signed
+}
+
+// Logs returns everything the service logged so far.
+func (at *Test) Logs() string {
+	return at.logs.String()
+}
+
+// Run executes the table of requests as subtests named after the testName
+// and each entry's Name.
+func (at *Test) Run(t *testing.T, table []Table, testName string) {
+	t.Helper()
+
+	for _, tt := range table {
+		f := func(t *testing.T) {
+			t.Helper()
+
+			status, body := at.Do(t, tt.Method, tt.URL, tt.Token, tt.Input)
+
+			if status != tt.StatusCode {
+				t.Fatalf("%s: should receive a status code of %d, got %d: %s", tt.Name, tt.StatusCode, status, body)
+			}
+
+			if tt.Golden != "" {
+				testutil.GoldenJSON(t, tt.Golden, body, testutil.Volatile...)
+			}
+
+			if tt.GotResp == nil {
+				return
+			}
+
+			if err := json.Unmarshal(body, tt.GotResp); err != nil {
+				t.Fatalf("%s: should be able to unmarshal the response: %s: %s", tt.Name, err, body)
+			}
+
+			cmpFunc := tt.CmpFunc
+			if cmpFunc == nil {
+				cmpFunc = func(got any, exp any) string {
+					return cmp.Diff(got, exp)
+				}
+			}
+
+			if diff := cmpFunc(tt.GotResp, tt.ExpResp); diff != "" {
+				t.Log("DIFF")
+				t.Logf("%s", diff)
+				t.Log("GOT")
+				t.Logf("%#v", tt.GotResp)
+				t.Log("EXP")
+				t.Logf("%#v", tt.ExpResp)
+				t.Fatalf("%s: should get the expected response", tt.Name)
+			}
+		}
+
+		t.Run(testName+"-"+tt.Name, f)
+	}
+}
+
+// Do issues a single request and returns the status code and raw body. The
+// input is marshaled as JSON when it isn't nil and the token is sent as a
+// bearer token when it isn't empty.
+func (at *Test) Do(t *testing.T, method string, url string, token string, input any) (int, []byte) {
+	t.Helper()
+
+	var body io.Reader
+	if input != nil {
+		b, err := json.Marshal(input)
+		if err != nil {
+			t.Fatalf("should be able to marshal the input: %s", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, at.Server.URL+url, body)
+	if err != nil {
+		t.Fatalf("should be able to construct the request: %s", err)
+	}
+
+	if input != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := at.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("should be able to issue the request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("should be able to read the response: %s", err)
+	}
+
+	return resp.StatusCode, b
+}
+
+// =============================================================================
+
+// claims are the claims of a token minted for a test user.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// newKeyStore constructs a keystore holding a freshly generated key, so no
+// key files are needed to run the tests.
+func newKeyStore() (*keystore.KeyStore, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling key: %w", err)
+	}
+
+	block := pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}
+
+	ks := keystore.New()
+	if err := ks.Add("test", string(pem.EncodeToMemory(&block))); err != nil {
+		return nil, fmt.Errorf("adding key: %w", err)
+	}
+
+	return ks, nil
+}
+
+// syncBuffer is a bytes.Buffer that can be written to by the concurrent
+// request goroutines of the server.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,15 @@
+package apptest
+
+// Table represents fields needed for running a request test.
+type Table struct {
+	Name       string
+	URL        string
+	Token      string
+	Method     string
+	StatusCode int
+	Input      any
+	GotResp    any
+	ExpResp    any
+	CmpFunc    func(got any, exp any) string
+	Golden     string // Optional, compares the body with testdata/<Golden>.golden
+}
@@ -0,0 +1,159 @@
+// Package config defines the configuration of the sales service, so tools
+// can resolve it exactly as the service does.
+package config
+
+import (
+	"time"
+
+	"github.com/ardanlabs/conf/v3"
+)
+
+// Prefix is the prefix of the environment variables of the service.
+const Prefix = "SALES"
+
+// Config is the configuration of the sales service.
+type Config struct {
+	conf.Version
+	Environment string `conf:"default:development"`
+	Log         struct {
+		Level     string `conf:"default:INFO,help:minimum level at startup; only read from the environment"`
+		Format    string `conf:"default:json,help:json, text, console, or logfmt; only read from the environment"`
+		Collector struct {
+			Host     string `conf:"help:OTLP collector receiving the logs, empty disables; only read from the environment"`
+			Protocol string `conf:"default:grpc,help:grpc or http; only read from the environment"`
+		}
+	}
+	Alert struct {
+		WebhookURL string `conf:"mask,help:Slack compatible webhook receiving error records; only read from the environment"`
+	}
+	Web struct {
+		ReadTimeout     time.Duration `conf:"default:5s"`
+		WriteTimeout    time.Duration `conf:"default:10s"`
+		IdleTimeout     time.Duration `conf:"default:120s"`
+		ShutdownTimeout time.Duration `conf:"default:20s"`
+		APIHost         string        `conf:"default:0.0.0.0:3000"`
+		GRPCHost        string        `conf:"default:0.0.0.0:3005"`
+		DebugHost       string        `conf:"default:0.0.0.0:3010"`
+	}
+	DB struct {
+		User          string        `conf:"default:postgres"`
+		Password      string        `conf:"default:postgres,mask"`
+		HostPort      string        `conf:"default:database-service.sales-system.svc.cluster.local"`
+		Name          string        `conf:"default:postgres"`
+		MaxIdleConns  int           `conf:"default:2"`
+		MaxOpenConns  int           `conf:"default:0"`
+		DisableTLS    bool          `conf:"default:true"`
+		SlowQuery     time.Duration `conf:"default:200ms,help:queries running longer are logged, 0 disables"`
+		StatsInterval time.Duration `conf:"default:15s"`
+	}
+	Metrics struct {
+		Backend string `conf:"default:expvar,help:expvar or prometheus"`
+	}
+	Chaos struct {
+		Rules string `conf:"help:fault injection rules, ignored in production"`
+	}
+	Capture struct {
+		File    string  `conf:"help:file receiving sanitized request captures"`
+		Percent float64 `conf:"default:1,help:share of requests captured"`
+	}
+	Profiling struct {
+		URL         string        `conf:"help:Pyroscope compatible ingest URL, empty disables pushing"`
+		Interval    time.Duration `conf:"default:60s"`
+		CPUDuration time.Duration `conf:"default:10s"`
+	}
+	Shed struct {
+		Enabled         bool          `conf:"default:true"`
+		MaxInFlight     int           `conf:"default:1000"`
+		MaxQueueWait    time.Duration `conf:"default:100ms"`
+		MemoryThreshold float64       `conf:"default:0.9"`
+		Priorities      string        `conf:"help:path prefix priorities like /v1/docs=low,/v1/orders=critical"`
+	}
+	Inventory struct {
+		HoldFor       time.Duration `conf:"default:15m,help:how long stock stays reserved for an unpaid order"`
+		SweepInterval time.Duration `conf:"default:1m"`
+	}
+	Customer struct {
+		DeletedRetention  time.Duration `conf:"default:720h,help:how long deleted customers are kept before they're archived"`
+		ArchiveInterval   time.Duration `conf:"default:1h"`
+		ReencryptInterval time.Duration `conf:"default:1h"`
+	}
+	Audit struct {
+		ArchiveAfter      time.Duration `conf:"default:720h,help:age at which audits move to compressed archives"`
+		PurgeAfter        time.Duration `conf:"default:8760h,help:age at which archives are removed"`
+		RetentionInterval time.Duration `conf:"default:1h"`
+		LogFile           string        `conf:"help:file receiving the hash chained audit log, empty disables; only read from the environment"`
+	}
+	Pricing struct {
+		Taxes string `conf:"help:tax jurisdictions like US-FL=7%;US-NY=8.875%:half-even"`
+	}
+	Tempo struct {
+		Host        string `conf:"help:OTLP gRPC collector host like tempo:4317, empty disables exporting"`
+		ServiceName string `conf:"default:sales"`
+	}
+	Auth struct {
+		KeysFolder string `conf:"default:deployments/keys/"`
+		Issuer     string `conf:"default:service project"`
+		GRPCHost   string `conf:"help:auth service gRPC host like auth-service:3015, empty disables the client"`
+	}
+	Startup struct {
+		RetryInterval time.Duration `conf:"default:2s"`
+	}
+	Secrets struct {
+		Folder     string        `conf:"help:folder of mounted secret files"`
+		VaultAddr  string        `conf:"help:Vault address like https://vault:8200, empty disables the provider"`
+		VaultToken string        `conf:"mask"`
+		VaultMount string        `conf:"default:secret"`
+		AWSRegion  string        `conf:"help:AWS Secrets Manager region, empty disables the provider"`
+		TTL        time.Duration `conf:"default:5m"`
+	}
+	Encryption struct {
+		ActiveKey string `conf:"default:1"`
+		Keys      string `conf:"mask,help:AES-256 keys like 1=base64;2=base64, empty disables encryption"`
+		IndexKey  string `conf:"mask,help:base64 key for the blind indexes of encrypted values"`
+	}
+	Runtime Runtime
+	Reload  struct {
+		File     string        `conf:"help:JSON file with dynamic settings, watched for changes"`
+		Interval time.Duration `conf:"default:5s"`
+	}
+	Dynamic Dynamic
+}
+
+// New returns the configuration with the version information set, ready to
+// be parsed.
+func New(build string) Config {
+	return Config{
+		Version: conf.Version{
+			Build: build,
+			Desc:  "Sales",
+		},
+	}
+}
+
+// Runtime holds the knobs used to size the Go runtime to the container.
+type Runtime struct {
+	MaxProcs       int     `conf:"default:0,help:overrides GOMAXPROCS when greater than zero"`
+	MemLimit       int64   `conf:"default:0,help:overrides GOMEMLIMIT in bytes when greater than zero"`
+	MemLimitRatio  float64 `conf:"default:0.9,help:fraction of the cgroup memory limit used for GOMEMLIMIT"`
+	DisableCgroups bool    `conf:"default:false,help:ignore container limits and keep runtime defaults"`
+}
+
+// Dynamic holds the settings that can change while the service is running.
+// They are re-read on SIGHUP or when the reload file changes.
+type Dynamic struct {
+	LogLevel      string   `conf:"help:overrides the startup log level while set"`
+	RateLimit     int      `conf:"default:0,help:requests per second per client, 0 disables"`
+	FeatureFlags  []string `conf:"help:comma separated list of enabled features: read-only"`
+	TraceSampling float64  `conf:"default:0.05"`
+	ActiveKID     string   `conf:"help:kid used for signing, defaults to the newest key file"`
+}
+
+// Enabled reports whether the named feature flag is turned on.
+func (d Dynamic) Enabled(feature string) bool {
+	for _, f := range d.FeatureFlags {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
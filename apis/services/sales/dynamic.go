@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/AlmirSai/service/apis/services/sales/config"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/ardanlabs/conf/v3"
+)
+
+// loadDynamic returns a function that re-parses the dynamic settings from
+// the defaults, the optional JSON reload file and the environment. Values
+// from the environment win over the file, so a variable set on the pod pins
+// that setting until it's removed.
+func loadDynamic(prefix string, file string) func() (config.Dynamic, error) {
+	return func() (config.Dynamic, error) {
+		cfg := struct {
+			Dynamic config.Dynamic
+		}{}
+
+		if _, err := conf.Parse(prefix, &cfg, dynamicFile(file)); err != nil {
+			return config.Dynamic{}, fmt.Errorf("parsing dynamic config: %w", err)
+		}
+
+		if cfg.Dynamic.LogLevel != "" {
+			if _, err := logger.ParseLevel(cfg.Dynamic.LogLevel); err != nil {
+				return config.Dynamic{}, fmt.Errorf("parsing log level: %w", err)
+			}
+		}
+
+		if cfg.Dynamic.TraceSampling < 0 || cfg.Dynamic.TraceSampling > 1 {
+			return config.Dynamic{}, fmt.Errorf("trace sampling %v out of range [0, 1]", cfg.Dynamic.TraceSampling)
+		}
+
+		return cfg.Dynamic, nil
+	}
+}
+
+// applyLogLevel updates the logger's minimum level from the settings. When
+// the setting is removed the level goes back to the startup level.
+func applyLogLevel(log *logger.Logger, startup logger.Level) func(ctx context.Context, d config.Dynamic) {
+	return func(ctx context.Context, d config.Dynamic) {
+		lvl := startup
+		if d.LogLevel != "" {
+			var err error
+			if lvl, err = logger.ParseLevel(d.LogLevel); err != nil {
+				return
+			}
+		}
+
+		prev := log.GetLevel()
+		if prev == lvl {
+			return
+		}
+
+		// Log before applying so the change is recorded when the level is raised.
+		log.Info(ctx, "dynamic", "status", "log level changed", "from", prev, "to", lvl)
+		log.SetLevel(lvl)
+	}
+}
+
+// applyKeyRotation reloads the key files and activates the configured kid, or
+// the newest key when none is configured. Keys that are no longer active stay
+// valid for verification for as long as their files remain in the folder.
+func applyKeyRotation(log *logger.Logger, ks *keystore.KeyStore, fsys fs.FS) func(ctx context.Context, d config.Dynamic) {
+	return func(ctx context.Context, d config.Dynamic) {
+		n, err := ks.LoadKeys(fsys)
+		if err != nil {
+			log.Error(ctx, "keystore", "status", "reloading keys, keeping previous keys", "error", err)
+			return
+		}
+
+		if d.ActiveKID != "" {
+			if err := ks.Rotate(d.ActiveKID); err != nil {
+				log.Error(ctx, "keystore", "status", "activating kid", "kid", d.ActiveKID, "error", err)
+			}
+		}
+
+		log.Info(ctx, "keystore", "status", "keys loaded", "count", n, "active", ks.ActiveKID())
+	}
+}
+
+// =============================================================================
+
+// dynamicFile is a conf parser that decodes a JSON document into the dynamic
+// settings, for example {"LogLevel":"DEBUG","FeatureFlags":["beta"]}.
+type dynamicFile string
+
+// Process implements the conf.Parsers interface.
+func (f dynamicFile) Process(prefix string, cfg any) error {
+	if f == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(string(f))
+	if err != nil {
+		// A missing file simply means nothing is overridden yet.
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	c, ok := cfg.(*struct{ Dynamic config.Dynamic })
+	if !ok {
+		return errors.New("dynamic file: unexpected config type")
+	}
+
+	return json.Unmarshal(b, &c.Dynamic)
+}
@@ -2,16 +2,55 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
-	"os/signal"
 	"runtime"
-	"syscall"
+	"time"
 
+	"github.com/AlmirSai/service/apis/services/sales/config"
+	"github.com/AlmirSai/service/apis/services/sales/mux"
+	"github.com/AlmirSai/service/app/sdk/capture"
+	"github.com/AlmirSai/service/app/sdk/grpcclient"
+	"github.com/AlmirSai/service/app/sdk/grpcsrv"
+	"github.com/AlmirSai/service/app/sdk/locale"
+	"github.com/AlmirSai/service/app/sdk/mid"
+	"github.com/AlmirSai/service/business/sdk/jobs"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/business/sdk/migrate"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/AlmirSai/service/foundation/health"
+	"github.com/AlmirSai/service/foundation/httpclient"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/locker"
 	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/logger/alert"
+	"github.com/AlmirSai/service/foundation/logger/audit"
+	"github.com/AlmirSai/service/foundation/logger/otlp"
+	"github.com/AlmirSai/service/foundation/metrics"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/AlmirSai/service/foundation/profiler"
+	"github.com/AlmirSai/service/foundation/ratelimit"
+	"github.com/AlmirSai/service/foundation/reload"
+	"github.com/AlmirSai/service/foundation/safego"
+	"github.com/AlmirSai/service/foundation/scheduler"
+	"github.com/AlmirSai/service/foundation/secrets"
+	"github.com/AlmirSai/service/foundation/shutdown"
+	"github.com/AlmirSai/service/foundation/startup"
+	"github.com/AlmirSai/service/foundation/version"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/ardanlabs/conf/v3"
+	"github.com/jmoiron/sqlx"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-var build string = "develop"
-
 func main() {
 	var log *logger.Logger
 
@@ -21,32 +60,709 @@ func main() {
 		},
 	}
 
+	// Error records are posted to the webhook when one is configured.
+	var webhook *alert.Webhook
+	if url := os.Getenv(config.Prefix + "_ALERT_WEBHOOK_URL"); url != "" {
+		webhook = alert.New(alert.Config{
+			URL:     url,
+			Service: "SALES",
+		})
+		events.Error = webhook.Event
+	}
+
 	traceIDFn := func(ctx context.Context) string {
-		// TODO: Implement a function to extract trace ID from context
-		// TODO: web.GetTraceID(ctx)
-		return ""
+		return web.GetTraceID(ctx)
 	}
 
-	log = logger.NewWithEvents(os.Stdout, logger.LevelInfo, "SALES", traceIDFn, events)
+	// The logger exists before the configuration is parsed, so the level and
+	// format are taken straight from the environment. Bad values fail startup
+	// once the logger, built with the defaults, can report them.
+	level, levelErr := logger.LevelFromEnv(config.Prefix+"_LOG_LEVEL", logger.LevelInfo)
+
+	format, formatErr := logger.ParseFormat(os.Getenv(config.Prefix + "_LOG_FORMAT"))
 
 	ctx := context.Background()
 
-	if err := run(ctx, log); err != nil {
-		log.Error(ctx, "failed to run sales service", "error", err)
-		panic("failed to run sales service: " + err.Error())
+	// The span ID lets a log line be matched to the exact span of the trace.
+	spanAttrsFn := func(ctx context.Context) []slog.Attr {
+		if id := otel.GetSpanID(ctx); id != "" {
+			return []slog.Attr{slog.String("span_id", id)}
+		}
+		return nil
+	}
+
+	opts := []logger.Option{
+		logger.WithFormat(format),
+		logger.WithContextAttrs(spanAttrsFn),
+		logger.WithRedaction(logger.DefaultRedactKeys),
+		logger.WithRuntimeAttrs(os.Getenv(config.Prefix + "_ENVIRONMENT")),
+	}
+
+	// Exporting to a collector is optional, the records are written to
+	// stdout either way.
+	var sink *otlp.Handler
+	var sinkErr error
+	if host := os.Getenv(config.Prefix + "_LOG_COLLECTOR_HOST"); host != "" {
+		sink, sinkErr = otlp.New(ctx, otlp.Config{
+			ServiceName: "sales",
+			Host:        host,
+			Protocol:    os.Getenv(config.Prefix + "_LOG_COLLECTOR_PROTOCOL"),
+			MinLevel:    level,
+		})
+		if sinkErr == nil {
+			opts = append(opts, logger.WithSink(sink))
+		}
+	}
+
+	// Audit records also go to a hash chained file, so tampering with the
+	// trail can be detected.
+	var auditLog *audit.Handler
+	var auditErr error
+	if file := os.Getenv(config.Prefix + "_AUDIT_LOG_FILE"); file != "" {
+		auditLog, auditErr = audit.Open(file)
+		if auditErr == nil {
+			opts = append(opts, logger.WithSink(auditLog))
+		}
+	}
+
+	// Flush the exporters and the audit log before a fatal record ends the
+	// process.
+	opts = append(opts, logger.WithFatalHook(func(ctx context.Context) {
+		if sink != nil {
+			sink.Shutdown(ctx)
+		}
+		if webhook != nil {
+			webhook.Shutdown(ctx)
+		}
+		if auditLog != nil {
+			auditLog.Close()
+		}
+	}))
+
+	log = logger.NewWithEvents(os.Stdout, level, "SALES", traceIDFn, events, opts...)
+
+	if levelErr != nil {
+		log.Fatal(ctx, "failed to parse log level", "error", levelErr)
+	}
+
+	if formatErr != nil {
+		log.Fatal(ctx, "failed to parse log format", "error", formatErr)
+	}
+
+	if sinkErr != nil {
+		log.Error(ctx, "startup", "status", "log export disabled", "error", sinkErr)
+	}
+
+	if auditErr != nil {
+		log.Fatal(ctx, "failed to open audit log", "error", auditErr)
+	}
+
+	if err := run(ctx, log, webhook); err != nil {
+		log.Fatal(ctx, "failed to run sales service", "error", err)
+	}
+
+	if sink != nil {
+		sink.Shutdown(ctx)
+	}
+
+	if webhook != nil {
+		webhook.Shutdown(ctx)
+	}
+
+	if auditLog != nil {
+		auditLog.Close()
 	}
 }
 
-func run(ctx context.Context, log *logger.Logger) error {
+func run(ctx context.Context, log *logger.Logger, webhook *alert.Webhook) error {
+	build := version.Version()
+
+	// -------------------------------------------------------------------------
+	// Configuration
+
+	cfg := config.New(build)
+
+	help, err := conf.Parse(config.Prefix, &cfg)
+	if err != nil {
+		if errors.Is(err, conf.ErrHelpWanted) {
+			fmt.Println(help)
+			return nil
+		}
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	if r := cfg.Runtime.MemLimitRatio; r <= 0 || r > 1 {
+		return fmt.Errorf("memory limit ratio %v out of range (0, 1]", r)
+	}
+
+	// -------------------------------------------------------------------------
+	// App Starting
+
+	// Tune first so the startup line reports the GOMAXPROCS in effect.
+	tuneRuntime(ctx, log, cfg.Runtime)
+
 	log.Info(ctx, "startup", "GOMAXPROCS", runtime.GOMAXPROCS(0), "build", build)
 
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+	out, err := conf.String(&cfg)
+	if err != nil {
+		return fmt.Errorf("generating config for output: %w", err)
+	}
+	log.Info(ctx, "startup", "config", out)
+
+	// -------------------------------------------------------------------------
+	// Shutdown Support
+
+	// Subsystems register how they stop as they start. The deferred call
+	// cleans up whatever was started when startup fails part way through.
+	// Servers get the shutdown timeout to drain, the remaining phases share
+	// what's left of the overall budget.
+	sd := shutdown.New(shutdown.Config{
+		Log:     log,
+		Timeout: cfg.Web.ShutdownTimeout + 10*time.Second,
+	})
+	defer sd.Shutdown(context.Background())
+
+	// -------------------------------------------------------------------------
+	// Secrets
+
+	// Settings holding a secret:// reference are resolved after the config
+	// is logged, so only the reference ever appears in the output.
+	secretStore := secrets.New(secrets.Config{
+		Log: log,
+		TTL: cfg.Secrets.TTL,
+	})
+
+	secretStore.Register("env", secrets.Env{})
+	if cfg.Secrets.Folder != "" {
+		secretStore.Register("file", secrets.NewFile(cfg.Secrets.Folder))
+	}
+	if cfg.Secrets.VaultAddr != "" {
+		secretStore.Register("vault", secrets.NewVault(secrets.VaultConfig{
+			Addr:  cfg.Secrets.VaultAddr,
+			Token: cfg.Secrets.VaultToken,
+			Mount: cfg.Secrets.VaultMount,
+		}))
+	}
+	if cfg.Secrets.AWSRegion != "" {
+		secretStore.Register("aws", secrets.NewAWS(secrets.AWSConfig{
+			Region: cfg.Secrets.AWSRegion,
+		}))
+	}
+
+	if err := secretStore.Resolve(ctx, &cfg); err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Dynamic Settings
+
+	watcher, err := reload.New(reload.Config[config.Dynamic]{
+		Log:      log,
+		Load:     loadDynamic(config.Prefix, cfg.Reload.File),
+		File:     cfg.Reload.File,
+		Interval: cfg.Reload.Interval,
+	})
+	if err != nil {
+		return fmt.Errorf("loading dynamic settings: %w", err)
+	}
+
+	watcher.OnChange(ctx, applyLogLevel(log, log.GetLevel()))
+
+	// -------------------------------------------------------------------------
+	// Start Tracing Support
+
+	log.Info(ctx, "startup", "status", "initializing tracing support")
+
+	tracing, err := otel.InitTracing(ctx, log, otel.Config{
+		ServiceName:    cfg.Tempo.ServiceName,
+		ServiceVersion: build,
+		Host:           cfg.Tempo.Host,
+		Probability:    watcher.Current().TraceSampling,
+		ExcludedRoutes: map[string]struct{}{
+			"/v1/liveness":  {},
+			"/v1/readiness": {},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("starting tracing: %w", err)
+	}
+
+	sd.Register(shutdown.PhaseFlush, "tracing", 0, tracing.Shutdown)
+
+	watcher.OnChange(ctx, func(ctx context.Context, d config.Dynamic) {
+		tracing.SetProbability(d.TraceSampling)
+	})
+
+	tracer := tracing.Tracer(cfg.Tempo.ServiceName)
+
+	// -------------------------------------------------------------------------
+	// Auth Support
+
+	log.Info(ctx, "startup", "status", "initializing authentication support", "folder", cfg.Auth.KeysFolder)
+
+	// Keys are re-read with the dynamic settings, so a new key file plus a
+	// SIGHUP rotates signing without a restart.
+	ks := keystore.New()
+	watcher.OnChange(ctx, applyKeyRotation(log, ks, os.DirFS(cfg.Auth.KeysFolder)))
+
+	// -------------------------------------------------------------------------
+	// Encryption Support
+
+	// Customer personal data is encrypted when keys are configured. Rotating
+	// means adding a key and making it active; the re-encrypt job moves the
+	// existing rows over and the old key can be dropped once it's done.
+	var crypt *encrypt.Keyring
+	if cfg.Encryption.Keys == "" {
+		log.Warn(ctx, "startup", "status", "encryption disabled, personal data is stored in plaintext")
+	} else {
+		keys, err := encrypt.ParseKeys(cfg.Encryption.Keys)
+		if err != nil {
+			return fmt.Errorf("parsing encryption keys: %w", err)
+		}
+
+		indexKey, err := base64.StdEncoding.DecodeString(cfg.Encryption.IndexKey)
+		if err != nil {
+			return fmt.Errorf("parsing encryption index key: %w", err)
+		}
+
+		crypt, err = encrypt.New(cfg.Encryption.ActiveKey, keys, indexKey)
+		if err != nil {
+			return fmt.Errorf("constructing keyring: %w", err)
+		}
+
+		log.Info(ctx, "startup", "status", "encryption enabled", "active_key", crypt.Active(), "keys", len(keys))
+	}
+
+	// Background workers run until the service begins shutting down.
+	workers := safego.New(ctx, log)
+
+	sd.Register(shutdown.PhaseWorkers, "background workers", 0, workers.Stop)
+
+	workers.Go("config watcher", watcher.Run)
+	workers.Go("secrets refresh", secretStore.Run)
+
+	// -------------------------------------------------------------------------
+	// Continuous Profiling
+
+	if cfg.Profiling.URL != "" {
+		pod := os.Getenv("KUBERNETES_NAME")
+		if pod == "" {
+			pod, _ = os.Hostname()
+		}
+
+		prof, err := profiler.New(profiler.Config{
+			Log:     log,
+			URL:     cfg.Profiling.URL,
+			AppName: "sales",
+			Labels: map[string]string{
+				"service": "sales",
+				"version": build,
+				"pod":     pod,
+			},
+			Interval:    cfg.Profiling.Interval,
+			CPUDuration: cfg.Profiling.CPUDuration,
+		})
+		if err != nil {
+			return fmt.Errorf("constructing profiler: %w", err)
+		}
+
+		log.Info(ctx, "startup", "status", "continuous profiling enabled", "url", cfg.Profiling.URL)
+
+		workers.Go("profiler", prof.Run)
+	}
+
+	// -------------------------------------------------------------------------
+	// Start Debug Service
+
+	metricsProvider, err := metrics.New(metrics.Backend(cfg.Metrics.Backend))
+	if err != nil {
+		return fmt.Errorf("constructing metrics: %w", err)
+	}
+
+	log.SetMetrics(metricsProvider)
+
+	debugMux := http.NewServeMux()
+	debugMux.Handle("GET /metrics", metricsProvider.Handler())
+
+	debug := http.Server{
+		Addr:     cfg.Web.DebugHost,
+		Handler:  debugMux,
+		ErrorLog: logger.NewStdLogger(log, logger.LevelError),
+	}
+
+	safego.Go(ctx, log, "debug router", func(ctx context.Context) {
+		log.Info(ctx, "startup", "status", "debug router started", "host", debug.Addr, "metrics", cfg.Metrics.Backend)
+
+		if err := debug.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(ctx, "shutdown", "status", "debug router closed", "host", debug.Addr, "error", err)
+		}
+	})
 
-	sig := <-shutdown
+	sd.Register(shutdown.PhaseServers, "debug router", 0, debug.Shutdown)
+
+	// -------------------------------------------------------------------------
+	// Start API Service
+
+	log.Info(ctx, "startup", "status", "initializing V1 API support")
+
+	chaos, err := mid.ParseChaosRules(cfg.Chaos.Rules)
+	if err != nil {
+		return fmt.Errorf("parsing chaos rules: %w", err)
+	}
+
+	if len(chaos) > 0 {
+		if cfg.Environment == mux.Production {
+			log.Warn(ctx, "startup", "status", "chaos rules ignored in production")
+		} else {
+			log.Warn(ctx, "startup", "status", "chaos fault injection enabled", "rules", len(chaos))
+		}
+	}
+
+	var captureSink capture.Sink
+	if cfg.Capture.File != "" {
+		fs, err := capture.NewFileSink(cfg.Capture.File)
+		if err != nil {
+			return fmt.Errorf("opening capture sink: %w", err)
+		}
+		sd.Register(shutdown.PhaseFlush, "capture sink", 0, func(ctx context.Context) error {
+			return fs.Close()
+		})
+
+		log.Info(ctx, "startup", "status", "request capture enabled", "file", cfg.Capture.File, "percent", cfg.Capture.Percent)
+		captureSink = fs
+	}
+
+	var shed *mid.ShedConfig
+	if cfg.Shed.Enabled {
+		prios, err := mid.ParseShedPriorities(cfg.Shed.Priorities)
+		if err != nil {
+			return fmt.Errorf("parsing shed priorities: %w", err)
+		}
+
+		shed = &mid.ShedConfig{
+			MaxInFlight:     cfg.Shed.MaxInFlight,
+			MaxQueueWait:    cfg.Shed.MaxQueueWait,
+			MemoryThreshold: cfg.Shed.MemoryThreshold,
+			Priorities:      prios,
+		}
+	}
+
+	// The limit follows the dynamic settings, a limit of 0 lets every
+	// request through.
+	limiter := ratelimit.NewMemory(ratelimit.Config{
+		Limit: watcher.Current().RateLimit,
+	})
+
+	watcher.OnChange(ctx, func(ctx context.Context, d config.Dynamic) {
+		limiter.SetLimit(d.RateLimit)
+	})
+
+	// -------------------------------------------------------------------------
+	// Database Support
+
+	log.Info(ctx, "startup", "status", "initializing database support", "hostport", cfg.DB.HostPort)
+
+	db, err := sqldb.Open(sqldb.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		HostPort:     cfg.DB.HostPort,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+		Log:          log,
+	})
+	if err != nil {
+		return fmt.Errorf("connecting to db: %w", err)
+	}
+
+	sd.Register(shutdown.PhaseResources, "database", 0, func(ctx context.Context) error {
+		return db.Close()
+	})
+
+	sqldb.SetSlowQueryThreshold(cfg.DB.SlowQuery)
+
+	debugMux.Handle("GET /debug/migrations", migrations(log, db))
+
+	workers.Go("database stats", func(ctx context.Context) {
+		sqldb.CollectStats(ctx, db, metricsProvider, cfg.DB.StatsInterval)
+	})
+
+	// -------------------------------------------------------------------------
+	// Background Jobs
+
+	queue := jobs.New(log, jobs.NewPostgres(log, db))
+
+	// Alerts go through the queue once the database is up, so a webhook that
+	// is down delays them instead of losing them.
+	if webhook != nil {
+		webhook.SetDeliver(func(ctx context.Context, msg alert.Message) error {
+			return queue.Enqueue(ctx, alertQueue, msg)
+		})
+
+		workers.Go("alert delivery", func(ctx context.Context) {
+			queue.Consume(ctx, alertQueue, jobs.Config{}, deliverAlert(webhook))
+		})
+	}
+
+	// -------------------------------------------------------------------------
+	// Startup Checks
+
+	// Readiness is withheld until the critical dependency checks pass. HTTP
+	// and gRPC health both report the state of the gate, which reads the
+	// checks from the registry.
+	checks := health.New()
+	gate := startup.New(log, checks)
+
+	checks.Register(health.Check{
+		Name:    "database",
+		Level:   health.Critical,
+		Timeout: 5 * time.Second,
+		Fn: func(ctx context.Context) error {
+			return sqldb.StatusCheck(ctx, db)
+		},
+	})
+
+	if cfg.Profiling.URL != "" {
+		checks.Register(health.Check{
+			Name:  "profiling",
+			Level: health.NonCritical,
+			Fn:    reachable(httpclient.New(httpclient.Config{Name: "profiling", Log: log}), cfg.Profiling.URL),
+		})
+	}
+
+	if cfg.Auth.GRPCHost != "" {
+		authConn, err := grpcclient.New(cfg.Auth.GRPCHost, grpcclient.Config{
+			Name: "auth",
+			Log:  log,
+		})
+		if err != nil {
+			return fmt.Errorf("constructing auth client: %w", err)
+		}
+
+		sd.Register(shutdown.PhaseResources, "auth client", 0, func(ctx context.Context) error {
+			return authConn.Close()
+		})
+
+		checks.Register(health.Check{
+			Name:    "auth",
+			Level:   health.NonCritical,
+			Timeout: 5 * time.Second,
+			Fn:      serving(healthpb.NewHealthClient(authConn)),
+		})
+	}
+
+	workers.Go("startup checks", func(ctx context.Context) {
+		if err := gate.RunUntilReady(ctx, cfg.Startup.RetryInterval); err != nil {
+			log.Info(ctx, "startup checks", "status", "stopped", "error", err)
+		}
+	})
+
+	ready := gate.Ready
+
+	jurisdictions, err := pricing.ParseJurisdictions(cfg.Pricing.Taxes)
+	if err != nil {
+		return fmt.Errorf("parsing tax jurisdictions: %w", err)
+	}
+
+	bundle, err := locale.New()
+	if err != nil {
+		return fmt.Errorf("loading message catalogs: %w", err)
+	}
+
+	sched := scheduler.New(log)
+
+	// Business middleware runs for HTTP and gRPC calls alike. Refused calls
+	// are still measured and audited, but never open a transaction.
+	business := []bmid.Middleware{
+		bmid.Metrics(metricsProvider),
+		bmid.Audit(log, bmid.NewLogAuditor(log)),
+		bmid.Authorize(bmid.NewRoleAuthorizer(mux.Roles)),
+		bmid.BeginCommitRollback(log, sqldb.NewTran(db)),
+	}
+
+	webAPI := mux.WebAPI(mux.Config{
+		Build:       build,
+		Environment: cfg.Environment,
+		Shutdown:    sd.Signals(),
+		Log:         log,
+		DB:          db,
+		Ready:       ready,
+		Chaos:       chaos,
+		Capture:     captureSink,
+		CapturePct:  cfg.Capture.Percent,
+		Shed:        shed,
+		RateLimiter: limiter,
+		Metrics:     metricsProvider,
+		Locale:      bundle,
+		Scheduler:   sched,
+		Locker:      locker.NewPostgres(db.DB, locker.PostgresConfig{Log: log}),
+		Inventory: mux.InventoryConfig{
+			HoldFor:       cfg.Inventory.HoldFor,
+			SweepInterval: cfg.Inventory.SweepInterval,
+		},
+		Customer: mux.CustomerConfig{
+			DeletedRetention:  cfg.Customer.DeletedRetention,
+			ArchiveInterval:   cfg.Customer.ArchiveInterval,
+			ReencryptInterval: cfg.Customer.ReencryptInterval,
+		},
+		Encryption: crypt,
+		Audit: mux.AuditConfig{
+			ArchiveAfter:      cfg.Audit.ArchiveAfter,
+			PurgeAfter:        cfg.Audit.PurgeAfter,
+			RetentionInterval: cfg.Audit.RetentionInterval,
+		},
+		Pricing:  pricing.New(jurisdictions),
+		KeyStore: ks,
+		Issuer:   cfg.Auth.Issuer,
+		Tracer:   tracer,
+		Business: business,
+		Features: func(feature string) bool {
+			return watcher.Current().Enabled(feature)
+		},
+	})
+
+	workers.Go("scheduler", sched.Run)
+
+	api := http.Server{
+		Addr:         cfg.Web.APIHost,
+		Handler:      webAPI,
+		ReadTimeout:  cfg.Web.ReadTimeout,
+		WriteTimeout: cfg.Web.WriteTimeout,
+		IdleTimeout:  cfg.Web.IdleTimeout,
+		ErrorLog:     logger.NewStdLogger(log, logger.LevelError),
+	}
+
+	serverErrors := make(chan error, 2)
+
+	safego.Go(ctx, log, "api router", func(ctx context.Context) {
+		log.Info(ctx, "startup", "status", "api router started", "host", api.Addr)
+
+		serverErrors <- api.ListenAndServe()
+	})
+
+	sd.Register(shutdown.PhaseServers, "api router", cfg.Web.ShutdownTimeout, func(ctx context.Context) error {
+		if err := api.Shutdown(ctx); err != nil {
+			api.Close()
+			return fmt.Errorf("could not stop server gracefully: %w", err)
+		}
+		return nil
+	})
+
+	// -------------------------------------------------------------------------
+	// Start gRPC Service
+
+	grpcAPI := grpcsrv.New(grpcsrv.Config{
+		Log:      log,
+		Ready:    ready,
+		Business: business,
+	})
+
+	workers.Go("grpc readiness", grpcAPI.WatchReadiness)
+
+	grpcListener, err := net.Listen("tcp", cfg.Web.GRPCHost)
+	if err != nil {
+		return fmt.Errorf("grpc listen: %w", err)
+	}
+
+	safego.Go(ctx, log, "grpc router", func(ctx context.Context) {
+		log.Info(ctx, "startup", "status", "grpc router started", "host", cfg.Web.GRPCHost)
+
+		serverErrors <- grpcAPI.Serve(grpcListener)
+	})
+
+	sd.Register(shutdown.PhaseServers, "grpc router", cfg.Web.ShutdownTimeout, func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		safego.Go(ctx, log, "grpc graceful stop", func(ctx context.Context) {
+			defer close(stopped)
+			grpcAPI.GracefulStop()
+		})
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			grpcAPI.Stop()
+			return ctx.Err()
+		}
+	})
+
+	// -------------------------------------------------------------------------
+	// Shutdown
+
+	return sd.Wait(serverErrors)
+}
+
+// alertQueue is the job queue delivering alert messages to the webhook.
+const alertQueue = "alerts"
+
+// deliverAlert returns a job handler posting the queued message to the
+// webhook. A message the webhook refuses is dead-lettered right away.
+func deliverAlert(webhook *alert.Webhook) jobs.HandlerFunc {
+	return func(ctx context.Context, job jobs.Job) error {
+		var msg alert.Message
+		if err := job.Decode(&msg); err != nil {
+			return jobs.Permanent(err)
+		}
+
+		err := webhook.Post(ctx, msg)
+
+		var se *alert.StatusError
+		if errors.As(err, &se) && !se.Temporary() {
+			return jobs.Permanent(err)
+		}
+
+		return err
+	}
+}
+
+// reachable returns a check that succeeds when the HTTP endpoint answers.
+func reachable(client *httpclient.Client, url string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		return nil
+	}
+}
+
+// serving returns a check that succeeds when the gRPC dependency reports
+// itself as serving.
+func serving(client healthpb.HealthClient) health.CheckFunc {
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", resp.GetStatus())
+		}
+
+		return nil
+	}
+}
 
-	log.Info(ctx, "shutdown", "status", "shutdown started", "signal", sig)
-	defer log.Info(ctx, "shutdown", "status", "shutdown completed", "signal", sig)
+// migrations returns a handler reporting the applied and pending migrations
+// of the database.
+func migrations(log *logger.Logger, db *sqlx.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses, err := migrate.Status(r.Context(), db)
+		if err != nil {
+			log.Error(r.Context(), "debug", "status", "migration status", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	return nil
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statuses)
+	})
 }
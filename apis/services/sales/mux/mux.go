@@ -0,0 +1,280 @@
+// Package mux provides support to bind domain level routes
+// to the application mux.
+package mux
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/AlmirSai/service/app/domain/auditapp"
+	"github.com/AlmirSai/service/app/domain/authapp"
+	"github.com/AlmirSai/service/app/domain/checkapp"
+	"github.com/AlmirSai/service/app/domain/customerapp"
+	"github.com/AlmirSai/service/app/domain/docsapp"
+	"github.com/AlmirSai/service/app/domain/inventoryapp"
+	"github.com/AlmirSai/service/app/domain/orderapp"
+	"github.com/AlmirSai/service/app/domain/vorderapp"
+	"github.com/AlmirSai/service/app/sdk/capture"
+	"github.com/AlmirSai/service/app/sdk/mid"
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/domain/auditbus/stores/auditdb"
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/domain/customerbus/stores/customercache"
+	"github.com/AlmirSai/service/business/domain/customerbus/stores/customerdb"
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/business/domain/inventorybus/stores/inventorydb"
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/domain/orderbus/stores/orderdb"
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/domain/vorderbus/stores/vorderdb"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/AlmirSai/service/foundation/i18n"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/locker"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/metrics"
+	"github.com/AlmirSai/service/foundation/ratelimit"
+	"github.com/AlmirSai/service/foundation/scheduler"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Production is the environment name in which fault injection is refused.
+const Production = "production"
+
+// FeatureReadOnly is the feature flag that rejects the requests changing
+// state, see mid.ReadOnly.
+const FeatureReadOnly = "read-only"
+
+// Roles are the roles allowed to call the routes. The docs and the key set
+// are public, the health checks bypass the middleware altogether.
+var Roles = bmid.Roles{
+	Public: []string{
+		"GET /v1/docs/{$}",
+		"GET /v1/docs/openapi.json",
+		"GET /v1/auth/jwks",
+	},
+	Read:  []string{"ADMIN", "USER"},
+	Write: []string{"ADMIN"},
+}
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build       string
+	Environment string
+	Shutdown    chan os.Signal
+	Log         *logger.Logger
+	DB          *sqlx.DB
+	Ready       checkapp.ReadyFunc
+	Chaos       []mid.ChaosRule
+	Capture     capture.Sink
+	CapturePct  float64
+	Shed        *mid.ShedConfig
+	RateLimiter ratelimit.Limiter
+	Metrics     metrics.Provider
+	Locale      *i18n.Bundle
+	Scheduler   *scheduler.Scheduler
+	Locker      locker.Locker
+	Inventory   InventoryConfig
+	Audit       AuditConfig
+	Customer    CustomerConfig
+	Encryption  *encrypt.Keyring
+	Pricing     *pricing.Calculator
+	KeyStore    *keystore.KeyStore
+	Issuer      string // Issuer the tokens must carry, empty accepts any
+	Tracer      trace.Tracer
+	Business    []bmid.Middleware
+	Features    func(feature string) bool // Reports whether a feature flag is on, may be nil
+}
+
+// InventoryConfig controls how long stock is held for unpaid orders and how
+// often expired holds are swept.
+type InventoryConfig struct {
+	HoldFor       time.Duration
+	SweepInterval time.Duration
+}
+
+// CustomerConfig controls how long deleted customers are kept before they're
+// archived and how often the archive and re-encrypt jobs run.
+type CustomerConfig struct {
+	DeletedRetention  time.Duration
+	ArchiveInterval   time.Duration
+	ReencryptInterval time.Duration
+}
+
+// AuditConfig controls how long audits are kept online, how long their
+// archives are kept and how often the retention job runs.
+type AuditConfig struct {
+	ArchiveAfter      time.Duration
+	PurgeAfter        time.Duration
+	RetentionInterval time.Duration
+}
+
+// WebAPI constructs a web.App with all application routes bound to it.
+func WebAPI(cfg Config) *web.App {
+	mw := []web.Middleware{
+		mid.Logger(cfg.Log),
+	}
+
+	if cfg.Locale != nil {
+		mw = append(mw, mid.Localize(cfg.Locale))
+	}
+
+	if cfg.Metrics != nil {
+		mw = append(mw, mid.Metrics(cfg.Metrics))
+	}
+
+	mw = append(mw,
+		mid.Errors(cfg.Log),
+		mid.Panics(),
+	)
+
+	if cfg.RateLimiter != nil {
+		mw = append(mw, mid.RateLimit(cfg.Log, cfg.RateLimiter, mid.ClientIP))
+	}
+
+	if cfg.Shed != nil {
+		mw = append(mw, mid.Shed(cfg.Log, *cfg.Shed))
+	}
+
+	if cfg.Capture != nil {
+		mw = append(mw, mid.Capture(cfg.Log, cfg.Capture, cfg.CapturePct))
+	}
+
+	if len(cfg.Chaos) > 0 && cfg.Environment != Production {
+		mw = append(mw, mid.Chaos(cfg.Log, cfg.Chaos))
+	}
+
+	if cfg.Features != nil {
+		mw = append(mw, mid.ReadOnly(func() bool {
+			return cfg.Features(FeatureReadOnly)
+		}))
+	}
+
+	if cfg.KeyStore != nil {
+		mw = append(mw, mid.Authenticate(cfg.KeyStore, cfg.Issuer))
+	}
+
+	if len(cfg.Business) > 0 {
+		mw = append(mw, mid.Business(cfg.Business...))
+	}
+
+	app := web.NewApp(cfg.Shutdown, cfg.Tracer, mw...)
+
+	checkapp.Routes(app, checkapp.Config{
+		Build: cfg.Build,
+		Ready: cfg.Ready,
+	})
+
+	if cfg.KeyStore != nil {
+		authapp.Routes(app, authapp.Config{
+			KeyStore: cfg.KeyStore,
+		})
+	}
+
+	delegate := delegate.New(cfg.Log)
+	auditBus := auditbus.NewBusiness(cfg.Log, delegate, auditdb.NewStore(cfg.Log, cfg.DB))
+	customerStore := customercache.NewStore(cfg.Log, delegate, customerdb.NewStore(cfg.Log, cfg.DB, cfg.Encryption), time.Minute)
+	customerBus := customerbus.NewBusiness(cfg.Log, delegate, customerStore)
+	inventoryBus := inventorybus.NewBusiness(cfg.Log, inventorydb.NewStore(cfg.Log, cfg.DB), cfg.Inventory.HoldFor)
+	orderBus := orderbus.NewBusiness(cfg.Log, delegate, sqldb.NewTran(cfg.DB), customerBus, inventoryBus, cfg.Pricing, orderdb.NewStore(cfg.Log, cfg.DB))
+	vorderBus := vorderbus.NewBusiness(cfg.Log, vorderdb.NewStore(cfg.Log, cfg.DB, cfg.Encryption))
+
+	if cfg.Scheduler != nil {
+		sweep := func(ctx context.Context) error {
+			return inventoryBus.ReleaseExpired(ctx)
+		}
+
+		// Only one replica sweeps at a time so they don't contend for the
+		// same reservation rows.
+		if cfg.Locker != nil {
+			sweep = locker.Singleton(cfg.Locker, "inventory-release-expired", sweep)
+		}
+
+		cfg.Scheduler.Add(scheduler.Job{
+			Name:     "inventory-release-expired",
+			Interval: cfg.Inventory.SweepInterval,
+			Fn:       sweep,
+		})
+
+		archive := func(ctx context.Context) error {
+			return customerBus.ArchiveDeleted(ctx, cfg.Customer.DeletedRetention)
+		}
+
+		if cfg.Locker != nil {
+			archive = locker.Singleton(cfg.Locker, "customer-archive-deleted", archive)
+		}
+
+		cfg.Scheduler.Add(scheduler.Job{
+			Name:     "customer-archive-deleted",
+			Interval: cfg.Customer.ArchiveInterval,
+			Fn:       archive,
+		})
+
+		if cfg.Encryption != nil {
+			reencrypt := func(ctx context.Context) error {
+				return customerBus.Reencrypt(ctx)
+			}
+
+			if cfg.Locker != nil {
+				reencrypt = locker.Singleton(cfg.Locker, "customer-reencrypt", reencrypt)
+			}
+
+			cfg.Scheduler.Add(scheduler.Job{
+				Name:     "customer-reencrypt",
+				Interval: cfg.Customer.ReencryptInterval,
+				Fn:       reencrypt,
+			})
+		}
+
+		retain := func(ctx context.Context) error {
+			return auditBus.Retain(ctx, auditbus.RetentionConfig{
+				ArchiveAfter: cfg.Audit.ArchiveAfter,
+				PurgeAfter:   cfg.Audit.PurgeAfter,
+			})
+		}
+
+		if cfg.Locker != nil {
+			retain = locker.Singleton(cfg.Locker, "audit-retention", retain)
+		}
+
+		cfg.Scheduler.Add(scheduler.Job{
+			Name:     "audit-retention",
+			Interval: cfg.Audit.RetentionInterval,
+			Fn:       retain,
+		})
+	}
+
+	customerapp.Routes(app, customerapp.Config{
+		CustomerBus: customerBus,
+	})
+
+	inventoryapp.Routes(app, inventoryapp.Config{
+		InventoryBus: inventoryBus,
+	})
+
+	orderapp.Routes(app, orderapp.Config{
+		OrderBus: orderBus,
+	})
+
+	vorderapp.Routes(app, vorderapp.Config{
+		VOrderBus: vorderBus,
+	})
+
+	auditapp.Routes(app, auditapp.Config{
+		AuditBus: auditBus,
+	})
+
+	docsapp.Routes(app, docsapp.Config{
+		Build: cfg.Build,
+		Title: "Sales API",
+	})
+
+	return app
+}
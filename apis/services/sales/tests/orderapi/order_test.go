@@ -0,0 +1,134 @@
+package orderapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/AlmirSai/service/apis/services/sales/apptest"
+	"github.com/AlmirSai/service/app/domain/orderapp"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Orders from the seed data.
+const (
+	adminOrderID    = "a2b0639f-2cc6-44b8-b97b-15d69dbb511e"
+	adminCustomerID = "3c1f3a55-8a0c-4dd4-9b57-9f1cc1bb2f01"
+)
+
+func Test_Order(t *testing.T) {
+	t.Parallel()
+
+	at := apptest.New(t, "Test_Order", apptest.Config{})
+
+	at.Run(t, queryByID(at), "query-by-id")
+	at.Run(t, create(at), "create")
+}
+
+func queryByID(at *apptest.Test) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "seeded",
+			URL:        "/v1/orders/" + adminOrderID,
+			Token:      at.Admin.Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &orderapp.Order{},
+			ExpResp: &orderapp.Order{
+				ID:              adminOrderID,
+				UserID:          at.Admin.ID.String(),
+				CustomerID:      adminCustomerID,
+				Currency:        "USD",
+				Subtotal:        10000,
+				Tax:             700,
+				Total:           10700,
+				TaxJurisdiction: "US-FL",
+				DateCreated:     "2019-03-24T00:00:00Z",
+				DateUpdated:     "2019-03-24T00:00:00Z",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp, cmpopts.IgnoreFields(orderapp.Order{}, "Status", "Items"))
+			},
+		},
+		{
+			Name:       "not-found",
+			URL:        "/v1/orders/00000000-0000-0000-0000-000000000001",
+			Token:      at.Admin.Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+		},
+		{
+			Name:       "bad-id",
+			URL:        "/v1/orders/not-a-uuid",
+			Token:      at.Admin.Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+		},
+	}
+
+	return table
+}
+
+func create(at *apptest.Test) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/orders",
+			Token:      at.Admin.Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &orderapp.NewOrder{
+				CustomerID: adminCustomerID,
+				Currency:   "USD",
+				Items: []orderapp.NewItem{
+					{SKU: "COMIC-001", Name: "Comic Books", Quantity: 1, UnitPrice: 5000},
+				},
+			},
+			GotResp: &orderapp.Order{},
+			ExpResp: &orderapp.Order{
+				UserID:     at.Admin.ID.String(),
+				CustomerID: adminCustomerID,
+				Currency:   "USD",
+				Subtotal:   5000,
+				Total:      5000,
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp, cmpopts.IgnoreFields(orderapp.Order{}, "ID", "Status", "Items", "DateCreated", "DateUpdated"))
+			},
+		},
+		{
+			Name:       "missing-items",
+			URL:        "/v1/orders",
+			Token:      at.Admin.Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &orderapp.NewOrder{
+				CustomerID: adminCustomerID,
+				Currency:   "USD",
+			},
+		},
+		{
+			Name:       "no-token",
+			URL:        "/v1/orders",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: &orderapp.NewOrder{
+				CustomerID: adminCustomerID,
+				Currency:   "USD",
+			},
+		},
+		{
+			Name:       "user-role",
+			URL:        "/v1/orders",
+			Token:      at.User.Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			Input: &orderapp.NewOrder{
+				CustomerID: adminCustomerID,
+				Currency:   "USD",
+			},
+		},
+	}
+
+	return table
+}
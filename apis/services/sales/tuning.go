@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/AlmirSai/service/apis/services/sales/config"
+	"github.com/AlmirSai/service/foundation/cgroup"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// tuneRuntime sets GOMAXPROCS and GOMEMLIMIT from the container cgroup limits,
+// unless explicit overrides are provided in the configuration. The effective
+// values are logged so throttling or OOM kills can be traced back to config.
+func tuneRuntime(ctx context.Context, log *logger.Logger, cfg config.Runtime) {
+	procsSource := "default"
+	memSource := "default"
+
+	switch {
+	case cfg.MaxProcs > 0:
+		runtime.GOMAXPROCS(cfg.MaxProcs)
+		procsSource = "config"
+
+	case !cfg.DisableCgroups:
+		quota, ok, err := cgroup.CPUQuota()
+		if err != nil {
+			log.Warn(ctx, "tuning", "status", "reading cpu quota", "error", err)
+			break
+		}
+
+		if ok {
+			// Round up so a 1.5 CPU quota still gets two Ps.
+			runtime.GOMAXPROCS(max(1, int(math.Ceil(quota))))
+			procsSource = "cgroup"
+		}
+	}
+
+	switch {
+	case cfg.MemLimit > 0:
+		debug.SetMemoryLimit(cfg.MemLimit)
+		memSource = "config"
+
+	case !cfg.DisableCgroups:
+		limit, ok, err := cgroup.MemoryLimit()
+		if err != nil {
+			log.Warn(ctx, "tuning", "status", "reading memory limit", "error", err)
+			break
+		}
+
+		if ok {
+			// Leave headroom below the hard limit for non-heap memory.
+			debug.SetMemoryLimit(int64(float64(limit) * cfg.MemLimitRatio))
+			memSource = "cgroup"
+		}
+	}
+
+	log.Info(ctx, "tuning",
+		"GOMAXPROCS", runtime.GOMAXPROCS(0), "GOMAXPROCS_source", procsSource,
+		"GOMEMLIMIT", debug.SetMemoryLimit(-1), "GOMEMLIMIT_source", memSource,
+	)
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlmirSai/service/foundation/logger/audit"
+)
+
+// AuditVerify checks the hash chain of an audit log written by the sales
+// service and prints the last record it covers. It fails on the first record
+// that was edited, removed, or moved.
+func AuditVerify(path string) error {
+	if path == "" {
+		return errors.New("missing file, usage: audit-verify <file>")
+	}
+
+	last, err := audit.VerifyFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("chain intact: %d records, last hash %s\n", last.Seq, last.Hash)
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CallConfig holds the settings of a request issued by the call command.
+type CallConfig struct {
+	Host       string
+	UserID     string
+	KeysFolder string
+	DB         sqldb.Config
+	Token      TokenConfig
+}
+
+// Call issues a request to the service as the configured user and pretty
+// prints the JSON response. The token is minted on first use and cached
+// until it's about to expire, and every request carries a fresh trace id so
+// it can be found in the service logs. A body of "-" is read from stdin.
+func Call(ctx context.Context, log *logger.Logger, cfg CallConfig, method string, path string, body string) error {
+	if method == "" || path == "" {
+		return errors.New("missing method or path, usage: call <method> <path> [body|-]")
+	}
+
+	token, err := cachedToken(ctx, log, cfg)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader
+	switch body {
+	case "":
+	case "-":
+		r = os.Stdin
+	default:
+		r = strings.NewReader(body)
+	}
+
+	url := strings.TrimSuffix(cfg.Host, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, r)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	traceID, parent := traceParent()
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("traceparent", parent)
+	if r != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("issuing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	fmt.Printf("%s %s: %s (%s)\n", req.Method, url, resp.Status, time.Since(start).Round(time.Millisecond))
+	fmt.Println("trace id:", traceID)
+
+	if len(data) > 0 {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, data, "", "  "); err != nil {
+			pretty.Reset()
+			pretty.Write(data)
+		}
+		fmt.Println(pretty.String())
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("request failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// cachedToken returns the cached token of the user when it was minted with
+// the same settings and is valid for at least another minute, otherwise it
+// mints and caches a new one.
+func cachedToken(ctx context.Context, log *logger.Logger, cfg CallConfig) (string, error) {
+	var file string
+	if dir, err := os.UserCacheDir(); err == nil {
+		file = filepath.Join(dir, "sales-admin", cfg.UserID+".jwt")
+	}
+
+	if file != "" {
+		if data, err := os.ReadFile(file); err == nil {
+			token := strings.TrimSpace(string(data))
+			if reusable(token, cfg) {
+				return token, nil
+			}
+		}
+	}
+
+	token, _, _, err := mintToken(ctx, log, cfg.DB, cfg.KeysFolder, cfg.UserID, "", cfg.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Failing to cache only costs a token per call.
+	if file != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err == nil {
+			os.WriteFile(file, []byte(token), 0600)
+		}
+	}
+
+	return token, nil
+}
+
+// reusable reports whether the cached token matches the settings and has
+// time left. The signature is checked by the service.
+func reusable(token string, cfg CallConfig) bool {
+	var c claims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &c); err != nil {
+		return false
+	}
+
+	switch {
+	case c.Subject != cfg.UserID, c.Issuer != cfg.Token.Issuer:
+		return false
+	case c.ExpiresAt == nil || time.Until(c.ExpiresAt.Time) < time.Minute:
+		return false
+	}
+
+	return true
+}
+
+// traceParent returns a new trace id and the W3C traceparent header that
+// starts the trace with it.
+func traceParent() (string, string) {
+	var ids [24]byte
+	rand.Read(ids[:])
+
+	traceID := hex.EncodeToString(ids[:16])
+	spanID := hex.EncodeToString(ids[16:])
+
+	return traceID, fmt.Sprintf("00-%s-%s-01", traceID, spanID)
+}
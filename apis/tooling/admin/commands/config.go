@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	salesconfig "github.com/AlmirSai/service/apis/services/sales/config"
+	"github.com/ardanlabs/conf/v3"
+)
+
+// ShowConfig resolves the configuration of the service from the environment
+// the same way the service does and prints every setting with where its
+// value came from. Masked settings stay masked. Only the environment is
+// read, the flags on this command line belong to the admin tool.
+func ShowConfig(service string) error {
+	var resolved, defaults any
+	var prefix string
+	var reloadFile func() string
+
+	switch service {
+	case "", "sales":
+		r, d := salesconfig.New(""), salesconfig.New("")
+		resolved, defaults, prefix = &r, &d, salesconfig.Prefix
+		reloadFile = func() string { return r.Reload.File }
+
+	default:
+		return fmt.Errorf("unknown service %q, expected sales", service)
+	}
+
+	args := os.Args
+	os.Args = os.Args[:1]
+	defer func() { os.Args = args }()
+
+	if _, err := conf.Parse(prefix, resolved); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	// The defaults are what the service resolves with none of its variables
+	// set, so they are hidden for the second parse.
+	overridden := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, _ := strings.Cut(kv, "="); strings.HasPrefix(key, prefix+"_") {
+			overridden[key] = value
+			os.Unsetenv(key)
+		}
+	}
+
+	_, err := conf.Parse(prefix, defaults)
+
+	for key, value := range overridden {
+		os.Setenv(key, value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("parsing defaults: %w", err)
+	}
+
+	values, err := settings(resolved)
+	if err != nil {
+		return err
+	}
+
+	defaultList, err := settings(defaults)
+	if err != nil {
+		return err
+	}
+
+	defaultValues := make(map[string]string, len(defaultList))
+	for _, s := range defaultList {
+		defaultValues[s.flag] = s.value
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE\tDEFAULT")
+
+	for _, s := range values {
+		env := prefix + "_" + strings.ToUpper(strings.ReplaceAll(s.flag, "-", "_"))
+
+		source, def := "default", "-"
+		if _, ok := overridden[env]; ok {
+			source, def = "env "+env, defaultValues[s.flag]
+			if def == "" {
+				def = "(empty)"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.flag, s.value, source, def)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if file := reloadFile(); file != "" {
+		fmt.Printf("\nthe dynamic settings can be changed at runtime by %s\n", file)
+	}
+
+	return nil
+}
+
+// setting is a single resolved setting, named after its flag.
+type setting struct {
+	flag  string
+	value string
+}
+
+// settings lists the settings of the parsed configuration in the order conf
+// prints them, with the masked values already masked.
+func settings(cfg any) ([]setting, error) {
+	out, err := conf.String(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("printing config: %w", err)
+	}
+
+	var list []setting
+	for line := range strings.Lines(out) {
+		flag, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		list = append(list, setting{
+			flag:  strings.TrimPrefix(flag, "--"),
+			value: value,
+		})
+	}
+
+	return list, nil
+}
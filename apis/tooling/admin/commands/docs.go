@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlmirSai/service/apis/services/sales/mux"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/openapi"
+	"go.yaml.in/yaml/v3"
+)
+
+// Docs writes the OpenAPI document of the sales service in json or yaml to
+// the file, docs/openapi.<format> when empty, or to stdout when file is "-".
+// The routes are registered on an app that's never served, so no database
+// or keys are needed. Only the route table is read.
+func Docs(build string, format string, file string) error {
+	log := logger.NewNop()
+
+	app := mux.WebAPI(mux.Config{
+		Build:    build,
+		Log:      log,
+		Pricing:  pricing.New(nil),
+		KeyStore: keystore.New(),
+	})
+
+	doc := openapi.Build(openapi.Info{
+		Title:   "Sales API",
+		Version: build,
+	}, app.Routes())
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+
+	if format == "" {
+		format = "json"
+	}
+
+	switch format {
+	case "json":
+		data = append(data, '\n')
+
+	case "yaml":
+		if data, err = jsonToYAML(data); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown format %q, expected json or yaml", format)
+	}
+
+	if file == "" {
+		file = "docs/openapi." + format
+	}
+
+	if file == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("creating folder: %w", err)
+	}
+
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("writing document: %w", err)
+	}
+
+	fmt.Printf("wrote %d paths to %s\n", len(doc.Paths), file)
+
+	return nil
+}
+
+// jsonToYAML converts the document to block style yaml. JSON is valid yaml,
+// so decoding it into a node keeps the order of the keys and only the style
+// needs resetting.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+
+	var reset func(n *yaml.Node)
+	reset = func(n *yaml.Node) {
+		n.Style = 0
+		for _, c := range n.Content {
+			reset(c)
+		}
+	}
+	reset(&node)
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&node); err != nil {
+		return nil, fmt.Errorf("encoding yaml: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding yaml: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlmirSai/service/foundation/keystore"
+)
+
+// GenKey creates a private key with the algorithm, rsa or ecdsa, and writes
+// it with its public key to the folder. The files are named after the kid,
+// the RFC 7638 thumbprint of the public key: kid.pem holds the private key
+// the service loads and kid.pub the public key. The sales service only signs
+// with RSA keys. When printJWK is set the public JWK is printed as well.
+func GenKey(folder string, algorithm string, printJWK bool) error {
+	var private crypto.Signer
+	var err error
+
+	switch algorithm {
+	case "", "rsa":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ecdsa":
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return fmt.Errorf("unknown algorithm %q, expected rsa or ecdsa", algorithm)
+	}
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	jwk, err := keystore.NewJWK("", private.Public())
+	if err != nil {
+		return err
+	}
+	jwk.KeyID = jwk.Thumbprint()
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return fmt.Errorf("marshaling private key: %w", err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return fmt.Errorf("marshaling public key: %w", err)
+	}
+
+	if err := os.MkdirAll(folder, 0o700); err != nil {
+		return fmt.Errorf("creating folder: %w", err)
+	}
+
+	privateFile := filepath.Join(folder, jwk.KeyID+".pem")
+	if err := writePEM(privateFile, "PRIVATE KEY", privateDER, 0o600); err != nil {
+		return err
+	}
+
+	publicFile := filepath.Join(folder, jwk.KeyID+".pub")
+	if err := writePEM(publicFile, "PUBLIC KEY", publicDER, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Println("kid:        ", jwk.KeyID)
+	fmt.Println("private key:", privateFile)
+	fmt.Println("public key: ", publicFile)
+
+	if printJWK {
+		data, err := json.MarshalIndent(jwk, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling jwk: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+
+	return nil
+}
+
+// writePEM writes a single PEM block to a new file. An existing file is never
+// overwritten.
+func writePEM(name string, blockType string, der []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+
+	return file.Close()
+}
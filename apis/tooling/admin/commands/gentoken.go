@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/userbus"
+	"github.com/AlmirSai/service/business/domain/userbus/stores/userdb"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenConfig holds the settings of a minted token.
+type TokenConfig struct {
+	Issuer string
+	Expiry time.Duration
+}
+
+// claims are the claims of a token minted for local testing.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// GenToken mints a token carrying the roles of the user, signed with the key
+// of the kid from the keys folder, or with the active key when kid is empty.
+// With curl set the token is printed as a ready to paste curl Authorization
+// header.
+func GenToken(ctx context.Context, log *logger.Logger, dbCfg sqldb.Config, folder string, userID string, kid string, cfg TokenConfig, curl bool) error {
+	signed, kid, expires, err := mintToken(ctx, log, dbCfg, folder, userID, kid, cfg)
+	if err != nil {
+		return err
+	}
+
+	if curl {
+		fmt.Printf("-H \"Authorization: Bearer %s\"\n", signed)
+		return nil
+	}
+
+	fmt.Printf("-----BEGIN TOKEN-----\n%s\n-----END TOKEN-----\n", signed)
+	fmt.Println("kid:    ", kid)
+	fmt.Println("expires:", expires.Format(time.RFC3339))
+
+	return nil
+}
+
+// mintToken looks the user up and signs a token with its roles. It returns
+// the token with the kid that signed it and its expiry.
+func mintToken(ctx context.Context, log *logger.Logger, dbCfg sqldb.Config, folder string, userID string, kid string, cfg TokenConfig) (string, string, time.Time, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("parsing user id: %w", err)
+	}
+
+	db, err := sqldb.Open(dbCfg)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	userBus := userbus.NewBusiness(log, userdb.NewStore(log, db))
+
+	usr, err := userBus.QueryByID(ctx, id)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("query user: %w", err)
+	}
+
+	if !usr.Enabled {
+		return "", "", time.Time{}, fmt.Errorf("user %s is disabled", usr.ID)
+	}
+
+	ks := keystore.New()
+	if _, err := ks.LoadKeys(os.DirFS(folder)); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("reading keys: %w", err)
+	}
+
+	if kid == "" {
+		kid = ks.ActiveKID()
+	}
+
+	privatePEM, err := ks.PrivateKey(kid)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("kid[%s]: %w", kid, err)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privatePEM))
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	now := time.Now()
+	expires := now.Add(cfg.Expiry)
+
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   usr.ID.String(),
+			Issuer:    cfg.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now.UTC()),
+			ExpiresAt: jwt.NewNumericDate(expires.UTC()),
+		},
+		Roles: userbus.RolesToStrings(usr.Roles),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(privateKey)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("signing token: %w", err)
+	}
+
+	return signed, kid, expires, nil
+}
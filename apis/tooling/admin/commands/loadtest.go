@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// LoadConfig holds the settings of a load test.
+type LoadConfig struct {
+	Call        CallConfig
+	Endpoints   []string
+	Rate        int
+	Concurrency int
+	Duration    time.Duration
+	NoAuth      bool
+	DebugHost   string
+	TailEvery   time.Duration
+}
+
+// LoadTest fires the endpoint mix at the service at the configured rate for
+// the duration and reports the latency percentiles and error rates of every
+// endpoint. An endpoint is "METHOD PATH" with an optional "N*" weight prefix,
+// so "3*GET /v1/orders" is picked three times as often as an unweighted one.
+// Requests that can't start because every worker is busy are counted as
+// dropped rather than queued, so the rate reported is the rate achieved.
+// With a debug host the service's expvar metrics are tailed during the run.
+func LoadTest(ctx context.Context, log *logger.Logger, cfg LoadConfig) error {
+	mix, err := parseMix(cfg.Endpoints)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Rate <= 0 || cfg.Concurrency <= 0 {
+		return errors.New("rate and concurrency must be positive")
+	}
+
+	var token string
+	if !cfg.NoAuth {
+		if token, err = cachedToken(ctx, log, cfg.Call); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	fmt.Printf("load test: %d req/s, %d workers, %s, %d endpoints\n", cfg.Rate, cfg.Concurrency, cfg.Duration, len(mix))
+
+	if cfg.DebugHost != "" {
+		go tailExpvar(ctx, cfg.DebugHost, cfg.TailEvery)
+	}
+
+	client := http.Client{
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: cfg.Concurrency,
+		},
+	}
+
+	work := make(chan endpoint)
+	results := make(chan result, cfg.Concurrency)
+
+	var wg sync.WaitGroup
+	for range cfg.Concurrency {
+		wg.Go(func() {
+			for ep := range work {
+				results <- ep.fire(ctx, &client, cfg.Call.Host, token)
+			}
+		})
+	}
+
+	stats := make(map[string]*endpointStats)
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for r := range results {
+			if r.aborted {
+				continue
+			}
+
+			s, ok := stats[r.name]
+			if !ok {
+				s = &endpointStats{}
+				stats[r.name] = s
+			}
+			s.add(r)
+		}
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+
+	var sent, dropped int
+loop:
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+		}
+
+		select {
+		case work <- mix[i%len(mix)]:
+			sent++
+		default:
+			dropped++
+		}
+	}
+
+	close(work)
+	wg.Wait()
+	close(results)
+	<-collected
+
+	elapsed := time.Since(start)
+
+	fmt.Printf("\nsent %d requests in %s (%.1f req/s), dropped %d\n\n", sent, elapsed.Round(time.Millisecond), float64(sent)/elapsed.Seconds(), dropped)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENDPOINT\tCOUNT\tERRORS\tERROR %\tP50\tP90\tP99\tMAX")
+
+	for _, name := range slices.Sorted(maps.Keys(stats)) {
+		s := stats[name]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\n", name, len(s.latencies), s.errors, s.errorRate(),
+			s.percentile(50), s.percentile(90), s.percentile(99), s.percentile(100))
+	}
+
+	return w.Flush()
+}
+
+// =============================================================================
+
+// endpoint is a request of the load test mix.
+type endpoint struct {
+	method string
+	path   string
+}
+
+func (ep endpoint) String() string {
+	return ep.method + " " + ep.path
+}
+
+// result is the outcome of a single request.
+type result struct {
+	name    string
+	latency time.Duration
+	failed  bool
+	aborted bool
+}
+
+// fire issues the request and reports how long it took. Transport errors and
+// responses with a 4xx or 5xx status are failures. Requests cut short by the
+// end of the run aren't counted.
+func (ep endpoint) fire(ctx context.Context, client *http.Client, host string, token string) result {
+	r := result{
+		name: ep.String(),
+	}
+
+	url := strings.TrimSuffix(host, "/") + "/" + strings.TrimPrefix(ep.path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, ep.method, url, nil)
+	if err != nil {
+		r.failed = true
+		return r
+	}
+
+	_, parent := traceParent()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("traceparent", parent)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		r.latency = time.Since(start)
+		r.failed = true
+		r.aborted = ctx.Err() != nil
+		return r
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	r.latency = time.Since(start)
+	r.failed = resp.StatusCode >= http.StatusBadRequest
+
+	return r
+}
+
+// parseMix expands the weighted endpoints into the round robin mix.
+func parseMix(endpoints []string) ([]endpoint, error) {
+	var mix []endpoint
+
+	for _, e := range endpoints {
+		weight := 1
+		if w, rest, ok := strings.Cut(e, "*"); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(w))
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("endpoint %q: invalid weight", e)
+			}
+			weight, e = n, rest
+		}
+
+		method, path, ok := strings.Cut(strings.TrimSpace(e), " ")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("endpoint %q: expected METHOD PATH", e)
+		}
+
+		ep := endpoint{
+			method: strings.ToUpper(method),
+			path:   strings.TrimSpace(path),
+		}
+
+		for range weight {
+			mix = append(mix, ep)
+		}
+	}
+
+	if len(mix) == 0 {
+		return nil, errors.New("no endpoints to load")
+	}
+
+	// Interleave the endpoints so a weighted one isn't fired in bursts.
+	interleaved := make([]endpoint, 0, len(mix))
+	for len(mix) > 0 {
+		var rest []endpoint
+		seen := make(map[endpoint]bool)
+		for _, ep := range mix {
+			if seen[ep] {
+				rest = append(rest, ep)
+				continue
+			}
+			seen[ep] = true
+			interleaved = append(interleaved, ep)
+		}
+		mix = rest
+	}
+
+	return interleaved, nil
+}
+
+// endpointStats accumulates the results of an endpoint.
+type endpointStats struct {
+	latencies []time.Duration
+	errors    int
+	sorted    bool
+}
+
+func (s *endpointStats) add(r result) {
+	s.latencies = append(s.latencies, r.latency)
+	if r.failed {
+		s.errors++
+	}
+	s.sorted = false
+}
+
+func (s *endpointStats) errorRate() float64 {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	return 100 * float64(s.errors) / float64(len(s.latencies))
+}
+
+// percentile returns the latency under which p percent of the requests
+// completed, using the nearest rank.
+func (s *endpointStats) percentile(p int) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+
+	if !s.sorted {
+		slices.Sort(s.latencies)
+		s.sorted = true
+	}
+
+	rank := (p*len(s.latencies)+99)/100 - 1
+	rank = max(0, min(rank, len(s.latencies)-1))
+
+	return s.latencies[rank].Round(time.Microsecond)
+}
+
+// tailExpvar prints the memory and connection pool figures of the service's
+// expvar page until the context is done.
+func tailExpvar(ctx context.Context, host string, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	url := strings.TrimSuffix(host, "/") + "/metrics"
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		vars, err := readExpvar(ctx, url)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Println("expvar:", err)
+			}
+			continue
+		}
+
+		fmt.Printf("expvar: heap %.1fMB, gc %d, db open %v, in use %v, waits %v\n",
+			float64(vars.Memstats.HeapAlloc)/(1<<20), vars.Memstats.NumGC,
+			vars.Metrics["db_open_connections"], vars.Metrics["db_in_use_connections"], vars.Metrics["db_wait_count_total"])
+	}
+}
+
+// expvars is the part of the expvar page the load test reports.
+type expvars struct {
+	Memstats struct {
+		HeapAlloc uint64
+		NumGC     uint32
+	} `json:"memstats"`
+	Metrics map[string]any `json:"metrics"`
+}
+
+func readExpvar(ctx context.Context, url string) (expvars, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return expvars{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return expvars{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return expvars{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var vars expvars
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		return expvars{}, fmt.Errorf("decoding: %w", err)
+	}
+
+	return vars, nil
+}
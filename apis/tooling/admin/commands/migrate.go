@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/AlmirSai/service/business/sdk/migrate"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrate applies the pending migrations and prints the resulting schema
+// version.
+func Migrate(ctx context.Context, cfg sqldb.Config) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate.Migrate(ctx, db); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	fmt.Println("migrations complete")
+
+	return printVersion(ctx, db)
+}
+
+// Seed applies the pending migrations, loads the seed data and prints the
+// resulting schema version.
+func Seed(ctx context.Context, cfg sqldb.Config) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate.Migrate(ctx, db); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	if err := migrate.Seed(ctx, db); err != nil {
+		return fmt.Errorf("seed database: %w", err)
+	}
+
+	fmt.Println("seed data complete")
+
+	return printVersion(ctx, db)
+}
+
+// MigrateStatus prints every migration and whether it has been applied.
+func MigrateStatus(ctx context.Context, cfg sqldb.Config) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	statuses, err := migrate.Status(ctx, db)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATUS\tAPPLIED AT\tDESCRIPTION")
+
+	for _, s := range statuses {
+		status, appliedAt := "pending", "-"
+		if s.Applied {
+			status, appliedAt = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, status, appliedAt, s.Description)
+	}
+
+	return w.Flush()
+}
+
+// MigrateDryRun prints the SQL of the pending migrations without running it.
+func MigrateDryRun(ctx context.Context, cfg sqldb.Config) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate.DryRun(ctx, db, os.Stdout); err != nil {
+		return fmt.Errorf("dry run: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateDown reverts the last applied migration. It's refused when the
+// environment is production.
+func MigrateDown(ctx context.Context, cfg sqldb.Config, environment string) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	m, err := migrate.Down(ctx, db, environment)
+	if err != nil {
+		return fmt.Errorf("down: %w", err)
+	}
+
+	fmt.Printf("reverted migration %s: %s\n", m.Version, m.Description)
+
+	return nil
+}
+
+// printVersion prints the last applied migration as the schema version.
+func printVersion(ctx context.Context, db *sqlx.DB) error {
+	statuses, err := migrate.Status(ctx, db)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	for _, s := range slices.Backward(statuses) {
+		if s.Applied {
+			fmt.Printf("schema version %s: %s\n", s.Version, s.Description)
+			return nil
+		}
+	}
+
+	fmt.Println("schema version: none applied")
+
+	return nil
+}
@@ -0,0 +1,540 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed scaffold
+var scaffoldFS embed.FS
+
+// migrationFile is the file the migration of a scaffolded domain is appended
+// to.
+const migrationFile = "business/sdk/migrate/sql/migrate.sql"
+
+// domainName matches the names accepted for a domain, which become package
+// names.
+var domainName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// Scaffold generates the skeleton of a new domain from the root of the
+// module: the business package with its Storer, the postgres store, the app
+// layer handlers and routes, their tests, and a migration appended to the
+// embedded migrations. Fields are a comma separated list of name:type pairs like
+// "name:string,price:int64,available:bool", where the type is one of
+// string, int, int64, float64, bool, time or uuid. Existing packages are
+// never overwritten. The domain still has to be wired into the mux.
+func Scaffold(name string, fields string) error {
+	if !domainName.MatchString(name) {
+		return fmt.Errorf("domain name %q must be lower case letters and digits, usage: scaffold <name> <field:type,...>", name)
+	}
+
+	module, err := moduleName()
+	if err != nil {
+		return err
+	}
+
+	d, err := newDomain(module, name, fields)
+	if err != nil {
+		return err
+	}
+
+	targets := map[string]string{
+		"bus": filepath.Join("business", "domain", name+"bus"),
+		"db":  filepath.Join("business", "domain", name+"bus", "stores", name+"db"),
+		"app": filepath.Join("app", "domain", name+"app"),
+		"api": filepath.Join("apis", "services", "sales", "tests", name+"api"),
+	}
+
+	for _, dir := range targets {
+		if _, err := os.Stat(dir); err == nil {
+			return fmt.Errorf("%s already exists", dir)
+		}
+	}
+
+	funcs := template.FuncMap{
+		"padColumn": pad(d.columnWidth()),
+		"padType":   pad(d.typeWidth()),
+	}
+
+	// Everything is rendered before anything is written so a template error
+	// doesn't leave half a domain behind.
+	files := make(map[string][]byte)
+
+	for layer, dir := range targets {
+		// The layers share file names, so each is parsed into its own set.
+		tmpls, err := template.New("").Funcs(funcs).ParseFS(scaffoldFS, path.Join("scaffold", layer, "*.tmpl"))
+		if err != nil {
+			return fmt.Errorf("parsing templates: %w", err)
+		}
+
+		entries, err := fs.ReadDir(scaffoldFS, path.Join("scaffold", layer))
+		if err != nil {
+			return fmt.Errorf("reading templates: %w", err)
+		}
+
+		for _, e := range entries {
+			var b bytes.Buffer
+			if err := tmpls.ExecuteTemplate(&b, e.Name(), d); err != nil {
+				return fmt.Errorf("rendering %s/%s: %w", layer, e.Name(), err)
+			}
+
+			src, err := format.Source(b.Bytes())
+			if err != nil {
+				return fmt.Errorf("formatting %s/%s: %w", layer, e.Name(), err)
+			}
+
+			file := strings.TrimSuffix(e.Name(), ".tmpl")
+			switch file {
+			case "bus.go", "db.go", "app.go", "bus_test.go", "api_test.go":
+				file = name + file
+			}
+
+			files[filepath.Join(dir, file)] = src
+		}
+	}
+
+	tmpl, err := template.New("migration.sql.tmpl").Funcs(funcs).ParseFS(scaffoldFS, "scaffold/migration.sql.tmpl")
+	if err != nil {
+		return fmt.Errorf("parsing templates: %w", err)
+	}
+
+	var migration bytes.Buffer
+	if err := tmpl.Execute(&migration, d); err != nil {
+		return fmt.Errorf("rendering migration: %w", err)
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("creating folder: %w", err)
+		}
+
+		if err := os.WriteFile(file, files[file], 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", file, err)
+		}
+
+		fmt.Println("created", file)
+	}
+
+	f, err := os.OpenFile(migrationFile, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("opening migrations: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(migration.Bytes()); err != nil {
+		return fmt.Errorf("appending migration: %w", err)
+	}
+
+	fmt.Printf("appended migration %s to %s\n", d.Version, migrationFile)
+
+	fmt.Println()
+	fmt.Println("next steps:")
+	fmt.Printf("  go generate ./business/domain/%sbus\n", name)
+	fmt.Printf("  construct %sbus.NewBusiness with %sdb.NewStore and call %sapp.Routes in apis/services/sales/mux\n", name, name, name)
+	fmt.Printf("  add the %s business to dbtest.BusDomain\n", name)
+	fmt.Printf("  go test ./business/domain/%sbus ./apis/services/sales/tests/%sapi\n", name, name)
+
+	return nil
+}
+
+// =============================================================================
+
+// domain is the data the scaffold templates are rendered with.
+type domain struct {
+	Module     string
+	Name       string
+	Type       string
+	PluralType string
+	NewType    string
+	UpdateType string
+	DBType     string
+	Var        string
+	Plural     string
+	Table      string
+	IDColumn   string
+	IDVar      string
+	Version    string
+	Fields     []field
+}
+
+func newDomain(module string, name string, fields string) (domain, error) {
+	typ := strings.ToUpper(name[:1]) + name[1:]
+	plural := pluralize(name)
+
+	d := domain{
+		Module:     module,
+		Name:       name,
+		Type:       typ,
+		PluralType: strings.ToUpper(plural[:1]) + plural[1:],
+		NewType:    "New" + typ,
+		UpdateType: "Update" + typ,
+		DBType:     "db" + typ,
+		Var:        shortName(name),
+		Plural:     plural,
+		Table:      plural,
+		IDColumn:   name + "_id",
+		IDVar:      name + "ID",
+	}
+
+	seen := map[string]bool{
+		"id":           true,
+		"date_created": true,
+		"date_updated": true,
+		d.IDColumn:     true,
+	}
+
+	for spec := range strings.SplitSeq(fields, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		fname, kind, ok := strings.Cut(spec, ":")
+		if !ok {
+			return domain{}, fmt.Errorf("field %q: expected name:type", spec)
+		}
+
+		f, err := newField(fname, kind)
+		if err != nil {
+			return domain{}, err
+		}
+
+		if seen[f.Column] {
+			return domain{}, fmt.Errorf("field %q: duplicate or reserved", fname)
+		}
+		seen[f.Column] = true
+
+		d.Fields = append(d.Fields, f)
+	}
+
+	if len(d.Fields) == 0 {
+		return domain{}, errors.New("at least one field is required")
+	}
+
+	version, err := nextVersion()
+	if err != nil {
+		return domain{}, err
+	}
+	d.Version = version
+
+	return d, nil
+}
+
+// StringFields returns the fields the queries can be filtered on.
+func (d domain) StringFields() []field {
+	var fields []field
+	for _, f := range d.Fields {
+		if f.Kind == "string" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// HasUUID reports whether any field is a uuid.
+func (d domain) HasUUID() bool {
+	for _, f := range d.Fields {
+		if f.IsUUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTime reports whether any field is a time.
+func (d domain) HasTime() bool {
+	for _, f := range d.Fields {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRequired reports whether a new document can't be empty.
+func (d domain) HasRequired() bool {
+	for _, f := range d.Fields {
+		if f.NewValidate() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (d domain) columnWidth() int {
+	width := len("date_created")
+	for _, f := range append([]field{{Column: d.IDColumn}}, d.Fields...) {
+		width = max(width, len(f.Column))
+	}
+	return width
+}
+
+func (d domain) typeWidth() int {
+	width := len("TIMESTAMP")
+	for _, f := range d.Fields {
+		width = max(width, len(f.SQLType()))
+	}
+	return width
+}
+
+// field is a field of a scaffolded domain.
+type field struct {
+	GoName   string
+	Column   string
+	JSON     string
+	LocalVar string
+	Kind     string
+}
+
+// fieldName matches the names accepted for a field, in snake case.
+var fieldName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func newField(name string, kind string) (field, error) {
+	name = strings.TrimSpace(name)
+	kind = strings.TrimSpace(kind)
+
+	if !fieldName.MatchString(name) {
+		return field{}, fmt.Errorf("field %q: name must be snake case", name)
+	}
+
+	switch kind {
+	case "string", "int", "int64", "float64", "bool", "time", "uuid":
+	default:
+		return field{}, fmt.Errorf("field %q: unknown type %q", name, kind)
+	}
+
+	var goName strings.Builder
+	for part := range strings.SplitSeq(name, "_") {
+		switch part {
+		case "id", "url", "sku", "api":
+			goName.WriteString(strings.ToUpper(part))
+		default:
+			goName.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+
+	json := goName.String()
+	switch {
+	case strings.ToUpper(json) == json:
+		json = strings.ToLower(json)
+	default:
+		json = strings.ToLower(json[:1]) + json[1:]
+	}
+
+	return field{
+		GoName:   goName.String(),
+		Column:   name,
+		JSON:     json,
+		LocalVar: json,
+		Kind:     kind,
+	}, nil
+}
+
+// GoType returns the type of the field in the business and store layers.
+func (f field) GoType() string {
+	switch f.Kind {
+	case "time":
+		return "time.Time"
+	case "uuid":
+		return "uuid.UUID"
+	}
+	return f.Kind
+}
+
+// AppType returns the type of the field in the API documents, where times
+// and ids are carried as strings.
+func (f field) AppType() string {
+	switch f.Kind {
+	case "time", "uuid":
+		return "string"
+	}
+	return f.Kind
+}
+
+// SQLType returns the column type of the field.
+func (f field) SQLType() string {
+	switch f.Kind {
+	case "string":
+		return "TEXT"
+	case "int":
+		return "INT"
+	case "int64":
+		return "BIGINT"
+	case "float64":
+		return "DOUBLE PRECISION"
+	case "bool":
+		return "BOOLEAN"
+	case "time":
+		return "TIMESTAMP"
+	}
+	return "UUID"
+}
+
+// NewValidate returns the validate tag of the field when adding.
+func (f field) NewValidate() string {
+	switch f.Kind {
+	case "string", "time":
+		return "required"
+	case "uuid":
+		return "required,uuid"
+	}
+	return ""
+}
+
+// UpdateValidate returns the validate tag of the field when updating.
+func (f field) UpdateValidate() string {
+	switch f.Kind {
+	case "string":
+		return "omitempty,min=1"
+	case "uuid":
+		return "omitempty,uuid"
+	}
+	return ""
+}
+
+// IsTime reports whether the field is a time.
+func (f field) IsTime() bool {
+	return f.Kind == "time"
+}
+
+// IsUUID reports whether the field is a uuid.
+func (f field) IsUUID() bool {
+	return f.Kind == "uuid"
+}
+
+// TestValue returns a literal of the field's business type for the generated
+// tests. Every n gives a different value.
+func (f field) TestValue(n int) string {
+	switch f.Kind {
+	case "time":
+		return fmt.Sprintf("time.Date(2024, 1, %d, 0, 0, 0, 0, time.UTC)", n)
+	case "uuid":
+		return fmt.Sprintf("uuid.MustParse(%q)", testUUID(n))
+	}
+	return f.AppTestValue(n)
+}
+
+// AppTestValue returns the literal sent in API documents for TestValue(n).
+func (f field) AppTestValue(n int) string {
+	switch f.Kind {
+	case "string":
+		return strconv.Quote(fmt.Sprintf("%s %d", f.Column, n))
+	case "float64":
+		return fmt.Sprintf("%d.5", n)
+	case "bool":
+		return strconv.FormatBool(n%2 == 1)
+	case "time":
+		return strconv.Quote(fmt.Sprintf("2024-01-%02dT00:00:00Z", n))
+	case "uuid":
+		return strconv.Quote(testUUID(n))
+	}
+	return strconv.Itoa(n)
+}
+
+// AppExpValue returns an expression of the value API documents carry for
+// TestValue(n). Times are returned in the local time zone.
+func (f field) AppExpValue(n int) string {
+	if f.IsTime() {
+		return f.TestValue(n) + ".Local().Format(time.RFC3339)"
+	}
+	return f.AppTestValue(n)
+}
+
+// testUUID returns a fixed uuid for the generated tests.
+func testUUID(n int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+}
+
+// =============================================================================
+
+// moduleName reads the module path from the go.mod of the working directory,
+// which has to be the root of the module.
+func moduleName() (string, error) {
+	f, err := os.Open("go.mod")
+	if err != nil {
+		return "", fmt.Errorf("run from the root of the module: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(scanner.Text(), "module "); ok {
+			return strings.TrimSpace(module), nil
+		}
+	}
+
+	return "", errors.New("go.mod has no module line")
+}
+
+// migrationVersion matches the version line of a migration.
+var migrationVersion = regexp.MustCompile(`(?m)^-- Version: (\d+)\.(\d+)\s*$`)
+
+// nextVersion returns the version following the last migration in the
+// migration file, keeping the two digit minor versions of the existing ones.
+func nextVersion() (string, error) {
+	data, err := os.ReadFile(migrationFile)
+	if err != nil {
+		return "", fmt.Errorf("reading migrations: %w", err)
+	}
+
+	versions := migrationVersion.FindAllSubmatch(data, -1)
+	if len(versions) == 0 {
+		return "1.01", nil
+	}
+
+	last := versions[len(versions)-1]
+
+	minor, err := strconv.Atoi(string(last[2]))
+	if err != nil {
+		return "", fmt.Errorf("unexpected migration version %s.%s", last[1], last[2])
+	}
+
+	return fmt.Sprintf("%s.%02d", last[1], minor+1), nil
+}
+
+// pluralize returns the English plural of the domain name for its routes
+// and table.
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// shortName returns the variable name used for a value of the domain, the
+// first three letters of its name like the hand written domains use, unless
+// that clashes with a name the generated code already uses.
+func shortName(name string) string {
+	short := name[:min(3, len(name))]
+
+	switch short {
+	case "app", "ctx", "err", "db", "id", "row", "got":
+		return name
+	}
+	return short
+}
+
+// pad returns a template function padding values to the width so the
+// columns of the migration line up.
+func pad(width int) func(string) string {
+	return func(s string) string {
+		return fmt.Sprintf("%-*s", width, s)
+	}
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenInfo decodes the token, verifies its signature against the keys
+// folder or, when jwksURL is set, against the key set published there and
+// prints its claims with the time left before it expires. The claims are
+// printed even when the signature doesn't verify, which is what's wanted
+// when chasing a 401.
+func TokenInfo(ctx context.Context, folder string, token string, jwksURL string) error {
+	if token == "" {
+		return errors.New("missing token, usage: tokeninfo <token> [jwks-url]")
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	keyFunc, err := fileKeyFunc(folder)
+	if jwksURL != "" {
+		keyFunc, err = jwksKeyFunc(ctx, jwksURL)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The claims aren't validated by the parser so an expired token still
+	// reports whether its signature is good.
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "ES256", "ES384", "ES512"}),
+		jwt.WithoutClaimsValidation(),
+	)
+
+	var c claims
+	tkn, verifyErr := parser.ParseWithClaims(token, &c, keyFunc)
+	if tkn == nil || tkn.Header == nil {
+		return fmt.Errorf("decoding token: %w", verifyErr)
+	}
+
+	fmt.Println("alg:      ", tkn.Header["alg"])
+	fmt.Println("kid:      ", tkn.Header["kid"])
+	fmt.Println("subject:  ", c.Subject)
+	fmt.Println("issuer:   ", c.Issuer)
+	fmt.Println("roles:    ", strings.Join(c.Roles, ", "))
+
+	if c.IssuedAt != nil {
+		fmt.Println("issued:   ", c.IssuedAt.Format(time.RFC3339))
+	}
+
+	switch {
+	case c.ExpiresAt == nil:
+		fmt.Println("expires:   never")
+	case time.Until(c.ExpiresAt.Time) <= 0:
+		fmt.Printf("expires:   %s (expired %s ago)\n", c.ExpiresAt.Format(time.RFC3339), time.Since(c.ExpiresAt.Time).Round(time.Second))
+	default:
+		fmt.Printf("expires:   %s (in %s)\n", c.ExpiresAt.Format(time.RFC3339), time.Until(c.ExpiresAt.Time).Round(time.Second))
+	}
+
+	if verifyErr != nil {
+		fmt.Println("signature: invalid")
+		return fmt.Errorf("verifying token: %w", verifyErr)
+	}
+
+	fmt.Println("signature: valid")
+
+	return nil
+}
+
+// fileKeyFunc resolves the verification key of a token from the keys folder.
+func fileKeyFunc(folder string) (jwt.Keyfunc, error) {
+	ks := keystore.New()
+	if _, err := ks.LoadKeys(os.DirFS(folder)); err != nil {
+		return nil, fmt.Errorf("reading keys: %w", err)
+	}
+
+	f := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		publicPEM, err := ks.PublicKey(kid)
+		if err != nil {
+			return nil, fmt.Errorf("kid[%s]: %w", kid, err)
+		}
+
+		return jwt.ParseRSAPublicKeyFromPEM([]byte(publicPEM))
+	}
+
+	return f, nil
+}
+
+// jwksKeyFunc resolves the verification key of a token from the key set
+// published at the url.
+func jwksKeyFunc(ctx context.Context, url string) (jwt.Keyfunc, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching jwks: status %d", resp.StatusCode)
+	}
+
+	var jwks keystore.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("decoding jwks: %w", err)
+	}
+
+	f := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		for _, jwk := range jwks.Keys {
+			if jwk.KeyID == kid {
+				return jwk.PublicKey()
+			}
+		}
+
+		return nil, fmt.Errorf("kid[%s]: %w", kid, keystore.ErrKeyNotFound)
+	}
+
+	return f, nil
+}
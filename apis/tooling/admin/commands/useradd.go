@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+
+	"github.com/AlmirSai/service/business/domain/userbus"
+	"github.com/AlmirSai/service/business/domain/userbus/stores/userdb"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// UserAdd adds a user with the roles to the database, for bootstrapping the
+// first admin account of a fresh environment.
+func UserAdd(ctx context.Context, log *logger.Logger, cfg sqldb.Config, name string, email string, password string, roles []string) error {
+	if name == "" || email == "" || password == "" {
+		fmt.Println("help: useradd <name> <email> <password> [role,...]")
+		return ErrHelp
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return fmt.Errorf("parsing email: %w", err)
+	}
+
+	usrRoles, err := userbus.ParseRoles(roles)
+	if err != nil {
+		return fmt.Errorf("parsing roles: %w", err)
+	}
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	userBus := userbus.NewBusiness(log, userdb.NewStore(log, db))
+
+	nu := userbus.NewUser{
+		Name:     name,
+		Email:    *addr,
+		Roles:    usrRoles,
+		Password: password,
+	}
+
+	usr, err := userBus.Create(ctx, nu)
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	fmt.Println("user id:", usr.ID)
+
+	return nil
+}
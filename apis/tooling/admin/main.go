@@ -0,0 +1,238 @@
+// This program performs administrative tasks for the sales service.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/AlmirSai/service/apis/tooling/admin/commands"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/ardanlabs/conf/v3"
+)
+
+type config struct {
+	conf.Version
+	Args        conf.Args
+	Environment string `conf:"default:development"`
+	DB          struct {
+		User         string `conf:"default:postgres"`
+		Password     string `conf:"default:postgres,mask"`
+		HostPort     string `conf:"default:database-service.sales-system.svc.cluster.local"`
+		Name         string `conf:"default:postgres"`
+		MaxIdleConns int    `conf:"default:2"`
+		MaxOpenConns int    `conf:"default:0"`
+		DisableTLS   bool   `conf:"default:true"`
+	}
+	Auth struct {
+		KeysFolder string `conf:"default:deployments/keys/"`
+	}
+	Token struct {
+		Issuer string        `conf:"default:service project"`
+		Expiry time.Duration `conf:"default:8760h"`
+	}
+	Call struct {
+		Host   string `conf:"default:http://localhost:3000"`
+		UserID string `conf:"default:5cf37266-3473-4006-984f-9325122678b7"`
+	}
+	Docs struct {
+		Output string `conf:"help:file to write, - for stdout, defaults to docs/openapi.<format>"`
+	}
+	Load struct {
+		Endpoints   []string      `conf:"default:GET /v1/customers;GET /v1/orders"`
+		Rate        int           `conf:"default:50"`
+		Concurrency int           `conf:"default:10"`
+		Duration    time.Duration `conf:"default:30s"`
+		NoAuth      bool          `conf:"default:false"`
+		DebugHost   string        `conf:"default:http://localhost:3010"`
+		TailEvery   time.Duration `conf:"default:5s"`
+	}
+	User struct {
+		Roles []string `conf:"default:ADMIN"`
+	}
+}
+
+func main() {
+	if err := run(); err != nil {
+		if !errors.Is(err, commands.ErrHelp) {
+			fmt.Println("msg", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg := config{
+		Version: conf.Version{
+			Build: "develop",
+			Desc:  "Sales service administration",
+		},
+	}
+
+	// The same prefix as the sales service, so the admin tool connects with
+	// the environment the service runs with.
+	const prefix = "SALES"
+	help, err := conf.Parse(prefix, &cfg)
+	if err != nil {
+		if errors.Is(err, conf.ErrHelpWanted) {
+			fmt.Println(help)
+			return nil
+		}
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	return processCommands(cfg.Args, cfg)
+}
+
+// processCommands handles the execution of the commands specified on
+// the command line.
+func processCommands(args conf.Args, cfg config) error {
+	dbConfig := sqldb.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		HostPort:     cfg.DB.HostPort,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+	}
+
+	callConfig := commands.CallConfig{
+		Host:       cfg.Call.Host,
+		UserID:     cfg.Call.UserID,
+		KeysFolder: cfg.Auth.KeysFolder,
+		DB:         dbConfig,
+		Token: commands.TokenConfig{
+			Issuer: cfg.Token.Issuer,
+			Expiry: cfg.Token.Expiry,
+		},
+	}
+
+	log := logger.New(os.Stdout, logger.LevelInfo, "ADMIN", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch args.Num(0) {
+	case "genkey":
+		if err := commands.GenKey(cfg.Auth.KeysFolder, args.Num(1), args.Num(2) == "jwk"); err != nil {
+			return fmt.Errorf("key generation: %w", err)
+		}
+
+	case "gentoken":
+		tokenConfig := commands.TokenConfig{
+			Issuer: cfg.Token.Issuer,
+			Expiry: cfg.Token.Expiry,
+		}
+
+		if err := commands.GenToken(ctx, log, dbConfig, cfg.Auth.KeysFolder, args.Num(1), args.Num(2), tokenConfig, args.Num(3) == "curl"); err != nil {
+			return fmt.Errorf("generating token: %w", err)
+		}
+
+	case "call":
+		if err := commands.Call(ctx, log, callConfig, args.Num(1), args.Num(2), args.Num(3)); err != nil {
+			return fmt.Errorf("calling service: %w", err)
+		}
+
+	case "loadtest":
+		loadConfig := commands.LoadConfig{
+			Call:        callConfig,
+			Endpoints:   cfg.Load.Endpoints,
+			Rate:        cfg.Load.Rate,
+			Concurrency: cfg.Load.Concurrency,
+			Duration:    cfg.Load.Duration,
+			NoAuth:      cfg.Load.NoAuth,
+			DebugHost:   cfg.Load.DebugHost,
+			TailEvery:   cfg.Load.TailEvery,
+		}
+
+		// The run outlives the command timeout and an interrupt ends it early
+		// with the report of what was sent.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := commands.LoadTest(ctx, log, loadConfig); err != nil {
+			return fmt.Errorf("load testing: %w", err)
+		}
+
+	case "tokeninfo":
+		if err := commands.TokenInfo(ctx, cfg.Auth.KeysFolder, args.Num(1), args.Num(2)); err != nil {
+			return fmt.Errorf("inspecting token: %w", err)
+		}
+
+	case "config":
+		if err := commands.ShowConfig(args.Num(1)); err != nil {
+			return fmt.Errorf("showing config: %w", err)
+		}
+
+	case "docs":
+		if err := commands.Docs(cfg.Build, args.Num(1), cfg.Docs.Output); err != nil {
+			return fmt.Errorf("generating docs: %w", err)
+		}
+
+	case "scaffold":
+		if err := commands.Scaffold(args.Num(1), args.Num(2)); err != nil {
+			return fmt.Errorf("scaffolding domain: %w", err)
+		}
+
+	case "useradd":
+		if err := commands.UserAdd(ctx, log, dbConfig, args.Num(1), args.Num(2), args.Num(3), cfg.User.Roles); err != nil {
+			return fmt.Errorf("adding user: %w", err)
+		}
+
+	case "migrate":
+		if err := commands.Migrate(ctx, dbConfig); err != nil {
+			return fmt.Errorf("migrating database: %w", err)
+		}
+
+	case "seed":
+		if err := commands.Seed(ctx, dbConfig); err != nil {
+			return fmt.Errorf("seeding database: %w", err)
+		}
+
+	case "migrate-status":
+		if err := commands.MigrateStatus(ctx, dbConfig); err != nil {
+			return fmt.Errorf("migrating status: %w", err)
+		}
+
+	case "migrate-dryrun":
+		if err := commands.MigrateDryRun(ctx, dbConfig); err != nil {
+			return fmt.Errorf("migrating dry run: %w", err)
+		}
+
+	case "migrate-down":
+		if err := commands.MigrateDown(ctx, dbConfig, cfg.Environment); err != nil {
+			return fmt.Errorf("migrating down: %w", err)
+		}
+
+	case "audit-verify":
+		if err := commands.AuditVerify(args.Num(1)); err != nil {
+			return fmt.Errorf("verifying audit log: %w", err)
+		}
+
+	default:
+		fmt.Println("genkey:         generate a key pair named after its kid, usage: genkey [rsa|ecdsa] [jwk]")
+		fmt.Println("gentoken:       mint a token for a user with its roles, usage: gentoken <userID> [kid] [curl]")
+		fmt.Println("useradd:        add a user with the SALES_USER_ROLES roles, usage: useradd <name> <email> <password>")
+		fmt.Println("call:           call the service with a cached token, usage: call <method> <path> [body|-]")
+		fmt.Println("loadtest:       fire the load endpoint mix at the service and report latencies")
+		fmt.Println("tokeninfo:      decode and verify a token, usage: tokeninfo <token> [jwks-url]")
+		fmt.Println("config:         print the resolved config of a service, usage: config [sales]")
+		fmt.Println("docs:           write the OpenAPI document of the routes, usage: docs [json|yaml]")
+		fmt.Println("scaffold:       generate a new domain, usage: scaffold <name> <field:type,...>")
+		fmt.Println("migrate:        apply the pending migrations and print the schema version")
+		fmt.Println("seed:           apply the pending migrations and load the seed data")
+		fmt.Println("migrate-status: list the applied and pending migrations")
+		fmt.Println("migrate-dryrun: print the SQL of the pending migrations without running it")
+		fmt.Println("migrate-down:   revert the last applied migration, refused in production")
+		fmt.Println("audit-verify:   check the hash chain of an audit log, usage: audit-verify <file>")
+		fmt.Println("provide a command to get more help.")
+		return commands.ErrHelp
+	}
+
+	return nil
+}
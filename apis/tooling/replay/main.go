@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AlmirSai/service/app/sdk/capture"
+)
+
+var (
+	file        string
+	target      string
+	token       string
+	concurrency int
+	timeout     time.Duration
+	dryRun      bool
+)
+
+func init() {
+	// Register command-line flags that control the replay
+	flag.StringVar(&file, "file", "capture.jsonl", "capture file to replay")
+	flag.StringVar(&target, "target", "http://localhost:3000", "base URL of the instance receiving the traffic")
+	flag.StringVar(&token, "token", "", "bearer token sent in place of the redacted Authorization header")
+	flag.IntVar(&concurrency, "c", 4, "number of concurrent requests")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "per request timeout")
+	flag.BoolVar(&dryRun, "dry-run", false, "print the requests without sending them")
+}
+
+func main() {
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(ctx context.Context) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	client := http.Client{Timeout: timeout}
+
+	var (
+		mu       sync.Mutex
+		statuses = make(map[string]int)
+		wg       sync.WaitGroup
+	)
+
+	records := make(chan capture.Record)
+
+	// Start the workers that re-issue the captured requests
+	for range concurrency {
+		wg.Go(func() {
+			for rec := range records {
+				status := replay(ctx, &client, rec)
+
+				mu.Lock()
+				statuses[status]++
+				mu.Unlock()
+			}
+		})
+	}
+
+	err = capture.Read(f, func(rec capture.Record) error {
+		if dryRun {
+			fmt.Printf("%s %s?%s %s\n", rec.Method, rec.Path, rec.Query, string(rec.Body))
+			return nil
+		}
+
+		select {
+		case records <- rec:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	close(records)
+	wg.Wait()
+
+	// Print a summary of the response statuses received
+	keys := make([]string, 0, len(statuses))
+	for k := range statuses {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Printf("%-10s %d\n", k, statuses[k])
+	}
+
+	return err
+}
+
+// replay sends a single captured request to the target and returns the
+// status code, or "error" when the request couldn't be completed.
+func replay(ctx context.Context, client *http.Client, rec capture.Record) string {
+	url := strings.TrimSuffix(target, "/") + rec.Path
+	if rec.Query != "" {
+		url += "?" + rec.Query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rec.Method, url, bytes.NewReader(rec.Body))
+	if err != nil {
+		log.Println(err)
+		return "error"
+	}
+
+	for k, v := range rec.Headers {
+		req.Header[k] = v
+	}
+	req.Header.Del("Authorization")
+	req.Header.Del("Cookie")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Println(err)
+		return "error"
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	return fmt.Sprintf("%d", resp.StatusCode)
+}
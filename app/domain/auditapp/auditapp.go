@@ -0,0 +1,54 @@
+// Package auditapp maintains the app layer api for the audit domain.
+package auditapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type app struct {
+	auditBus *auditbus.Business
+}
+
+func newApp(auditBus *auditbus.Business) *app {
+	return &app{
+		auditBus: auditBus,
+	}
+}
+
+func (a *app) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	qp := parseQueryParams(r)
+
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, auditbus.DefaultOrderBy)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	audits, err := a.auditBus.Query(ctx, filter, orderBy, pg)
+	if err != nil {
+		return err
+	}
+
+	total, err := a.auditBus.Count(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, page.NewDocument(toAppAudits(audits), total, pg), http.StatusOK)
+}
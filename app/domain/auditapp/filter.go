@@ -0,0 +1,84 @@
+package auditapp
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page      string
+	Rows      string
+	OrderBy   string
+	ObjID     string
+	ObjDomain string
+	ActorID   string
+	Action    string
+	StartDate string
+	EndDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	return queryParams{
+		Page:      values.Get("page"),
+		Rows:      values.Get("rows"),
+		OrderBy:   values.Get("orderBy"),
+		ObjID:     values.Get("obj_id"),
+		ObjDomain: values.Get("obj_domain"),
+		ActorID:   values.Get("actor_id"),
+		Action:    values.Get("action"),
+		StartDate: values.Get("start_date"),
+		EndDate:   values.Get("end_date"),
+	}
+}
+
+func parseFilter(qp queryParams) (auditbus.QueryFilter, error) {
+	var filter auditbus.QueryFilter
+
+	if qp.ObjID != "" {
+		id, err := uuid.Parse(qp.ObjID)
+		if err != nil {
+			return auditbus.QueryFilter{}, fmt.Errorf("obj_id: %w", err)
+		}
+		filter.ObjID = &id
+	}
+
+	if qp.ObjDomain != "" {
+		filter.ObjDomain = &qp.ObjDomain
+	}
+
+	if qp.ActorID != "" {
+		id, err := uuid.Parse(qp.ActorID)
+		if err != nil {
+			return auditbus.QueryFilter{}, fmt.Errorf("actor_id: %w", err)
+		}
+		filter.ActorID = &id
+	}
+
+	if qp.Action != "" {
+		filter.Action = &qp.Action
+	}
+
+	if qp.StartDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartDate)
+		if err != nil {
+			return auditbus.QueryFilter{}, fmt.Errorf("start_date: %w", err)
+		}
+		filter.StartDate = &t
+	}
+
+	if qp.EndDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndDate)
+		if err != nil {
+			return auditbus.QueryFilter{}, fmt.Errorf("end_date: %w", err)
+		}
+		filter.EndDate = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,44 @@
+package auditapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+)
+
+// Audit represents an audit returned from the API.
+type Audit struct {
+	ID        string          `json:"id"`
+	ObjID     string          `json:"objID"`
+	ObjDomain string          `json:"objDomain"`
+	ActorID   string          `json:"actorID,omitempty"`
+	Action    string          `json:"action"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Timestamp string          `json:"timestamp"`
+}
+
+func toAppAudit(a auditbus.Audit) Audit {
+	var actorID string
+	if a.ActorID != nil {
+		actorID = a.ActorID.String()
+	}
+
+	return Audit{
+		ID:        a.ID.String(),
+		ObjID:     a.ObjID.String(),
+		ObjDomain: a.ObjDomain,
+		ActorID:   actorID,
+		Action:    a.Action,
+		Data:      a.Data,
+		Timestamp: a.Timestamp.Format(time.RFC3339),
+	}
+}
+
+func toAppAudits(audits []auditbus.Audit) []Audit {
+	app := make([]Audit, len(audits))
+	for i, a := range audits {
+		app[i] = toAppAudit(a)
+	}
+	return app
+}
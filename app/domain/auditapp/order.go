@@ -0,0 +1,10 @@
+package auditapp
+
+import "github.com/AlmirSai/service/business/domain/auditbus"
+
+var orderByFields = map[string]string{
+	"obj_id":     auditbus.OrderByObjID,
+	"obj_domain": auditbus.OrderByObjDomain,
+	"action":     auditbus.OrderByAction,
+	"timestamp":  auditbus.OrderByTimestamp,
+}
@@ -0,0 +1,28 @@
+package auditapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	AuditBus *auditbus.Business
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.AuditBus)
+
+	app.Handle(http.MethodGet, version, "/audits", api.query).
+		Describe(web.RouteDoc{
+			Summary:  "Queries audits by actor, object and date range",
+			Tags:     []string{"audits"},
+			Response: page.Document[Audit]{},
+		})
+}
@@ -0,0 +1,28 @@
+// Package authapp maintains the app layer api for the auth domain.
+package authapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type app struct {
+	keys *keystore.KeyStore
+}
+
+func newApp(keys *keystore.KeyStore) *app {
+	return &app{
+		keys: keys,
+	}
+}
+
+// jwks returns the public keys valid for verifying tokens. The response is
+// cacheable for a short time so a rotation reaches validators quickly.
+func (a *app) jwks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Cache-Control", "public, max-age=60")
+
+	return web.Respond(ctx, w, a.keys.JWKS(), http.StatusOK)
+}
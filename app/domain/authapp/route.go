@@ -0,0 +1,27 @@
+package authapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	KeyStore *keystore.KeyStore
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.KeyStore)
+
+	app.Handle(http.MethodGet, version, "/auth/jwks", api.jwks).
+		Describe(web.RouteDoc{
+			Summary:  "Returns the JSON Web Key Set for verifying tokens",
+			Tags:     []string{"auth"},
+			Response: keystore.JWKS{},
+		})
+}
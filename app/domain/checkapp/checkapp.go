@@ -0,0 +1,80 @@
+// Package checkapp maintains the app layer api for the check domain.
+package checkapp
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/version"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type app struct {
+	build string
+	ready ReadyFunc
+}
+
+func newApp(build string, ready ReadyFunc) *app {
+	return &app{
+		build: build,
+		ready: ready,
+	}
+}
+
+// readiness checks if the service is ready and if not will return a 500
+// status. Do not respond by just returning an error because further up in
+// the call stack it will interpret that as a non-trusted error.
+func (a *app) readiness(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	status := Status{
+		Status: "ok",
+	}
+	statusCode := http.StatusOK
+
+	if a.ready != nil {
+		if err := a.ready(ctx); err != nil {
+			status.Status = "not ready"
+			statusCode = http.StatusInternalServerError
+		}
+	}
+
+	return web.Respond(ctx, w, status, statusCode)
+}
+
+// liveness returns simple status info if the service is alive. If the
+// app is deployed to a Kubernetes cluster, it will also return pod, node, and
+// namespace details via the Downward API. The Kubernetes environment variables
+// need to be set within your Pod/Deployment manifest.
+func (a *app) liveness(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unavailable"
+	}
+
+	var buildTime string
+	if t := version.BuildTime(); !t.IsZero() {
+		buildTime = t.Format(time.RFC3339)
+	}
+
+	info := Info{
+		Status:     "up",
+		Build:      a.build,
+		Revision:   version.Revision(),
+		BuildTime:  buildTime,
+		Host:       host,
+		Name:       os.Getenv("KUBERNETES_NAME"),
+		PodIP:      os.Getenv("KUBERNETES_POD_IP"),
+		Node:       os.Getenv("KUBERNETES_NODE_NAME"),
+		Namespace:  os.Getenv("KUBERNETES_NAMESPACE"),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+	}
+
+	// This handler provides a free timer loop.
+
+	return web.Respond(ctx, w, info, http.StatusOK)
+}
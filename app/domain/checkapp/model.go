@@ -0,0 +1,20 @@
+package checkapp
+
+// Status represents the readiness state of the service.
+type Status struct {
+	Status string `json:"status"`
+}
+
+// Info represents information about the service.
+type Info struct {
+	Status     string `json:"status,omitempty"`
+	Build      string `json:"build,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+	BuildTime  string `json:"buildTime,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Name       string `json:"name,omitempty"`
+	PodIP      string `json:"podIP,omitempty"`
+	Node       string `json:"node,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	GOMAXPROCS int    `json:"GOMAXPROCS,omitempty"`
+}
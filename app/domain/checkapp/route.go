@@ -0,0 +1,39 @@
+package checkapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// ReadyFunc reports whether the service and its dependencies are ready to
+// receive traffic. It's shared with other transports so they agree on state.
+type ReadyFunc func(ctx context.Context) error
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Ready ReadyFunc
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.Build, cfg.Ready)
+
+	app.HandleNoMiddleware(http.MethodGet, version, "/readiness", api.readiness).
+		Describe(web.RouteDoc{
+			Summary:  "Reports whether the service is ready to receive traffic",
+			Tags:     []string{"check"},
+			Response: Status{},
+		})
+
+	app.HandleNoMiddleware(http.MethodGet, version, "/liveness", api.liveness).
+		Describe(web.RouteDoc{
+			Summary:  "Reports whether the service is alive",
+			Tags:     []string{"check"},
+			Response: Info{},
+		})
+}
@@ -0,0 +1,135 @@
+// Package customerapp maintains the app layer api for the customer domain.
+package customerapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+type app struct {
+	customerBus *customerbus.Business
+}
+
+func newApp(customerBus *customerbus.Business) *app {
+	return &app{
+		customerBus: customerBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app NewCustomer
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nc, err := toBusNewCustomer(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cus, err := a.customerBus.Create(ctx, nc)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppCustomer(cus), http.StatusCreated)
+}
+
+func (a *app) update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app UpdateCustomer
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	uc, err := toBusUpdateCustomer(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cus, err := a.queryCustomer(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	cus, err = a.customerBus.Update(ctx, cus, uc)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppCustomer(cus), http.StatusOK)
+}
+
+func (a *app) delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	cus, err := a.queryCustomer(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if err := a.customerBus.Delete(ctx, cus); err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+func (a *app) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	qp := parseQueryParams(r)
+
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, customerbus.DefaultOrderBy)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cuss, err := a.customerBus.Query(ctx, filter, orderBy, pg)
+	if err != nil {
+		return err
+	}
+
+	total, err := a.customerBus.Count(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, page.NewDocument(toAppCustomers(cuss), total, pg), http.StatusOK)
+}
+
+func (a *app) queryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	cus, err := a.queryCustomer(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppCustomer(cus), http.StatusOK)
+}
+
+// queryCustomer loads the customer named by the customer_id path parameter.
+func (a *app) queryCustomer(ctx context.Context, r *http.Request) (customerbus.Customer, error) {
+	customerID, err := uuid.Parse(web.Param(r, "customer_id"))
+	if err != nil {
+		return customerbus.Customer{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	cus, err := a.customerBus.QueryByID(ctx, customerID)
+	if err != nil {
+		return customerbus.Customer{}, err
+	}
+
+	return cus, nil
+}
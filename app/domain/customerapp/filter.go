@@ -0,0 +1,53 @@
+package customerapp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page    string
+	Rows    string
+	OrderBy string
+	UserID  string
+	Name    string
+	Email   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	return queryParams{
+		Page:    values.Get("page"),
+		Rows:    values.Get("rows"),
+		OrderBy: values.Get("orderBy"),
+		UserID:  values.Get("user_id"),
+		Name:    values.Get("name"),
+		Email:   values.Get("email"),
+	}
+}
+
+func parseFilter(qp queryParams) (customerbus.QueryFilter, error) {
+	var filter customerbus.QueryFilter
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return customerbus.QueryFilter{}, fmt.Errorf("user_id: %w", err)
+		}
+		filter.UserID = &id
+	}
+
+	if qp.Name != "" {
+		filter.Name = &qp.Name
+	}
+
+	if qp.Email != "" {
+		filter.Email = &qp.Email
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,224 @@
+package customerapp
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/app/sdk/encoding"
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+// Customer represents a customer returned from the API.
+type Customer struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userID,omitempty"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone,omitempty"`
+	Addresses   []Address `json:"addresses"`
+	DateCreated string    `json:"dateCreated"`
+	DateUpdated string    `json:"dateUpdated"`
+}
+
+// Address represents a postal address of a customer returned from the API.
+type Address struct {
+	ID      string      `json:"id"`
+	Kind    AddressKind `json:"kind"`
+	Line1   string      `json:"line1"`
+	Line2   string      `json:"line2,omitempty"`
+	City    string      `json:"city"`
+	State   string      `json:"state"`
+	ZipCode string      `json:"zipCode"`
+	Country string      `json:"country"`
+}
+
+// AddressKind is the kind of an address as it's carried in the API.
+type AddressKind = encoding.Enum[customerbus.AddressKind, addressKindParser]
+
+type addressKindParser struct{}
+
+func (addressKindParser) Parse(value string) (customerbus.AddressKind, error) {
+	return customerbus.ParseAddressKind(value)
+}
+
+func toAppCustomer(cus customerbus.Customer) Customer {
+	var userID string
+	if cus.UserID != nil {
+		userID = cus.UserID.String()
+	}
+
+	addrs := make([]Address, len(cus.Addresses))
+	for i, a := range cus.Addresses {
+		addrs[i] = Address{
+			ID:      a.ID.String(),
+			Kind:    encoding.NewEnum[customerbus.AddressKind, addressKindParser](a.Kind),
+			Line1:   a.Line1,
+			Line2:   a.Line2,
+			City:    a.City,
+			State:   a.State,
+			ZipCode: a.ZipCode,
+			Country: a.Country,
+		}
+	}
+
+	return Customer{
+		ID:          cus.ID.String(),
+		UserID:      userID,
+		Name:        cus.Name,
+		Email:       cus.Email.Address,
+		Phone:       cus.Phone,
+		Addresses:   addrs,
+		DateCreated: cus.DateCreated.Format(time.RFC3339),
+		DateUpdated: cus.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppCustomers(cuss []customerbus.Customer) []Customer {
+	items := make([]Customer, len(cuss))
+	for i, cus := range cuss {
+		items[i] = toAppCustomer(cus)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// NewCustomer defines the data needed to add a new customer.
+type NewCustomer struct {
+	UserID    string       `json:"userID,omitempty" validate:"omitempty,uuid"`
+	Name      string       `json:"name" validate:"required"`
+	Email     string       `json:"email" validate:"required,email"`
+	Phone     string       `json:"phone,omitempty" validate:"omitempty,max=32"`
+	Addresses []NewAddress `json:"addresses" validate:"dive"`
+}
+
+// NewAddress defines the data needed to add an address to a customer.
+type NewAddress struct {
+	Kind    AddressKind `json:"kind"`
+	Line1   string      `json:"line1" validate:"required"`
+	Line2   string      `json:"line2,omitempty"`
+	City    string      `json:"city" validate:"required"`
+	State   string      `json:"state"`
+	ZipCode string      `json:"zipCode" validate:"required"`
+	Country string      `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewCustomer) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewCustomer(app NewCustomer) (customerbus.NewCustomer, error) {
+	var userID *uuid.UUID
+	if app.UserID != "" {
+		id, err := uuid.Parse(app.UserID)
+		if err != nil {
+			return customerbus.NewCustomer{}, fmt.Errorf("parse userID: %w", err)
+		}
+		userID = &id
+	}
+
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return customerbus.NewCustomer{}, fmt.Errorf("parse email: %w", err)
+	}
+
+	addrs, err := toBusNewAddresses(app.Addresses)
+	if err != nil {
+		return customerbus.NewCustomer{}, err
+	}
+
+	nc := customerbus.NewCustomer{
+		UserID:    userID,
+		Name:      app.Name,
+		Email:     *addr,
+		Phone:     app.Phone,
+		Addresses: addrs,
+	}
+
+	return nc, nil
+}
+
+// =============================================================================
+
+// UpdateCustomer defines the data needed to update a customer. Fields left
+// out of the document are unchanged and addresses replace the full set.
+type UpdateCustomer struct {
+	UserID    *string       `json:"userID" validate:"omitempty,uuid"`
+	Name      *string       `json:"name" validate:"omitempty,min=1"`
+	Email     *string       `json:"email" validate:"omitempty,email"`
+	Phone     *string       `json:"phone" validate:"omitempty,max=32"`
+	Addresses *[]NewAddress `json:"addresses" validate:"omitempty,dive"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateCustomer) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateCustomer(app UpdateCustomer) (customerbus.UpdateCustomer, error) {
+	var uc customerbus.UpdateCustomer
+
+	if app.UserID != nil {
+		id, err := uuid.Parse(*app.UserID)
+		if err != nil {
+			return customerbus.UpdateCustomer{}, fmt.Errorf("parse userID: %w", err)
+		}
+		uc.UserID = &id
+	}
+
+	if app.Email != nil {
+		addr, err := mail.ParseAddress(*app.Email)
+		if err != nil {
+			return customerbus.UpdateCustomer{}, fmt.Errorf("parse email: %w", err)
+		}
+		uc.Email = addr
+	}
+
+	if app.Addresses != nil {
+		addrs, err := toBusNewAddresses(*app.Addresses)
+		if err != nil {
+			return customerbus.UpdateCustomer{}, err
+		}
+		uc.Addresses = &addrs
+	}
+
+	uc.Name = app.Name
+	uc.Phone = app.Phone
+
+	return uc, nil
+}
+
+// =============================================================================
+
+func toBusNewAddresses(app []NewAddress) ([]customerbus.NewAddress, error) {
+	addrs := make([]customerbus.NewAddress, len(app))
+	for i, a := range app {
+		if a.Kind.IsZero() {
+			return nil, fmt.Errorf("parse addresses[%d].kind: required", i)
+		}
+
+		addrs[i] = customerbus.NewAddress{
+			Kind:    a.Kind.Value,
+			Line1:   a.Line1,
+			Line2:   a.Line2,
+			City:    a.City,
+			State:   a.State,
+			ZipCode: a.ZipCode,
+			Country: a.Country,
+		}
+	}
+	return addrs, nil
+}
@@ -0,0 +1,10 @@
+package customerapp
+
+import "github.com/AlmirSai/service/business/domain/customerbus"
+
+var orderByFields = map[string]string{
+	"customer_id":  customerbus.OrderByID,
+	"name":         customerbus.OrderByName,
+	"email":        customerbus.OrderByEmail,
+	"date_created": customerbus.OrderByDateCreated,
+}
@@ -0,0 +1,59 @@
+package customerapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	CustomerBus *customerbus.Business
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.CustomerBus)
+
+	app.Handle(http.MethodPost, version, "/customers", api.create).
+		Describe(web.RouteDoc{
+			Summary:  "Creates a customer",
+			Tags:     []string{"customers"},
+			Request:  NewCustomer{},
+			Response: Customer{},
+			Status:   http.StatusCreated,
+		})
+
+	app.Handle(http.MethodGet, version, "/customers", api.query).
+		Describe(web.RouteDoc{
+			Summary:  "Queries customers",
+			Tags:     []string{"customers"},
+			Response: page.Document[Customer]{},
+		})
+
+	app.Handle(http.MethodGet, version, "/customers/{customer_id}", api.queryByID).
+		Describe(web.RouteDoc{
+			Summary:  "Queries a customer by its ID",
+			Tags:     []string{"customers"},
+			Response: Customer{},
+		})
+
+	app.Handle(http.MethodPut, version, "/customers/{customer_id}", api.update).
+		Describe(web.RouteDoc{
+			Summary:  "Updates a customer",
+			Tags:     []string{"customers"},
+			Request:  UpdateCustomer{},
+			Response: Customer{},
+		})
+
+	app.Handle(http.MethodDelete, version, "/customers/{customer_id}", api.delete).
+		Describe(web.RouteDoc{
+			Summary: "Deletes a customer without orders",
+			Tags:    []string{"customers"},
+			Status:  http.StatusNoContent,
+		})
+}
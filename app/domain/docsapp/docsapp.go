@@ -0,0 +1,57 @@
+// Package docsapp serves the OpenAPI document generated from the route table
+// along with a Swagger UI page to browse it. The Swagger UI assets are
+// embedded, so the page works without access to a CDN.
+package docsapp
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/openapi"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+//go:embed static
+var static embed.FS
+
+// assets returns the handler serving the Swagger UI files under the prefix.
+func assets(prefix string) http.Handler {
+	sub, err := fs.Sub(static, "static/swagger-ui")
+	if err != nil {
+		panic(err)
+	}
+
+	return http.StripPrefix(prefix, http.FileServerFS(sub))
+}
+
+type app struct {
+	web  *web.App
+	info openapi.Info
+}
+
+func newApp(webApp *web.App, info openapi.Info) *app {
+	return &app{
+		web:  webApp,
+		info: info,
+	}
+}
+
+// spec returns the OpenAPI document. It's built on every call from the
+// routes registered at that time, so it always matches the running binary.
+func (a *app) spec(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	doc := openapi.Build(a.info, a.web.Routes())
+
+	return web.Respond(ctx, w, doc, http.StatusOK)
+}
+
+// ui serves the Swagger UI page which loads the spec from a relative URL.
+func (a *app) ui(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := static.ReadFile("static/index.html")
+	if err != nil {
+		return err
+	}
+
+	return web.RespondRaw(ctx, w, page, "text/html; charset=utf-8", http.StatusOK)
+}
@@ -0,0 +1,36 @@
+package docsapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/openapi"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Title string
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(app, openapi.Info{
+		Title:   cfg.Title,
+		Version: cfg.Build,
+	})
+
+	app.HandleRaw(http.MethodGet, version, "/docs", http.RedirectHandler("/"+version+"/docs/", http.StatusMovedPermanently)).
+		Describe(web.RouteDoc{Hidden: true})
+
+	app.Handle(http.MethodGet, version, "/docs/{$}", api.ui).
+		Describe(web.RouteDoc{Hidden: true})
+
+	app.Handle(http.MethodGet, version, "/docs/openapi.json", api.spec).
+		Describe(web.RouteDoc{Hidden: true})
+
+	app.HandleRaw(http.MethodGet, version, "/docs/swagger-ui/", assets("/"+version+"/docs/swagger-ui/")).
+		Describe(web.RouteDoc{Hidden: true})
+}
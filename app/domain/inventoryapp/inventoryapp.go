@@ -0,0 +1,44 @@
+// Package inventoryapp maintains the app layer api for the inventory domain.
+package inventoryapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type app struct {
+	inventoryBus *inventorybus.Business
+}
+
+func newApp(inventoryBus *inventorybus.Business) *app {
+	return &app{
+		inventoryBus: inventoryBus,
+	}
+}
+
+func (a *app) update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app UpdateStock
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	stock, err := a.inventoryBus.SetStock(ctx, web.Param(r, "sku"), toBusUpdateStock(app))
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppStock(stock), http.StatusOK)
+}
+
+func (a *app) queryBySKU(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	stock, err := a.inventoryBus.QueryBySKU(ctx, web.Param(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppStock(stock), http.StatusOK)
+}
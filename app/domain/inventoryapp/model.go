@@ -0,0 +1,54 @@
+package inventoryapp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/foundation/validate"
+)
+
+// Stock represents the inventory level of a SKU returned from the API.
+type Stock struct {
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	OnHand      int    `json:"onHand"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+func toAppStock(s inventorybus.Stock) Stock {
+	return Stock{
+		SKU:         s.SKU,
+		Name:        s.Name,
+		OnHand:      s.OnHand,
+		Reserved:    s.Reserved,
+		Available:   s.Available(),
+		DateUpdated: s.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// UpdateStock defines the data needed to set the stock of a SKU.
+type UpdateStock struct {
+	Name   string `json:"name" validate:"required"`
+	OnHand int    `json:"onHand" validate:"gte=0"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateStock) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateStock(app UpdateStock) inventorybus.UpdateStock {
+	return inventorybus.UpdateStock{
+		Name:   app.Name,
+		OnHand: app.OnHand,
+	}
+}
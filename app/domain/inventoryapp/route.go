@@ -0,0 +1,35 @@
+package inventoryapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	InventoryBus *inventorybus.Business
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.InventoryBus)
+
+	app.Handle(http.MethodGet, version, "/inventory/{sku}", api.queryBySKU).
+		Describe(web.RouteDoc{
+			Summary:  "Queries the stock of a SKU",
+			Tags:     []string{"inventory"},
+			Response: Stock{},
+		})
+
+	app.Handle(http.MethodPut, version, "/inventory/{sku}", api.update).
+		Describe(web.RouteDoc{
+			Summary:  "Sets the on hand stock of a SKU",
+			Tags:     []string{"inventory"},
+			Request:  UpdateStock{},
+			Response: Stock{},
+		})
+}
@@ -0,0 +1,168 @@
+package orderapp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/app/sdk/encoding"
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/foundation/validate"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// Order represents an order returned from the API.
+type Order struct {
+	ID              string `json:"id"`
+	UserID          string `json:"userID"`
+	CustomerID      string `json:"customerID,omitempty"`
+	Status          Status `json:"status"`
+	Currency        string `json:"currency"`
+	Items           []Item `json:"items"`
+	Subtotal        int64  `json:"subtotal"`
+	Discount        int64  `json:"discount"`
+	Tax             int64  `json:"tax"`
+	Total           int64  `json:"total"`
+	TaxJurisdiction string `json:"taxJurisdiction,omitempty"`
+	DateCreated     string `json:"dateCreated"`
+	DateUpdated     string `json:"dateUpdated"`
+}
+
+// Status is the status of an order as it's carried in the API.
+type Status = encoding.Enum[orderbus.Status, statusParser]
+
+type statusParser struct{}
+
+func (statusParser) Parse(value string) (orderbus.Status, error) {
+	return orderbus.ParseStatus(value)
+}
+
+// Item represents a line item of an order returned from the API.
+type Item struct {
+	ID        string `json:"id"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unitPrice"`
+	Total     int64  `json:"total"`
+}
+
+func toAppOrder(ord orderbus.Order) Order {
+	items := make([]Item, len(ord.Items))
+	for i, it := range ord.Items {
+		items[i] = Item{
+			ID:        it.ID.String(),
+			SKU:       it.SKU,
+			Name:      it.Name,
+			Quantity:  it.Quantity,
+			UnitPrice: it.UnitPrice.Minor(),
+			Total:     it.Total().Minor(),
+		}
+	}
+
+	var customerID string
+	if ord.CustomerID != uuid.Nil {
+		customerID = ord.CustomerID.String()
+	}
+
+	return Order{
+		ID:              ord.ID.String(),
+		UserID:          ord.UserID.String(),
+		CustomerID:      customerID,
+		Status:          encoding.NewEnum[orderbus.Status, statusParser](ord.Status),
+		Currency:        ord.Currency.String(),
+		Items:           items,
+		Subtotal:        ord.Subtotal.Minor(),
+		Discount:        ord.Discount.Minor(),
+		Tax:             ord.Tax.Minor(),
+		Total:           ord.Total.Minor(),
+		TaxJurisdiction: ord.TaxJurisdiction,
+		DateCreated:     ord.DateCreated.Format(time.RFC3339),
+		DateUpdated:     ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []orderbus.Order) []Order {
+	items := make([]Order, len(ords))
+	for i, ord := range ords {
+		items[i] = toAppOrder(ord)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// NewOrder defines the data needed to add a new order. The order belongs to
+// the authenticated user.
+type NewOrder struct {
+	CustomerID string    `json:"customerID" validate:"required,uuid"`
+	Currency   string    `json:"currency" validate:"required,iso4217"`
+	Discount   string    `json:"discount,omitempty"`
+	Items      []NewItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// NewItem defines the data needed to add a line item to a new order.
+type NewItem struct {
+	SKU       string `json:"sku" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"gt=0"`
+	UnitPrice int64  `json:"unitPrice" validate:"gte=0"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewOrder) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	if app.Discount != "" {
+		if _, err := pricing.ParseRate(app.Discount); err != nil {
+			return fmt.Errorf("validate: %w", web.NewFieldsError("discount", err))
+		}
+	}
+
+	return nil
+}
+
+func toBusNewOrder(app NewOrder, userID uuid.UUID) (orderbus.NewOrder, error) {
+	customerID, err := uuid.Parse(app.CustomerID)
+	if err != nil {
+		return orderbus.NewOrder{}, fmt.Errorf("parse customerID: %w", err)
+	}
+
+	currency, err := money.ParseCurrency(app.Currency)
+	if err != nil {
+		return orderbus.NewOrder{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	var discount pricing.Rate
+	if app.Discount != "" {
+		discount, err = pricing.ParseRate(app.Discount)
+		if err != nil {
+			return orderbus.NewOrder{}, fmt.Errorf("parse discount: %w", err)
+		}
+	}
+
+	items := make([]orderbus.NewItem, len(app.Items))
+	for i, it := range app.Items {
+		items[i] = orderbus.NewItem{
+			SKU:       it.SKU,
+			Name:      it.Name,
+			Quantity:  it.Quantity,
+			UnitPrice: money.New(it.UnitPrice, currency),
+		}
+	}
+
+	no := orderbus.NewOrder{
+		UserID:     userID,
+		CustomerID: customerID,
+		Currency:   currency,
+		Discount:   discount,
+		Items:      items,
+	}
+
+	return no, nil
+}
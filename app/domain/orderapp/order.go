@@ -0,0 +1,11 @@
+package orderapp
+
+import "github.com/AlmirSai/service/business/domain/orderbus"
+
+var orderByFields = map[string]string{
+	"order_id":     orderbus.OrderByID,
+	"user_id":      orderbus.OrderByUserID,
+	"status":       orderbus.OrderByStatus,
+	"total":        orderbus.OrderByTotal,
+	"date_created": orderbus.OrderByDateCreated,
+}
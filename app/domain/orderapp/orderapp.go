@@ -0,0 +1,143 @@
+// Package orderapp maintains the app layer api for the order domain.
+package orderapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// maxIdempotencyKey is the longest Idempotency-Key header accepted.
+const maxIdempotencyKey = 255
+
+type app struct {
+	orderBus *orderbus.Business
+}
+
+func newApp(orderBus *orderbus.Business) *app {
+	return &app{
+		orderBus: orderBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app NewOrder
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	claims, ok := bmid.GetClaims(ctx)
+	if !ok {
+		return errs.Newf(errs.Unauthenticated, "authentication required")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "token subject isn't a user id")
+	}
+
+	no, err := toBusNewOrder(app, userID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	// A client retrying after a timeout sends the same key and gets the
+	// order of the first attempt instead of a second one, with a 200 rather
+	// than a 201 so it can tell nothing new was created.
+	key := r.Header.Get("Idempotency-Key")
+	if len(key) > maxIdempotencyKey {
+		return errs.Newf(errs.InvalidArgument, "idempotency key longer than %d characters", maxIdempotencyKey)
+	}
+	no.IdempotencyKey = key
+
+	ord, created, err := a.orderBus.Create(ctx, no)
+	if err != nil {
+		if errors.Is(err, orderbus.ErrIdempotencyReuse) {
+			return web.NewError(err, http.StatusUnprocessableEntity)
+		}
+		return err
+	}
+
+	if !created {
+		return web.Respond(ctx, w, toAppOrder(ord), http.StatusOK)
+	}
+
+	return web.Respond(ctx, w, toAppOrder(ord), http.StatusCreated)
+}
+
+func (a *app) cancel(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ord, err := a.queryOrder(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	ord, err = a.orderBus.Cancel(ctx, ord)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppOrder(ord), http.StatusOK)
+}
+
+func (a *app) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	qp := parseQueryParams(r)
+
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, orderbus.DefaultOrderBy)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ords, err := a.orderBus.Query(ctx, filter, orderBy, pg)
+	if err != nil {
+		return err
+	}
+
+	total, err := a.orderBus.Count(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, page.NewDocument(toAppOrders(ords), total, pg), http.StatusOK)
+}
+
+func (a *app) queryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ord, err := a.queryOrder(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppOrder(ord), http.StatusOK)
+}
+
+// queryOrder loads the order named by the order_id path parameter.
+func (a *app) queryOrder(ctx context.Context, r *http.Request) (orderbus.Order, error) {
+	orderID, err := uuid.Parse(web.Param(r, "order_id"))
+	if err != nil {
+		return orderbus.Order{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	ord, err := a.orderBus.QueryByID(ctx, orderID)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return ord, nil
+}
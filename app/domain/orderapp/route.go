@@ -0,0 +1,51 @@
+package orderapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	OrderBus *orderbus.Business
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.OrderBus)
+
+	app.Handle(http.MethodPost, version, "/orders", api.create).
+		Describe(web.RouteDoc{
+			Summary:  "Creates an order",
+			Tags:     []string{"orders"},
+			Request:  NewOrder{},
+			Response: Order{},
+			Status:   http.StatusCreated,
+		})
+
+	app.Handle(http.MethodGet, version, "/orders", api.query).
+		Describe(web.RouteDoc{
+			Summary:  "Queries orders",
+			Tags:     []string{"orders"},
+			Response: page.Document[Order]{},
+		})
+
+	app.Handle(http.MethodGet, version, "/orders/{order_id}", api.queryByID).
+		Describe(web.RouteDoc{
+			Summary:  "Queries an order by its ID",
+			Tags:     []string{"orders"},
+			Response: Order{},
+		})
+
+	app.Handle(http.MethodPost, version, "/orders/{order_id}/cancel", api.cancel).
+		Describe(web.RouteDoc{
+			Summary:  "Cancels an order that hasn't shipped",
+			Tags:     []string{"orders"},
+			Response: Order{},
+		})
+}
@@ -0,0 +1,89 @@
+package vorderapp
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	UserID           string
+	CustomerID       string
+	CustomerName     string
+	Status           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	return queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		UserID:           values.Get("user_id"),
+		CustomerID:       values.Get("customer_id"),
+		CustomerName:     values.Get("customer_name"),
+		Status:           values.Get("status"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+}
+
+func parseFilter(qp queryParams) (vorderbus.QueryFilter, error) {
+	var filter vorderbus.QueryFilter
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return vorderbus.QueryFilter{}, fmt.Errorf("user_id: %w", err)
+		}
+		filter.UserID = &id
+	}
+
+	if qp.CustomerID != "" {
+		id, err := uuid.Parse(qp.CustomerID)
+		if err != nil {
+			return vorderbus.QueryFilter{}, fmt.Errorf("customer_id: %w", err)
+		}
+		filter.CustomerID = &id
+	}
+
+	if qp.CustomerName != "" {
+		filter.CustomerName = &qp.CustomerName
+	}
+
+	if qp.Status != "" {
+		status, err := orderbus.ParseStatus(qp.Status)
+		if err != nil {
+			return vorderbus.QueryFilter{}, fmt.Errorf("status: %w", err)
+		}
+		filter.Status = &status
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		if err != nil {
+			return vorderbus.QueryFilter{}, fmt.Errorf("start_created_date: %w", err)
+		}
+		filter.StartCreatedDate = &t
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		if err != nil {
+			return vorderbus.QueryFilter{}, fmt.Errorf("end_created_date: %w", err)
+		}
+		filter.EndCreatedDate = &t
+	}
+
+	return filter, nil
+}
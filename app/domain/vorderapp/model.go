@@ -0,0 +1,54 @@
+package vorderapp
+
+import (
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/google/uuid"
+)
+
+// Order represents a row of the order view returned from the API.
+type Order struct {
+	ID            string `json:"id"`
+	UserID        string `json:"userID"`
+	CustomerID    string `json:"customerID,omitempty"`
+	CustomerName  string `json:"customerName,omitempty"`
+	CustomerEmail string `json:"customerEmail,omitempty"`
+	Status        string `json:"status"`
+	Currency      string `json:"currency"`
+	Total         int64  `json:"total"`
+	ItemCount     int    `json:"itemCount"`
+	Units         int    `json:"units"`
+	DateCreated   string `json:"dateCreated"`
+	DateUpdated   string `json:"dateUpdated"`
+}
+
+func toAppOrder(ord vorderbus.Order) Order {
+	var customerID string
+	if ord.CustomerID != uuid.Nil {
+		customerID = ord.CustomerID.String()
+	}
+
+	return Order{
+		ID:            ord.ID.String(),
+		UserID:        ord.UserID.String(),
+		CustomerID:    customerID,
+		CustomerName:  ord.CustomerName,
+		CustomerEmail: ord.CustomerEmail,
+		Status:        ord.Status.String(),
+		Currency:      ord.Currency.String(),
+		Total:         ord.Total.Minor(),
+		ItemCount:     ord.ItemCount,
+		Units:         ord.Units,
+		DateCreated:   ord.DateCreated.Format(time.RFC3339),
+		DateUpdated:   ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []vorderbus.Order) []Order {
+	app := make([]Order, len(ords))
+	for i, ord := range ords {
+		app[i] = toAppOrder(ord)
+	}
+	return app
+}
@@ -0,0 +1,12 @@
+package vorderapp
+
+import "github.com/AlmirSai/service/business/domain/vorderbus"
+
+var orderByFields = map[string]string{
+	"order_id":      vorderbus.OrderByID,
+	"customer_name": vorderbus.OrderByCustomerName,
+	"status":        vorderbus.OrderByStatus,
+	"total":         vorderbus.OrderByTotal,
+	"item_count":    vorderbus.OrderByItemCount,
+	"date_created":  vorderbus.OrderByDateCreated,
+}
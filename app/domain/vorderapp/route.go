@@ -0,0 +1,28 @@
+package vorderapp
+
+import (
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	VOrderBus *vorderbus.Business
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	api := newApp(cfg.VOrderBus)
+
+	app.Handle(http.MethodGet, version, "/vorders", api.query).
+		Describe(web.RouteDoc{
+			Summary:  "Queries orders with their customer and item summary",
+			Tags:     []string{"orders"},
+			Response: page.Document[Order]{},
+		})
+}
@@ -0,0 +1,54 @@
+// Package vorderapp maintains the app layer api for the order view.
+package vorderapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type app struct {
+	vorderBus *vorderbus.Business
+}
+
+func newApp(vorderBus *vorderbus.Business) *app {
+	return &app{
+		vorderBus: vorderBus,
+	}
+}
+
+func (a *app) query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	qp := parseQueryParams(r)
+
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, vorderbus.DefaultOrderBy)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ords, err := a.vorderBus.Query(ctx, filter, orderBy, pg)
+	if err != nil {
+		return err
+	}
+
+	total, err := a.vorderBus.Count(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, page.NewDocument(toAppOrders(ords), total, pg), http.StatusOK)
+}
@@ -0,0 +1,166 @@
+// Package capture records sanitized copies of API requests so they can be
+// replayed against another instance of the service.
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces the value of sensitive headers and body fields.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are never written to the capture.
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+}
+
+// sensitiveFields are JSON keys whose values are redacted in captured bodies.
+var sensitiveFields = map[string]bool{
+	"password":        true,
+	"passwordconfirm": true,
+	"token":           true,
+	"secret":          true,
+	"card_number":     true,
+	"cardnumber":      true,
+}
+
+// Record is a single captured request.
+type Record struct {
+	Time    time.Time       `json:"time"`
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Query   string          `json:"query,omitempty"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// Sink receives captured records.
+type Sink interface {
+	Write(r Record) error
+}
+
+// New builds a sanitized record from the request and its already read body.
+// Bodies that aren't valid JSON are dropped since they can't be sanitized.
+func New(r *http.Request, body []byte) Record {
+	rec := Record{
+		Time:    time.Now().UTC(),
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.RawQuery,
+		Headers: make(http.Header),
+	}
+
+	for k, v := range r.Header {
+		if sensitiveHeaders[strings.ToLower(k)] {
+			rec.Headers[k] = []string{redacted}
+			continue
+		}
+		rec.Headers[k] = v
+	}
+
+	if len(body) > 0 {
+		var v any
+		if err := json.Unmarshal(body, &v); err == nil {
+			if b, err := json.Marshal(sanitize(v)); err == nil {
+				rec.Body = b
+			}
+		}
+	}
+
+	return rec
+}
+
+// sanitize walks a decoded JSON document redacting sensitive fields.
+func sanitize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if sensitiveFields[strings.ToLower(k)] {
+				v[k] = redacted
+				continue
+			}
+			v[k] = sanitize(val)
+		}
+		return v
+
+	case []any:
+		for i := range v {
+			v[i] = sanitize(v[i])
+		}
+		return v
+	}
+
+	return v
+}
+
+// =============================================================================
+
+// FileSink appends records as JSON lines to a file.
+type FileSink struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFileSink opens the file for appending, creating it when needed.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open capture file: %w", err)
+	}
+
+	return &FileSink{
+		f:   f,
+		enc: json.NewEncoder(f),
+	}, nil
+}
+
+// Write implements the Sink interface.
+func (s *FileSink) Write(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enc.Encode(r)
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.f.Close()
+}
+
+// =============================================================================
+
+// Read decodes JSON line records from r, calling fn for each one until fn
+// returns an error or the input is exhausted.
+func Read(r io.Reader, fn func(Record) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("decode record: %w", err)
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
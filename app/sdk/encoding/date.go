@@ -0,0 +1,65 @@
+package encoding
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the RFC3339 full-date layout used for dates without a time.
+const DateLayout = time.DateOnly
+
+// Date is a calendar date encoded as "2006-01-02". The time is always
+// midnight UTC.
+type Date struct {
+	time.Time
+}
+
+// NewDate constructs the date of t in its own location.
+func NewDate(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date in the "2006-01-02" layout.
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: want YYYY-MM-DD", value)
+	}
+	return Date{t}, nil
+}
+
+// String returns the date in the "2006-01-02" layout.
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The zero date
+// encodes as null.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return null, nil
+	}
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	s, err := unquote(data, false)
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+
+	date, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = date
+	return nil
+}
@@ -0,0 +1,113 @@
+package encoding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number such as a money amount, held as an
+// integer count of units at a scale: 12.34 is Units 1234 at Scale 2. It
+// encodes as a JSON string and decodes from a string or a number without
+// going through float64.
+type Decimal struct {
+	Units int64
+	Scale int
+}
+
+// NewDecimal constructs a decimal from units at the scale, like an amount
+// in cents at scale 2.
+func NewDecimal(units int64, scale int) Decimal {
+	return Decimal{Units: units, Scale: scale}
+}
+
+// ParseDecimal parses a plain decimal like "-12.340". The scale is the
+// number of fraction digits given.
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+
+	sign := int64(1)
+	digits := value
+	if s, ok := strings.CutPrefix(digits, "-"); ok {
+		sign = -1
+		digits = s
+	}
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.ContainsAny(whole+frac, "+-eE") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	return Decimal{Units: sign * units, Scale: len(frac)}, nil
+}
+
+// Rescale returns the units of the decimal at the scale. Trailing zeros are
+// dropped as needed but significant digits never are, so "12.340" rescales
+// to 1234 at scale 2 and "12.345" fails.
+func (d Decimal) Rescale(scale int) (int64, error) {
+	units := d.Units
+
+	for s := d.Scale; s > scale; s-- {
+		if units%10 != 0 {
+			return 0, fmt.Errorf("decimal %s: more than %d decimal places", d, scale)
+		}
+		units /= 10
+	}
+
+	for s := d.Scale; s < scale; s++ {
+		next := units * 10
+		if next/10 != units {
+			return 0, fmt.Errorf("decimal %s: out of range", d)
+		}
+		units = next
+	}
+
+	return units, nil
+}
+
+// String returns the decimal with exactly Scale fraction digits.
+func (d Decimal) String() string {
+	if d.Scale <= 0 {
+		return strconv.FormatInt(d.Units, 10)
+	}
+
+	sign := ""
+	units := strconv.FormatInt(d.Units, 10)
+	if s, ok := strings.CutPrefix(units, "-"); ok {
+		sign = "-"
+		units = s
+	}
+
+	if len(units) <= d.Scale {
+		units = strings.Repeat("0", d.Scale-len(units)+1) + units
+	}
+
+	cut := len(units) - d.Scale
+	return sign + units[:cut] + "." + units[cut:]
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data, true)
+	if err != nil {
+		return fmt.Errorf("decimal: %w", err)
+	}
+
+	dec, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+
+	*d = dec
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package encoding provides the JSON representations shared by the app layer
+// models for dates, decimal amounts and enumerations, so every API spells
+// them the same way and rejects bad values while decoding.
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// null is the JSON literal for a missing value.
+var null = []byte("null")
+
+// unquote returns the contents of a JSON string. A JSON number is returned
+// as is when numbers are allowed, so decimals never pass through a float.
+func unquote(data []byte, numbers bool) (string, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	if numbers && len(data) > 0 && (data[0] == '-' || (data[0] >= '0' && data[0] <= '9')) {
+		return string(data), nil
+	}
+
+	return "", fmt.Errorf("unexpected JSON value %s", data)
+}
@@ -0,0 +1,69 @@
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Parser parses the name of an enumeration value. It's implemented by an
+// empty struct in the app package that owns the enumeration, delegating to
+// the parse function of the business type.
+type Parser[T any] interface {
+	Parse(value string) (T, error)
+}
+
+// Enum is a business enumeration, like an order status, as it's carried in
+// API models. It encodes as its name and fails to decode unknown names, so
+// bad values are rejected before they reach the handler.
+type Enum[T fmt.Stringer, P Parser[T]] struct {
+	Value T
+	set   bool
+}
+
+// NewEnum wraps the value.
+func NewEnum[T fmt.Stringer, P Parser[T]](value T) Enum[T, P] {
+	return Enum[T, P]{Value: value, set: true}
+}
+
+// IsZero reports whether no value was provided.
+func (e Enum[T, P]) IsZero() bool {
+	return !e.set
+}
+
+// String returns the name of the value.
+func (e Enum[T, P]) String() string {
+	if !e.set {
+		return ""
+	}
+	return e.Value.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Enum[T, P]) MarshalJSON() ([]byte, error) {
+	if !e.set {
+		return null, nil
+	}
+	return json.Marshal(e.Value.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Enum[T, P]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*e = Enum[T, P]{}
+		return nil
+	}
+
+	s, err := unquote(data, false)
+	if err != nil {
+		return fmt.Errorf("enum: %w", err)
+	}
+
+	var p P
+	v, err := p.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*e = Enum[T, P]{Value: v, set: true}
+	return nil
+}
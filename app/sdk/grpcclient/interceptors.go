@@ -0,0 +1,213 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/AlmirSai/service/foundation/retry"
+	otelglobal "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryDeadline applies the deadline to calls whose context has none.
+func UnaryDeadline(d time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryTracing starts a client span for the call and propagates the trace
+// to the server in the metadata.
+func UnaryTracing(name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := otel.AddSpan(ctx, method,
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("peer.service", name),
+		)
+		defer span.End()
+
+		err := invoker(injectTrace(ctx), method, req, reply, cc, opts...)
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+
+		return err
+	}
+}
+
+// UnaryLogging logs every call with its status code and duration.
+func UnaryLogging(log *logger.Logger, name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		logCall(ctx, log, name, method, start, err)
+
+		return err
+	}
+}
+
+// UnaryRetry retries calls that failed with a status indicating the server
+// may answer differently on another attempt.
+func UnaryRetry(cfg retry.Config) grpc.UnaryClientInterceptor {
+	cfg.RetryIf = retryable
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return retry.Do(ctx, cfg, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// UnaryAuth adds the token to the authorization metadata of every call.
+func UnaryAuth(token TokenFunc) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := withToken(ctx, token)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// =============================================================================
+
+// StreamTracing starts a client span for the stream and propagates the trace
+// to the server in the metadata. The span covers setting up the stream.
+func StreamTracing(name string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := otel.AddSpan(ctx, method,
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("peer.service", name),
+		)
+		defer span.End()
+
+		cs, err := streamer(injectTrace(ctx), desc, cc, method, opts...)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, status.Code(err).String())
+		}
+
+		return cs, err
+	}
+}
+
+// StreamLogging logs the outcome of setting up every stream.
+func StreamLogging(log *logger.Logger, name string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+
+		logCall(ctx, log, name, method, start, err)
+
+		return cs, err
+	}
+}
+
+// StreamAuth adds the token to the authorization metadata of every stream.
+func StreamAuth(token TokenFunc) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := withToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// =============================================================================
+
+// logCall writes the structured log line for a call.
+func logCall(ctx context.Context, log *logger.Logger, name string, method string, start time.Time, err error) {
+	if log == nil {
+		return
+	}
+
+	code := status.Code(err)
+	if err != nil {
+		log.Warn(ctx, "grpc client", "name", name, "method", method, "code", code.String(),
+			"duration", time.Since(start).String(), "error", err)
+		return
+	}
+
+	log.Info(ctx, "grpc client", "name", name, "method", method, "code", code.String(),
+		"duration", time.Since(start).String())
+}
+
+// retryable reports whether a failed call is worth repeating.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// withToken adds the bearer token to the outgoing metadata.
+func withToken(ctx context.Context, token TokenFunc) (context.Context, error) {
+	t, err := token(ctx)
+	if err != nil {
+		return ctx, status.Errorf(codes.Unauthenticated, "token: %s", err)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+t), nil
+}
+
+// injectTrace writes the trace context of ctx into the outgoing metadata.
+func injectTrace(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	} else {
+		md = md.Copy()
+	}
+
+	otelglobal.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// metadataCarrier adapts gRPC metadata to the otel text map carrier.
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	if v := metadata.MD(mc).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (mc metadataCarrier) Set(key string, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
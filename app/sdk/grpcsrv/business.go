@@ -0,0 +1,35 @@
+package grpcsrv
+
+import (
+	"context"
+	"strings"
+
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"google.golang.org/grpc"
+)
+
+// UnaryBusiness runs unary handlers through the business middleware chain,
+// with the full method name as the operation. The gRPC infrastructure
+// services, like health and reflection, bypass the chain.
+func UnaryBusiness(mw ...bmid.Middleware) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, "/grpc.") {
+			return handler(ctx, req)
+		}
+
+		ctx = bmid.WithOperation(ctx, bmid.Operation{
+			Name: info.FullMethod,
+		})
+
+		var resp any
+		next := func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		}
+
+		err := bmid.Chain(next, mw...)(ctx)
+
+		return resp, err
+	}
+}
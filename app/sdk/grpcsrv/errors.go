@@ -0,0 +1,78 @@
+package grpcsrv
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryErrors translates errors returned by unary handlers into gRPC status
+// errors the same way the web error middleware does for HTTP. Errors that
+// already carry a status are left alone and anything without a code is
+// reported as Internal without exposing its message.
+func UnaryErrors(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, toStatus(ctx, log, info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamErrors is the streaming counterpart of UnaryErrors.
+func StreamErrors(log *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatus(ss.Context(), log, info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+// toStatus logs the error and converts it into a gRPC status error.
+func toStatus(ctx context.Context, log *logger.Logger, method string, err error) error {
+	log.Error(ctx, "grpc", "method", method, "msg", err)
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr := errs.GetError(err)
+	if appErr == nil || appErr.Code == errs.Internal {
+		return status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	st := status.New(appErr.Code.GRPCCode(), appErr.Error())
+	if len(appErr.Fields) == 0 {
+		return st.Err()
+	}
+
+	// The fields at fault travel as BadRequest details like the fields of the
+	// HTTP error document.
+	br := errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(appErr.Fields)),
+	}
+	for _, field := range slices.Sorted(maps.Keys(appErr.Fields)) {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: appErr.Fields[field],
+		})
+	}
+
+	detailed, err := st.WithDetails(&br)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,120 @@
+// Package grpcsrv constructs the gRPC server used by the services with the
+// standard health checking and reflection services registered.
+package grpcsrv
+
+import (
+	"context"
+	"time"
+
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/foundation/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// ReadyFunc reports whether the service is ready to receive traffic.
+type ReadyFunc func(ctx context.Context) error
+
+// Config contains the settings for the server.
+type Config struct {
+	Log           *logger.Logger
+	Ready         ReadyFunc
+	CheckInterval time.Duration // How often readiness is evaluated, defaults to 5s
+	Options       []grpc.ServerOption
+	Business      []bmid.Middleware // Business middleware run for every application call
+}
+
+// Server wraps a grpc.Server and keeps the health status in sync with the
+// readiness checks.
+type Server struct {
+	*grpc.Server
+	log      *logger.Logger
+	ready    ReadyFunc
+	interval time.Duration
+	health   *health.Server
+}
+
+// New constructs a gRPC server with the grpc.health.v1 and reflection
+// services registered and coded errors translated into status codes. Application services can be registered on the
+// embedded grpc.Server before serving.
+func New(cfg Config) *Server {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Second
+	}
+
+	opts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryErrors(cfg.Log), UnaryBusiness(cfg.Business...)),
+		grpc.ChainStreamInterceptor(StreamErrors(cfg.Log)),
+	}, cfg.Options...)
+
+	srv := grpc.NewServer(opts...)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+
+	reflection.Register(srv)
+
+	return &Server{
+		Server:   srv,
+		log:      cfg.Log,
+		ready:    cfg.Ready,
+		interval: cfg.CheckInterval,
+		health:   hs,
+	}
+}
+
+// WatchReadiness evaluates the readiness checks on an interval and updates
+// the health status of every registered service until the context is done.
+func (s *Server) WatchReadiness(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var serving bool
+
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if s.ready != nil {
+			checkCtx, cancel := context.WithTimeout(ctx, time.Second)
+			err := s.ready(checkCtx)
+			cancel()
+
+			if err != nil {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				if serving {
+					s.log.Warn(ctx, "grpc health", "status", "not serving", "error", err)
+				}
+			}
+		}
+		serving = status == healthpb.HealthCheckResponse_SERVING
+
+		s.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GracefulStop marks every service as not serving, so load balancers drain
+// traffic, and then stops the server gracefully.
+func (s *Server) GracefulStop() {
+	s.health.Shutdown()
+	s.Server.GracefulStop()
+}
+
+// setStatus applies the status to the overall server and each service.
+func (s *Server) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", status)
+
+	for name := range s.Server.GetServiceInfo() {
+		if name == healthpb.Health_ServiceDesc.ServiceName {
+			continue
+		}
+		s.health.SetServingStatus(name, status)
+	}
+}
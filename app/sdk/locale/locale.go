@@ -0,0 +1,27 @@
+// Package locale holds the message catalogs of the API and builds the i18n
+// bundle that serves them.
+package locale
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/AlmirSai/service/foundation/i18n"
+)
+
+// Fallback is the language used when the client accepts none we have.
+const Fallback = "en"
+
+//go:embed catalog/*.json
+var catalogs embed.FS
+
+// New constructs a bundle with the embedded catalogs.
+func New() (*i18n.Bundle, error) {
+	bundle := i18n.New(Fallback)
+
+	if err := bundle.LoadFS(catalogs, "catalog"); err != nil {
+		return nil, fmt.Errorf("load catalogs: %w", err)
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,82 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claims are the claims of the tokens minted by the admin tool.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// Authenticate verifies the bearer token of the request against the public
+// keys of the key store and stores the caller's claims in the context for
+// the business authorizer. Requests without a token are passed on without
+// claims, it's up to the authorizer whether the operation is public. An
+// empty issuer accepts tokens from any issuer.
+func Authenticate(ks *keystore.KeyStore, issuer string) web.Middleware {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+
+	parser := jwt.NewParser(opts...)
+
+	keyFunc := func(t *jwt.Token) (any, error) {
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("missing kid in token header")
+		}
+
+		publicPEM, err := ks.PublicKey(kid)
+		if err != nil {
+			return nil, fmt.Errorf("kid %q: %w", kid, err)
+		}
+
+		return jwt.ParseRSAPublicKeyFromPEM([]byte(publicPEM))
+	}
+
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				return handler(ctx, w, r)
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				return errs.Newf(errs.Unauthenticated, "expected authorization header format: Bearer <token>")
+			}
+
+			var c claims
+			if _, err := parser.ParseWithClaims(token, &c, keyFunc); err != nil {
+				return errs.Newf(errs.Unauthenticated, "invalid token: %s", err)
+			}
+
+			ctx = bmid.WithClaims(ctx, bmid.Claims{
+				Subject: c.Subject,
+				Roles:   c.Roles,
+			})
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,120 @@
+package mid_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/app/sdk/mid"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/foundation/keystore"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuer = "service project"
+
+func Test_Authorize(t *testing.T) {
+	t.Parallel()
+
+	ks := keystore.New()
+
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("should be able to generate a key: %s", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatalf("should be able to marshal the key: %s", err)
+	}
+
+	if err := ks.Add("test", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))); err != nil {
+		t.Fatalf("should be able to add the key: %s", err)
+	}
+
+	authorizer := bmid.NewRoleAuthorizer(bmid.Roles{
+		Public: []string{"GET /v1/public"},
+		Read:   []string{"ADMIN", "USER"},
+		Write:  []string{"ADMIN"},
+	})
+
+	app := web.NewApp(nil, nil,
+		mid.Errors(logger.NewNop()),
+		mid.Authenticate(ks, issuer),
+		mid.Business(bmid.Authorize(authorizer)),
+	)
+
+	ok := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	}
+	app.Handle(http.MethodGet, "v1", "/public", ok)
+	app.Handle(http.MethodGet, "v1", "/things", ok)
+	app.Handle(http.MethodPost, "v1", "/things", ok)
+
+	token := func(iss string, roles ...string) string {
+		c := struct {
+			jwt.RegisteredClaims
+			Roles []string `json:"roles"`
+		}{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+				Issuer:    iss,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+			Roles: roles,
+		}
+
+		tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+		tkn.Header["kid"] = "test"
+
+		signed, err := tkn.SignedString(pk)
+		if err != nil {
+			t.Fatalf("should be able to sign the token: %s", err)
+		}
+
+		return "Bearer " + signed
+	}
+
+	table := []struct {
+		name   string
+		method string
+		url    string
+		auth   string
+		status int
+	}{
+		{name: "public", method: http.MethodGet, url: "/v1/public", status: http.StatusNoContent},
+		{name: "no-token", method: http.MethodGet, url: "/v1/things", status: http.StatusUnauthorized},
+		{name: "not-bearer", method: http.MethodGet, url: "/v1/things", auth: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized},
+		{name: "bad-signature", method: http.MethodGet, url: "/v1/things", auth: token(issuer, "ADMIN") + "x", status: http.StatusUnauthorized},
+		{name: "wrong-issuer", method: http.MethodGet, url: "/v1/things", auth: token("someone else", "ADMIN"), status: http.StatusUnauthorized},
+		{name: "user-read", method: http.MethodGet, url: "/v1/things", auth: token(issuer, "USER"), status: http.StatusNoContent},
+		{name: "user-write", method: http.MethodPost, url: "/v1/things", auth: token(issuer, "USER"), status: http.StatusForbidden},
+		{name: "no-roles", method: http.MethodGet, url: "/v1/things", auth: token(issuer), status: http.StatusForbidden},
+		{name: "admin-write", method: http.MethodPost, url: "/v1/things", auth: token(issuer, "ADMIN"), status: http.StatusNoContent},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("should receive a status code of %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package mid
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Business runs the handler through the business middleware chain, with the
+// route pattern as the operation. Requests with safe methods are marked read
+// only. It must run inside the error middleware so failures of the chain are
+// turned into responses.
+//
+// The response of an operation that changes state is held back until the
+// chain returns, so a client never sees success for a transaction that was
+// rolled back afterwards.
+func Business(mw ...bmid.Middleware) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			op := bmid.Operation{
+				Name:     r.Pattern,
+				ReadOnly: r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions,
+			}
+			ctx = bmid.WithOperation(ctx, op)
+
+			if op.ReadOnly {
+				next := func(ctx context.Context) error {
+					return handler(ctx, w, r)
+				}
+
+				return bmid.Chain(next, mw...)(ctx)
+			}
+
+			bw := bufferedWriter{header: w.Header().Clone()}
+
+			next := func(ctx context.Context) error {
+				return handler(ctx, &bw, r)
+			}
+
+			if err := bmid.Chain(next, mw...)(ctx); err != nil {
+				return err
+			}
+
+			return bw.flush(w)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// bufferedWriter holds a response in memory until it is flushed.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (bw *bufferedWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedWriter) WriteHeader(statusCode int) {
+	if bw.status == 0 {
+		bw.status = statusCode
+	}
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	bw.WriteHeader(http.StatusOK)
+	return bw.body.Write(b)
+}
+
+// flush sends the held response to w. Nothing is written when the handler
+// wrote nothing, leaving the default status to the server.
+func (bw *bufferedWriter) flush(w http.ResponseWriter) error {
+	for k, v := range bw.header {
+		w.Header()[k] = v
+	}
+
+	if bw.status == 0 {
+		return nil
+	}
+
+	w.WriteHeader(bw.status)
+
+	if _, err := w.Write(bw.body.Bytes()); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package mid_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlmirSai/service/app/sdk/mid"
+	bmid "github.com/AlmirSai/service/business/sdk/mid"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+func Test_BusinessHoldsResponse(t *testing.T) {
+	t.Parallel()
+
+	// commit fails after the handler ran, like a transaction that can't be
+	// committed.
+	commit := func(next bmid.HandlerFunc) bmid.HandlerFunc {
+		h := func(ctx context.Context) error {
+			if err := next(ctx); err != nil {
+				return err
+			}
+
+			if bmid.GetOperation(ctx).Name == "POST /v1/fail" {
+				return errs.New(errs.Internal, errors.New("commit failed"))
+			}
+
+			return nil
+		}
+
+		return h
+	}
+
+	app := web.NewApp(nil, nil, mid.Errors(logger.NewNop()), mid.Business(commit))
+
+	created := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Location", "/v1/things/1")
+		return web.Respond(ctx, w, map[string]string{"id": "1"}, http.StatusCreated)
+	}
+	app.Handle(http.MethodPost, "v1", "/ok", created)
+	app.Handle(http.MethodPost, "v1", "/fail", created)
+
+	table := []struct {
+		name     string
+		url      string
+		status   int
+		location string
+		body     string
+	}{
+		{name: "commit", url: "/v1/ok", status: http.StatusCreated, location: "/v1/things/1", body: `{"id":"1"}`},
+		{name: "rollback", url: "/v1/fail", status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range table {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.url, nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s: should receive a status code of %d, got %d", tt.name, tt.status, w.Code)
+		}
+
+		if got := w.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s: should receive a location of %q, got %q", tt.name, tt.location, got)
+		}
+
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: should receive a body of %s, got %s", tt.name, tt.body, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package mid
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/AlmirSai/service/app/sdk/capture"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// maxCaptureBody is the largest request body copied into a capture record.
+const maxCaptureBody = 1 << 20
+
+// Capture records a sanitized copy of the given percentage of requests into
+// the sink. The request body is buffered and restored for the handler.
+func Capture(log *logger.Logger, sink capture.Sink, percent float64) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			if rand.Float64() >= percent {
+				return handler(ctx, w, r)
+			}
+
+			var body []byte
+			if r.Body != nil && r.ContentLength <= maxCaptureBody {
+				b, err := io.ReadAll(io.LimitReader(r.Body, maxCaptureBody))
+				if err != nil {
+					return err
+				}
+
+				// Put back what was read in front of anything left unread.
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(b), r.Body), r.Body}
+
+				// A body filling the limit may be truncated and is left out.
+				if len(b) < maxCaptureBody {
+					body = b
+				}
+			}
+
+			if err := sink.Write(capture.New(r, body)); err != nil {
+				log.Warn(ctx, "capture", "status", "failed to record request", "error", err)
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,133 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// ChaosRule describes the faults injected into requests whose path starts
+// with Path. Percent selects the share of matching traffic that is affected,
+// the remaining rates apply to the affected requests.
+type ChaosRule struct {
+	Path      string
+	Percent   float64
+	Latency   time.Duration
+	ErrorRate float64
+	Status    int
+	DropRate  float64
+}
+
+// ParseChaosRules parses rules in the form
+// "path=/v1/orders,percent=0.2,latency=300ms,error=0.1,status=503,drop=0.05"
+// where multiple rules are separated by a semicolon.
+func ParseChaosRules(s string) ([]ChaosRule, error) {
+	var rules []ChaosRule
+
+	for raw := range strings.SplitSeq(s, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		rule := ChaosRule{
+			Percent: 1,
+			Status:  http.StatusServiceUnavailable,
+		}
+
+		for pair := range strings.SplitSeq(raw, ",") {
+			k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("chaos rule %q: expected key=value, got %q", raw, pair)
+			}
+
+			var err error
+			switch k {
+			case "path":
+				rule.Path = v
+			case "percent":
+				rule.Percent, err = strconv.ParseFloat(v, 64)
+			case "latency":
+				rule.Latency, err = time.ParseDuration(v)
+			case "error":
+				rule.ErrorRate, err = strconv.ParseFloat(v, 64)
+			case "status":
+				rule.Status, err = strconv.Atoi(v)
+			case "drop":
+				rule.DropRate, err = strconv.ParseFloat(v, 64)
+			default:
+				err = errors.New("unknown key")
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("chaos rule %q: key %q: %w", raw, k, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Chaos injects latency, errors and dropped connections into the requests
+// matching the rules. The first matching rule wins. It must never be
+// installed in production.
+func Chaos(log *logger.Logger, rules []ChaosRule) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			rule, ok := matchChaos(rules, r.URL.Path)
+			if !ok || rand.Float64() >= rule.Percent {
+				return handler(ctx, w, r)
+			}
+
+			if rule.Latency > 0 {
+				select {
+				case <-time.After(rule.Latency):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			switch n := rand.Float64(); {
+			case n < rule.DropRate:
+				log.Warn(ctx, "chaos", "fault", "drop", "path", r.URL.Path)
+
+				conn, _, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					return err
+				}
+				return conn.Close()
+
+			case n < rule.DropRate+rule.ErrorRate:
+				log.Warn(ctx, "chaos", "fault", "error", "path", r.URL.Path, "status", rule.Status)
+
+				return web.NewError(errors.New("chaos: injected failure"), rule.Status)
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// matchChaos returns the first rule whose path is a prefix of the request path.
+func matchChaos(rules []ChaosRule, path string) (ChaosRule, bool) {
+	for _, rule := range rules {
+		if strings.HasPrefix(path, rule.Path) {
+			return rule, true
+		}
+	}
+	return ChaosRule{}, false
+}
@@ -0,0 +1,113 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/i18n"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Errors handles errors coming out of the call chain. It detects normal
+// application errors which are used to respond to the client in a uniform way.
+// Coded errors from the errs package respond with the status for their code
+// and the fields they name, unless a web.Error wrapping them sets the status.
+// Unexpected errors (status >= 500) are logged. Messages are localized when
+// the Localize middleware has run.
+func Errors(log *logger.Logger) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			err := handler(ctx, w, r)
+			if err == nil {
+				return nil
+			}
+
+			loc := i18n.FromContext(ctx)
+
+			var er web.ErrorDocument
+			var status int
+
+			switch {
+			case web.IsFieldErrors(err):
+				fieldErrors := web.GetFieldErrors(err)
+				er = web.ErrorDocument{
+					Error:  translate(loc, "error.validation", "data validation error", nil),
+					Fields: localizeFields(loc, fieldErrors),
+				}
+				status = http.StatusBadRequest
+
+			case web.IsError(err):
+				reqErr := web.GetError(err)
+				er = web.ErrorDocument{
+					Error: reqErr.Error(),
+				}
+				status = reqErr.Status
+
+			case errs.IsError(err):
+				appErr := errs.GetError(err)
+				status = appErr.Code.HTTPStatus()
+				msg := appErr.Error()
+				if appErr.Code == errs.Internal {
+					msg = translate(loc, "error.internal", http.StatusText(status), nil)
+				}
+				er = web.ErrorDocument{
+					Error: msg,
+				}
+				if appErr.Code != errs.Internal {
+					er.Fields = appErr.Fields
+				}
+
+			default:
+				er = web.ErrorDocument{
+					Error: translate(loc, "error.internal", http.StatusText(http.StatusInternalServerError), nil),
+				}
+				status = http.StatusInternalServerError
+			}
+
+			if status >= http.StatusInternalServerError {
+				log.Error(ctx, "message", "msg", err)
+			}
+
+			if err := web.Respond(ctx, w, er, status); err != nil {
+				return err
+			}
+
+			// If we receive the shutdown err we need to return it
+			// back to the base handler to shut down the service.
+			if web.IsShutdown(err) {
+				return err
+			}
+
+			return nil
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// translate returns the localized message for the key, or the default when
+// there's no localizer or no catalog has the key.
+func translate(loc *i18n.Localizer, key string, def string, args i18n.Args) string {
+	if msg, ok := loc.Translate(key, args); ok {
+		return msg
+	}
+	return def
+}
+
+// localizeFields returns the field messages, localizing the ones produced by
+// a validation rule the catalogs know.
+func localizeFields(loc *i18n.Localizer, fe web.FieldErrors) map[string]string {
+	m := make(map[string]string, len(fe))
+	for _, fld := range fe {
+		msg := fld.Err
+		if fld.Rule != "" {
+			msg = translate(loc, "validate."+fld.Rule, fld.Err, i18n.Args{"field": fld.Field, "param": fld.Param})
+		}
+		m[fld.Field] = msg
+	}
+	return m
+}
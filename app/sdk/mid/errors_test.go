@@ -0,0 +1,120 @@
+package mid_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlmirSai/service/app/sdk/mid"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/testutil"
+	"github.com/AlmirSai/service/foundation/validate"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+type newThing struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (app newThing) Validate() error {
+	return validate.Check(app)
+}
+
+func Test_Errors(t *testing.T) {
+	t.Parallel()
+
+	app := web.NewApp(nil, nil, mid.Errors(logger.NewNop()))
+
+	app.Handle(http.MethodPost, "v1", "/things", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var nt newThing
+		if err := web.Decode(r, &nt); err != nil {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return web.Respond(ctx, w, nt, http.StatusCreated)
+	})
+
+	app.Handle(http.MethodGet, "v1", "/things/missing", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return errs.Newf(errs.NotFound, "thing not found")
+	})
+
+	app.Handle(http.MethodGet, "v1", "/things/internal", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return errs.Newf(errs.Internal, "connection to the database lost")
+	})
+
+	app.Handle(http.MethodGet, "v1", "/things/unexpected", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return errors.New("something the client must not see")
+	})
+
+	app.Handle(http.MethodGet, "v1", "/things/teapot", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.NewError(errors.New("short and stout"), http.StatusTeapot)
+	})
+
+	table := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+	}{
+		{name: "created", method: http.MethodPost, url: "/v1/things", body: `{"name":"bill","email":"bill@example.com"}`, status: http.StatusCreated},
+		{name: "validation", method: http.MethodPost, url: "/v1/things", body: `{"email":"not an email"}`, status: http.StatusBadRequest},
+		{name: "not-found", method: http.MethodGet, url: "/v1/things/missing", status: http.StatusNotFound},
+		{name: "internal", method: http.MethodGet, url: "/v1/things/internal", status: http.StatusInternalServerError},
+		{name: "unexpected", method: http.MethodGet, url: "/v1/things/unexpected", status: http.StatusInternalServerError},
+		{name: "web-error", method: http.MethodGet, url: "/v1/things/teapot", status: http.StatusTeapot},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			app.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("should receive a status code of %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+
+			testutil.GoldenJSON(t, "errors-"+tt.name, w.Body.Bytes(), testutil.Volatile...)
+		})
+	}
+}
+
+func Test_ErrorsLogsUnexpected(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name   string
+		err    error
+		logged bool
+	}{
+		{name: "not-found", err: errs.Newf(errs.NotFound, "thing not found")},
+		{name: "validation", err: errs.Newf(errs.InvalidArgument, "bad thing")},
+		{name: "internal", err: errs.Newf(errs.Internal, "connection to the database lost"), logged: true},
+		{name: "unexpected", err: errors.New("something broke"), logged: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(&buf, logger.LevelInfo, "TEST", nil)
+
+			app := web.NewApp(nil, nil, mid.Errors(log))
+			app.Handle(http.MethodGet, "v1", "/things", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+
+			app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/things", nil))
+
+			if got := strings.Contains(buf.String(), tt.err.Error()); got != tt.logged {
+				t.Errorf("should log the error %t, got %t:\n%s", tt.logged, got, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/i18n"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Localize negotiates the language of the request from its Accept-Language
+// header and stores the localizer in the context for the handlers and the
+// error middleware. It must run outside the error middleware.
+func Localize(bundle *i18n.Bundle) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			loc := bundle.Localizer(r.Header.Values("Accept-Language")...)
+
+			if lang := loc.Language(); lang != "" {
+				w.Header().Set("Content-Language", lang)
+			}
+
+			return handler(i18n.WithLocalizer(ctx, loc), w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
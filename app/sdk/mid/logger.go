@@ -0,0 +1,43 @@
+// Package mid provides app level middleware support.
+package mid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Logger writes information about the request to the logs and puts the
+// logger in the request context for logger.FromContext.
+func Logger(log *logger.Logger) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			v := web.GetValues(ctx)
+
+			path := r.URL.Path
+			if r.URL.RawQuery != "" {
+				path = fmt.Sprintf("%s?%s", path, r.URL.RawQuery)
+			}
+
+			log.Info(ctx, "request started", "method", r.Method, "path", path, "remoteaddr", r.RemoteAddr)
+
+			// Make the logger available to code that has no *Logger of its own.
+			ctx = logger.IntoContext(ctx, log)
+
+			err := handler(ctx, w, r)
+
+			log.Info(ctx, "request completed", "method", r.Method, "path", path, "remoteaddr", r.RemoteAddr,
+				"statuscode", v.StatusCode, "since", time.Since(v.Now).String())
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
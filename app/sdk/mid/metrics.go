@@ -0,0 +1,39 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Metrics counts requests and records their latency by route and status.
+// It must run outside the error middleware so the final status is known.
+func Metrics(provider metrics.Provider) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			err := handler(ctx, w, r)
+
+			v := web.GetValues(ctx)
+
+			labels := metrics.Labels{
+				"method": r.Method,
+				"route":  r.Pattern,
+				"status": strconv.Itoa(v.StatusCode),
+			}
+
+			provider.Counter("http_requests_total", "Requests handled.", labels).Inc()
+			provider.Histogram("http_request_duration_seconds", "Time spent handling requests.", metrics.DefaultBuckets, labels).
+				Observe(time.Since(v.Now).Seconds())
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,66 @@
+package mid
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/i18n"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/ratelimit"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// RateLimitKeyFunc returns the key a request is counted against.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// ClientIP counts requests against the address of the client.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RateLimit rejects requests with a 429 once the key of the request has
+// used up its allowance. The limit headers are set on every response. When
+// the limiter fails the request is let through, since the limiter shouldn't
+// take the service down with it.
+func RateLimit(log *logger.Logger, limiter ratelimit.Limiter, key RateLimitKeyFunc) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			k := key(r)
+
+			res, err := limiter.Allow(ctx, k)
+			if err != nil {
+				log.Warn(ctx, "rate limit", "status", "limiter failed, allowing request", "key", k, "error", err)
+				return handler(ctx, w, r)
+			}
+
+			if res.Limit > 0 {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			}
+
+			if !res.Allowed {
+				secs := int(math.Ceil(res.RetryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(secs))
+
+				if msg, ok := i18n.FromContext(ctx).TranslatePlural("error.rate_limited", secs, nil); ok {
+					return errs.Newf(errs.ResourceExhausted, "%s", msg)
+				}
+				return errs.Newf(errs.ResourceExhausted, "rate limit exceeded, retry later")
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
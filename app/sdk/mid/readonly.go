@@ -0,0 +1,35 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// ReadOnly rejects the requests that change state while enabled reports
+// true, for example during database maintenance. Requests with safe methods
+// are still served. It's checked on every request so it can be switched at
+// runtime.
+func ReadOnly(enabled func() bool) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return handler(ctx, w, r)
+			}
+
+			if enabled() {
+				w.Header().Set("Retry-After", "60")
+				return errs.Newf(errs.Unavailable, "service is read only")
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
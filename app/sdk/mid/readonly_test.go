@@ -0,0 +1,50 @@
+package mid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AlmirSai/service/app/sdk/mid"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+func Test_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	var readOnly atomic.Bool
+
+	app := web.NewApp(nil, nil, mid.Errors(logger.NewNop()), mid.ReadOnly(readOnly.Load))
+
+	ok := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	}
+	app.Handle(http.MethodGet, "v1", "/things", ok)
+	app.Handle(http.MethodPost, "v1", "/things", ok)
+
+	table := []struct {
+		name     string
+		readOnly bool
+		method   string
+		status   int
+	}{
+		{name: "off-get", method: http.MethodGet, status: http.StatusNoContent},
+		{name: "off-post", method: http.MethodPost, status: http.StatusNoContent},
+		{name: "on-get", readOnly: true, method: http.MethodGet, status: http.StatusNoContent},
+		{name: "on-post", readOnly: true, method: http.MethodPost, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range table {
+		readOnly.Store(tt.readOnly)
+
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(tt.method, "/v1/things", nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s: should receive a status code of %d, got %d", tt.name, tt.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime/debug"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Priority decides which requests are rejected first under load.
+type Priority int
+
+// Set of request priorities, from first to last to be shed.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityCritical
+)
+
+// ShedConfig holds the thresholds used by the load shedder.
+type ShedConfig struct {
+	MaxInFlight     int                 // Requests served concurrently before queuing
+	MaxQueueWait    time.Duration       // Longest a normal request waits for a slot
+	MemoryThreshold float64             // Heap share of GOMEMLIMIT above which low priority is shed
+	Priorities      map[string]Priority // Path prefix to priority, longest prefix wins
+}
+
+// ParseShedPriorities parses priorities in the form
+// "/v1/docs=low,/v1/orders=critical".
+func ParseShedPriorities(s string) (map[string]Priority, error) {
+	prios := make(map[string]Priority)
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		path, name, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("shed priority %q: expected path=priority", pair)
+		}
+
+		switch strings.ToLower(name) {
+		case "low":
+			prios[path] = PriorityLow
+		case "normal":
+			prios[path] = PriorityNormal
+		case "critical":
+			prios[path] = PriorityCritical
+		default:
+			return nil, fmt.Errorf("shed priority %q: unknown priority %q", pair, name)
+		}
+	}
+
+	return prios, nil
+}
+
+// Shed rejects requests with a 503 before the service is overwhelmed. Low
+// priority requests are shed as soon as every slot is taken, the average
+// queue wait climbs or memory is under pressure. Normal requests wait up to
+// MaxQueueWait for a slot. Critical requests are always admitted.
+func Shed(log *logger.Logger, cfg ShedConfig) web.Middleware {
+	s := newShedder(cfg)
+
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			prio := s.priority(r.URL.Path)
+
+			release, reason := s.admit(ctx, prio)
+			if reason != "" {
+				log.Warn(ctx, "load shed", "reason", reason, "path", r.URL.Path, "priority", prio, "inflight", len(s.slots))
+
+				w.Header().Set("Retry-After", "1")
+				return web.NewError(errors.New("service overloaded, retry later"), http.StatusServiceUnavailable)
+			}
+			defer release()
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// =============================================================================
+
+// shedder tracks the state used to make admission decisions.
+type shedder struct {
+	cfg   ShedConfig
+	slots chan struct{}
+
+	waitMu  sync.Mutex
+	avgWait time.Duration // Moving average of the time spent queuing
+
+	memAt    atomic.Int64  // Unix nanos of the last memory sample
+	memRatio atomic.Uint64 // Float64 bits of heap / GOMEMLIMIT
+}
+
+func newShedder(cfg ShedConfig) *shedder {
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 1000
+	}
+
+	if cfg.MemoryThreshold <= 0 {
+		cfg.MemoryThreshold = 0.9
+	}
+
+	return &shedder{
+		cfg:   cfg,
+		slots: make(chan struct{}, cfg.MaxInFlight),
+	}
+}
+
+// priority returns the priority of the longest matching path prefix.
+func (s *shedder) priority(path string) Priority {
+	prio := PriorityNormal
+	best := -1
+
+	for prefix, p := range s.cfg.Priorities {
+		if strings.HasPrefix(path, prefix) && len(prefix) > best {
+			prio = p
+			best = len(prefix)
+		}
+	}
+
+	return prio
+}
+
+// admit decides whether the request may proceed. It returns a release
+// function on success or a non-empty reason when the request is shed.
+func (s *shedder) admit(ctx context.Context, prio Priority) (func(), string) {
+	noop := func() {}
+	release := func() { <-s.slots }
+
+	if prio == PriorityCritical {
+		return noop, ""
+	}
+
+	if prio == PriorityLow {
+		if s.memoryPressure() {
+			return nil, "memory pressure"
+		}
+
+		if s.queueWait() > s.cfg.MaxQueueWait/2 {
+			return nil, "queue wait"
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+			return release, ""
+		default:
+			return nil, "in-flight limit"
+		}
+	}
+
+	// Fast path when a slot is free.
+	select {
+	case s.slots <- struct{}{}:
+		s.recordWait(0)
+		return release, ""
+	default:
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(s.cfg.MaxQueueWait)
+	defer timer.Stop()
+
+	select {
+	case s.slots <- struct{}{}:
+		s.recordWait(time.Since(start))
+		return release, ""
+
+	case <-timer.C:
+		s.recordWait(s.cfg.MaxQueueWait)
+		return nil, "queue timeout"
+
+	case <-ctx.Done():
+		return nil, "client gone"
+	}
+}
+
+// recordWait folds a queue wait sample into the moving average.
+func (s *shedder) recordWait(d time.Duration) {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+
+	s.avgWait = (s.avgWait*7 + d) / 8
+}
+
+// queueWait returns the moving average of the queue wait.
+func (s *shedder) queueWait() time.Duration {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+
+	return s.avgWait
+}
+
+// memoryPressure reports whether the heap is close to the memory limit.
+// The runtime is sampled at most every 100ms.
+func (s *shedder) memoryPressure() bool {
+	limit := debug.SetMemoryLimit(-1)
+	if limit == math.MaxInt64 {
+		return false
+	}
+
+	now := time.Now().UnixNano()
+	if last := s.memAt.Load(); now-last > int64(100*time.Millisecond) && s.memAt.CompareAndSwap(last, now) {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+
+		if sample[0].Value.Kind() == metrics.KindUint64 {
+			ratio := float64(sample[0].Value.Uint64()) / float64(limit)
+			s.memRatio.Store(math.Float64bits(ratio))
+		}
+	}
+
+	return math.Float64frombits(s.memRatio.Load()) >= s.cfg.MemoryThreshold
+}
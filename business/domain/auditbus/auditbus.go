@@ -0,0 +1,183 @@
+// Package auditbus provides business access to audit domain. Audits are
+// recorded from the events raised by the other domains, which don't know
+// they are audited.
+package auditbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// archiveBatch bounds how many audits are compressed into a single archive.
+const archiveBatch = 1000
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, audit Audit) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	Archive(ctx context.Context, before time.Time, limit int) (int, error)
+	PurgeArchives(ctx context.Context, before time.Time) (int, error)
+}
+
+// Business manages the set of APIs for audit access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an audit business API for use and subscribes it to
+// the events of the audited domains.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	b := Business{
+		log:    log,
+		storer: storer,
+	}
+
+	b.registerDelegateFunctions(delegate)
+
+	return &b
+}
+
+// Create adds a new audit to the system.
+func (b *Business) Create(ctx context.Context, na NewAudit) (Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.create")
+	defer span.End()
+
+	audit := Audit{
+		ID:        id.New(),
+		ObjID:     na.ObjID,
+		ObjDomain: na.ObjDomain,
+		ActorID:   na.ActorID,
+		Action:    na.Action,
+		Data:      na.Data,
+		Timestamp: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, audit); err != nil {
+		return Audit{}, fmt.Errorf("create: %w", err)
+	}
+
+	return audit, nil
+}
+
+// Query retrieves a list of existing audits.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.query")
+	defer span.End()
+
+	audits, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return audits, nil
+}
+
+// Count returns the total number of audits.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// Retain compresses the audits older than the archive age into archives and
+// removes the archives older than the purge age. Audits are moved in batches
+// so a large backlog doesn't hold locks for long.
+func (b *Business) Retain(ctx context.Context, rc RetentionConfig) error {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.retain")
+	defer span.End()
+
+	now := time.Now()
+
+	var archived int
+	for {
+		n, err := b.storer.Archive(ctx, now.Add(-rc.ArchiveAfter), archiveBatch)
+		if err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+
+		archived += n
+
+		if n < archiveBatch || ctx.Err() != nil {
+			break
+		}
+	}
+
+	purged, err := b.storer.PurgeArchives(ctx, now.Add(-rc.PurgeAfter))
+	if err != nil {
+		return fmt.Errorf("purge archives: %w", err)
+	}
+
+	if archived > 0 || purged > 0 {
+		b.log.Info(ctx, "audit", "status", "retention complete", "archived", archived, "purged_archives", purged)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// registerDelegateFunctions subscribes to the events of the audited domains.
+func (b *Business) registerDelegateFunctions(d *delegate.Delegate) {
+	d.Register(customerbus.DomainName, customerbus.ActionCreated, b.customerAudit)
+	d.Register(customerbus.DomainName, customerbus.ActionUpdated, b.customerAudit)
+	d.Register(customerbus.DomainName, customerbus.ActionDeleted, b.customerAudit)
+	d.Register(orderbus.DomainName, orderbus.ActionCreated, b.orderAudit)
+	d.Register(orderbus.DomainName, orderbus.ActionUpdated, b.orderAudit)
+}
+
+func (b *Business) customerAudit(ctx context.Context, data delegate.Data) error {
+	var params struct {
+		CustomerID uuid.UUID
+		UserID     *uuid.UUID
+	}
+	if err := data.Decode(&params); err != nil {
+		return err
+	}
+
+	return b.record(ctx, data, params.CustomerID, params.UserID)
+}
+
+func (b *Business) orderAudit(ctx context.Context, data delegate.Data) error {
+	// The updated params are a superset of the created ones.
+	var params orderbus.ActionUpdatedParms
+	if err := data.Decode(&params); err != nil {
+		return err
+	}
+
+	actorID := params.After.UserID
+
+	return b.record(ctx, data, params.OrderID, &actorID)
+}
+
+// record stores the audit of an event. The raw params of the event, with
+// their snapshots, are kept as the data of the audit.
+func (b *Business) record(ctx context.Context, data delegate.Data, objID uuid.UUID, actorID *uuid.UUID) error {
+	na := NewAudit{
+		ObjID:     objID,
+		ObjDomain: data.Domain,
+		ActorID:   actorID,
+		Action:    data.Action,
+		Data:      data.RawParams,
+	}
+
+	if _, err := b.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit %s: %w", data, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package auditbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field means the query isn't filtered on it.
+type QueryFilter struct {
+	ObjID     *uuid.UUID
+	ObjDomain *string
+	ActorID   *uuid.UUID
+	Action    *string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
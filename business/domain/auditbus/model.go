@@ -0,0 +1,35 @@
+package auditbus
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit represents a change made to an object of a domain.
+type Audit struct {
+	ID        uuid.UUID
+	ObjID     uuid.UUID
+	ObjDomain string
+	ActorID   *uuid.UUID // The user that made the change, when known
+	Action    string
+	Data      json.RawMessage
+	Timestamp time.Time
+}
+
+// NewAudit is what we require when recording an Audit.
+type NewAudit struct {
+	ObjID     uuid.UUID
+	ObjDomain string
+	ActorID   *uuid.UUID
+	Action    string
+	Data      json.RawMessage
+}
+
+// RetentionConfig controls how long audits are kept queryable and how long
+// their compressed archives are kept.
+type RetentionConfig struct {
+	ArchiveAfter time.Duration
+	PurgeAfter   time.Duration
+}
@@ -0,0 +1,14 @@
+package auditbus
+
+import "github.com/AlmirSai/service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByTimestamp, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByObjID     = "obj_id"
+	OrderByObjDomain = "obj_domain"
+	OrderByAction    = "action"
+	OrderByTimestamp = "timestamp"
+)
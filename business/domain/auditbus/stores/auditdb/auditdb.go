@@ -0,0 +1,216 @@
+// Package auditdb contains audit related CRUD functionality.
+package auditdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for audit database access.
+type Store struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new audit into the database.
+func (s *Store) Create(ctx context.Context, audit auditbus.Audit) error {
+	const q = `
+	INSERT INTO audits
+		(audit_id, obj_id, obj_domain, actor_id, action, data, timestamp)
+	VALUES
+		(:audit_id, :obj_id, :obj_domain, :actor_id, :action, :data, :timestamp)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, sqldb.Executor(ctx, s.db), q, toDBAudit(audit)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing audits from the database.
+func (s *Store) Query(ctx context.Context, filter auditbus.QueryFilter, orderBy order.By, page page.Page) ([]auditbus.Audit, error) {
+	data := map[string]any{
+		"offset":        page.Offset(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		audit_id, obj_id, obj_domain, actor_id, action, data, timestamp
+	FROM
+		audits`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbAudits []audit
+	if err := sqldb.NamedQuerySlice(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &dbAudits); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAudits(dbAudits), nil
+}
+
+// Count returns the total number of audits in the DB.
+func (s *Store) Count(ctx context.Context, filter auditbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		audits`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// Archive moves up to limit audits older than before into a gzip compressed
+// archive of JSON lines and returns the number of audits moved. The rows are
+// removed and the archive is stored in the same transaction, and rows locked
+// by a concurrent run are skipped.
+func (s *Store) Archive(ctx context.Context, before time.Time, limit int) (int, error) {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	const qd = `
+	DELETE FROM
+		audits
+	WHERE
+		audit_id IN (
+			SELECT
+				audit_id
+			FROM
+				audits
+			WHERE
+				timestamp < $1
+			ORDER BY
+				timestamp
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		audit_id, obj_id, obj_domain, actor_id, action, data, timestamp`
+
+	var dbAudits []audit
+	if err := tx.SelectContext(ctx, &dbAudits, qd, before.UTC(), limit); err != nil {
+		return 0, fmt.Errorf("delete audits: %w", err)
+	}
+
+	if len(dbAudits) == 0 {
+		return 0, nil
+	}
+
+	arc, err := toDBArchive(dbAudits)
+	if err != nil {
+		return 0, err
+	}
+
+	const qi = `
+	INSERT INTO audit_archives
+		(archive_id, start_timestamp, end_timestamp, row_count, data, date_created)
+	VALUES
+		(:archive_id, :start_timestamp, :end_timestamp, :row_count, :data, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, tx, qi, arc); err != nil {
+		return 0, fmt.Errorf("insert archive: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	return len(dbAudits), nil
+}
+
+// PurgeArchives removes the archives created before the given time and
+// returns the number removed.
+func (s *Store) PurgeArchives(ctx context.Context, before time.Time) (int, error) {
+	const q = `
+	DELETE FROM
+		audit_archives
+	WHERE
+		date_created < $1`
+
+	res, err := sqldb.Executor(ctx, s.db).ExecContext(ctx, q, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("execcontext: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rowsaffected: %w", err)
+	}
+
+	return int(n), nil
+}
+
+// =============================================================================
+
+// toDBArchive compresses the audits, oldest first, into an archive.
+func toDBArchive(dbAudits []audit) (archive, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+
+	for _, a := range dbAudits {
+		if err := enc.Encode(a); err != nil {
+			return archive{}, fmt.Errorf("encode audit: %w", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return archive{}, fmt.Errorf("compress audits: %w", err)
+	}
+
+	arc := archive{
+		ID:             id.New(),
+		StartTimestamp: dbAudits[0].Timestamp,
+		EndTimestamp:   dbAudits[len(dbAudits)-1].Timestamp,
+		RowCount:       len(dbAudits),
+		Data:           buf.Bytes(),
+		DateCreated:    time.Now().UTC(),
+	}
+
+	return arc, nil
+}
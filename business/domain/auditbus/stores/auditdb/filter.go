@@ -0,0 +1,47 @@
+package auditdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+)
+
+func applyFilter(filter auditbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ObjID != nil {
+		data["obj_id"] = *filter.ObjID
+		wc = append(wc, "obj_id = :obj_id")
+	}
+
+	if filter.ObjDomain != nil {
+		data["obj_domain"] = *filter.ObjDomain
+		wc = append(wc, "obj_domain = :obj_domain")
+	}
+
+	if filter.ActorID != nil {
+		data["actor_id"] = *filter.ActorID
+		wc = append(wc, "actor_id = :actor_id")
+	}
+
+	if filter.Action != nil {
+		data["action"] = *filter.Action
+		wc = append(wc, "action = :action")
+	}
+
+	if filter.StartDate != nil {
+		data["start_date"] = filter.StartDate.UTC()
+		wc = append(wc, "timestamp >= :start_date")
+	}
+
+	if filter.EndDate != nil {
+		data["end_date"] = filter.EndDate.UTC()
+		wc = append(wc, "timestamp <= :end_date")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,70 @@
+package auditdb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+type audit struct {
+	ID        uuid.UUID       `db:"audit_id" json:"audit_id"`
+	ObjID     uuid.UUID       `db:"obj_id" json:"obj_id"`
+	ObjDomain string          `db:"obj_domain" json:"obj_domain"`
+	ActorID   uuid.NullUUID   `db:"actor_id" json:"actor_id"`
+	Action    string          `db:"action" json:"action"`
+	Data      json.RawMessage `db:"data" json:"data"`
+	Timestamp time.Time       `db:"timestamp" json:"timestamp"`
+}
+
+type archive struct {
+	ID             uuid.UUID `db:"archive_id"`
+	StartTimestamp time.Time `db:"start_timestamp"`
+	EndTimestamp   time.Time `db:"end_timestamp"`
+	RowCount       int       `db:"row_count"`
+	Data           []byte    `db:"data"`
+	DateCreated    time.Time `db:"date_created"`
+}
+
+func toDBAudit(a auditbus.Audit) audit {
+	var actorID uuid.NullUUID
+	if a.ActorID != nil {
+		actorID = uuid.NullUUID{UUID: *a.ActorID, Valid: true}
+	}
+
+	return audit{
+		ID:        a.ID,
+		ObjID:     a.ObjID,
+		ObjDomain: a.ObjDomain,
+		ActorID:   actorID,
+		Action:    a.Action,
+		Data:      a.Data,
+		Timestamp: a.Timestamp.UTC(),
+	}
+}
+
+func toBusAudit(db audit) auditbus.Audit {
+	var actorID *uuid.UUID
+	if db.ActorID.Valid {
+		actorID = &db.ActorID.UUID
+	}
+
+	return auditbus.Audit{
+		ID:        db.ID,
+		ObjID:     db.ObjID,
+		ObjDomain: db.ObjDomain,
+		ActorID:   actorID,
+		Action:    db.Action,
+		Data:      db.Data,
+		Timestamp: db.Timestamp.In(time.Local),
+	}
+}
+
+func toBusAudits(dbs []audit) []auditbus.Audit {
+	audits := make([]auditbus.Audit, len(dbs))
+	for i, db := range dbs {
+		audits[i] = toBusAudit(db)
+	}
+	return audits
+}
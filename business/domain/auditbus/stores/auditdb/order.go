@@ -0,0 +1,17 @@
+package auditdb
+
+import (
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	auditbus.OrderByObjID:     "obj_id",
+	auditbus.OrderByObjDomain: "obj_domain",
+	auditbus.OrderByAction:    "action",
+	auditbus.OrderByTimestamp: "timestamp",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return order.Clause(orderBy, orderByFields, "audit_id")
+}
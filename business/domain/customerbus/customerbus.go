@@ -0,0 +1,302 @@
+// Package customerbus provides business access to customer domain.
+package customerbus
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/rules"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound    = errs.Newf(errs.NotFound, "customer not found")
+	ErrUniqueEmail = errs.Newf(errs.AlreadyExists, "email is not unique")
+	ErrHasOrders   = errs.Newf(errs.FailedPrecondition, "customer has orders")
+)
+
+// archiveBatch bounds how many deleted customers a single statement archives
+// so a backlog doesn't hold locks for long.
+const archiveBatch = 500
+
+// reencryptBatch bounds how many customers a single transaction re-encrypts.
+const reencryptBatch = 500
+
+//go:generate moq -pkg customermock -out customermock/customermock.go . Storer
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, cus Customer) error
+	Update(ctx context.Context, cus Customer) error
+	Delete(ctx context.Context, cus Customer, now time.Time) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Customer, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, customerID uuid.UUID) (Customer, error)
+	QueryByEmail(ctx context.Context, email mail.Address) (Customer, error)
+	ArchiveDeleted(ctx context.Context, before time.Time, now time.Time, limit int) (int, error)
+	Reencrypt(ctx context.Context, limit int) (int, error)
+}
+
+// Business manages the set of APIs for customer access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	rules    *rules.Set[Customer]
+}
+
+// NewBusiness constructs a customer business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	return &Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		rules:    newRules(storer),
+	}
+}
+
+// Create adds a new customer to the system.
+func (b *Business) Create(ctx context.Context, nc NewCustomer) (Customer, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.create")
+	defer span.End()
+
+	now := time.Now()
+	customerID := id.New()
+
+	cus := Customer{
+		ID:          customerID,
+		UserID:      nc.UserID,
+		Name:        nc.Name,
+		Email:       nc.Email,
+		Phone:       nc.Phone,
+		Addresses:   toAddresses(customerID, nc.Addresses),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.rules.Check(ctx, cus); err != nil {
+		return Customer{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := b.storer.Create(ctx, cus); err != nil {
+		return Customer{}, fmt.Errorf("create: %w", err)
+	}
+
+	data, err := ActionCreatedData(cus)
+	if err != nil {
+		return Customer{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		return Customer{}, fmt.Errorf("create: %w", err)
+	}
+
+	return cus, nil
+}
+
+// Update modifies information about a customer.
+func (b *Business) Update(ctx context.Context, cus Customer, uc UpdateCustomer) (Customer, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.update")
+	defer span.End()
+
+	before := cus
+
+	if uc.UserID != nil {
+		cus.UserID = uc.UserID
+	}
+
+	if uc.Name != nil {
+		cus.Name = *uc.Name
+	}
+
+	if uc.Email != nil {
+		cus.Email = *uc.Email
+	}
+
+	if uc.Phone != nil {
+		cus.Phone = *uc.Phone
+	}
+
+	if uc.Addresses != nil {
+		cus.Addresses = toAddresses(cus.ID, *uc.Addresses)
+	}
+
+	cus.DateUpdated = time.Now()
+
+	if err := b.rules.Check(ctx, cus); err != nil {
+		return Customer{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := b.storer.Update(ctx, cus); err != nil {
+		return Customer{}, fmt.Errorf("update: %w", err)
+	}
+
+	data, err := ActionUpdatedData(before, cus)
+	if err != nil {
+		return Customer{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		return Customer{}, fmt.Errorf("update: %w", err)
+	}
+
+	return cus, nil
+}
+
+// Delete removes the specified customer. The customer is only marked as
+// deleted until ArchiveDeleted moves it to the archive.
+func (b *Business) Delete(ctx context.Context, cus Customer) error {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, cus, time.Now()); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	data, err := ActionDeletedData(cus)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing customers.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Customer, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.query")
+	defer span.End()
+
+	customers, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return customers, nil
+}
+
+// Count returns the total number of customers.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the customer by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, customerID uuid.UUID) (Customer, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.querybyid")
+	defer span.End()
+
+	cus, err := b.storer.QueryByID(ctx, customerID)
+	if err != nil {
+		return Customer{}, fmt.Errorf("query: customerID[%s]: %w", customerID, err)
+	}
+
+	return cus, nil
+}
+
+// QueryByEmail finds the customer by a specified email.
+func (b *Business) QueryByEmail(ctx context.Context, email mail.Address) (Customer, error) {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.querybyemail")
+	defer span.End()
+
+	cus, err := b.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		return Customer{}, fmt.Errorf("query: email[%s]: %w", email.Address, err)
+	}
+
+	return cus, nil
+}
+
+// ArchiveDeleted moves the customers deleted longer ago than the retention
+// period into the archive and removes them from the customer tables. It's
+// intended to run periodically from the scheduler and is safe to run from
+// several instances at once.
+func (b *Business) ArchiveDeleted(ctx context.Context, retention time.Duration) error {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.archivedeleted")
+	defer span.End()
+
+	now := time.Now()
+
+	var archived int
+	for {
+		n, err := b.storer.ArchiveDeleted(ctx, now.Add(-retention), now, archiveBatch)
+		if err != nil {
+			return fmt.Errorf("archive deleted: %w", err)
+		}
+
+		archived += n
+
+		if n < archiveBatch || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if archived > 0 {
+		b.log.Info(ctx, "customer", "status", "archived deleted customers", "count", archived)
+	}
+
+	return nil
+}
+
+// Reencrypt rewrites the personal data of every customer that isn't encrypted
+// with the active key yet. It's intended to run periodically from the
+// scheduler so a key rotation, or enabling encryption, reaches existing rows.
+func (b *Business) Reencrypt(ctx context.Context) error {
+	ctx, span := otel.AddSpan(ctx, "business.customerbus.reencrypt")
+	defer span.End()
+
+	var rewritten int
+	for {
+		n, err := b.storer.Reencrypt(ctx, reencryptBatch)
+		if err != nil {
+			return fmt.Errorf("reencrypt: %w", err)
+		}
+
+		rewritten += n
+
+		if n < reencryptBatch || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if rewritten > 0 {
+		b.log.Info(ctx, "customer", "status", "re-encrypted customers", "count", rewritten)
+	}
+
+	return nil
+}
+
+// toAddresses assigns identities to the new addresses of a customer.
+func toAddresses(customerID uuid.UUID, nas []NewAddress) []Address {
+	addrs := make([]Address, len(nas))
+	for i, na := range nas {
+		addrs[i] = Address{
+			ID:         id.New(),
+			CustomerID: customerID,
+			Kind:       na.Kind,
+			Line1:      na.Line1,
+			Line2:      na.Line2,
+			City:       na.City,
+			State:      na.State,
+			ZipCode:    na.ZipCode,
+			Country:    na.Country,
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,67 @@
+package customerbus
+
+import (
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// DomainName represents the name of this domain for delegate events.
+const DomainName = "customer"
+
+// Set of delegate actions raised by this domain. The params of every action
+// carry snapshots of the customer so subscribers don't have to query it.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// ActionCreatedParms represents the parameters of the created action.
+type ActionCreatedParms struct {
+	CustomerID uuid.UUID
+	UserID     *uuid.UUID
+	After      Customer
+}
+
+// ActionCreatedData constructs the data for the created action.
+func ActionCreatedData(cus Customer) (delegate.Data, error) {
+	return delegate.NewData(DomainName, ActionCreated, ActionCreatedParms{
+		CustomerID: cus.ID,
+		UserID:     cus.UserID,
+		After:      cus,
+	})
+}
+
+// ActionUpdatedParms represents the parameters of the updated action.
+type ActionUpdatedParms struct {
+	CustomerID uuid.UUID
+	UserID     *uuid.UUID
+	Before     Customer
+	After      Customer
+}
+
+// ActionUpdatedData constructs the data for the updated action.
+func ActionUpdatedData(before Customer, after Customer) (delegate.Data, error) {
+	return delegate.NewData(DomainName, ActionUpdated, ActionUpdatedParms{
+		CustomerID: after.ID,
+		UserID:     after.UserID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// ActionDeletedParms represents the parameters of the deleted action.
+type ActionDeletedParms struct {
+	CustomerID uuid.UUID
+	UserID     *uuid.UUID
+	Before     Customer
+}
+
+// ActionDeletedData constructs the data for the deleted action.
+func ActionDeletedData(cus Customer) (delegate.Data, error) {
+	return delegate.NewData(DomainName, ActionDeleted, ActionDeletedParms{
+		CustomerID: cus.ID,
+		UserID:     cus.UserID,
+		Before:     cus,
+	})
+}
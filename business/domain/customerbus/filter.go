@@ -0,0 +1,14 @@
+package customerbus
+
+import (
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field means the query isn't filtered on it.
+type QueryFilter struct {
+	ID     *uuid.UUID
+	UserID *uuid.UUID
+	Name   *string
+	Email  *string
+}
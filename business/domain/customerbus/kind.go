@@ -0,0 +1,65 @@
+package customerbus
+
+import "fmt"
+
+// The set of address kinds that can be used.
+var (
+	AddressShipping = newAddressKind("SHIPPING")
+	AddressBilling  = newAddressKind("BILLING")
+)
+
+// =============================================================================
+
+// Set of known address kinds.
+var addressKinds = make(map[string]AddressKind)
+
+// AddressKind represents what an address is used for.
+type AddressKind struct {
+	value string
+}
+
+func newAddressKind(kind string) AddressKind {
+	k := AddressKind{kind}
+	addressKinds[kind] = k
+	return k
+}
+
+// String returns the name of the address kind.
+func (k AddressKind) String() string {
+	return k.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (k AddressKind) Equal(k2 AddressKind) bool {
+	return k.value == k2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (k AddressKind) MarshalText() ([]byte, error) {
+	return []byte(k.value), nil
+}
+
+// UnmarshalText provides support for decoding a kind that was marshaled.
+func (k *AddressKind) UnmarshalText(data []byte) error {
+	kind, err := ParseAddressKind(string(data))
+	if err != nil {
+		return err
+	}
+
+	*k = kind
+
+	return nil
+}
+
+// =============================================================================
+
+// ParseAddressKind parses the string value and returns an address kind if
+// one exists.
+func ParseAddressKind(value string) (AddressKind, error) {
+	kind, exists := addressKinds[value]
+	if !exists {
+		return AddressKind{}, fmt.Errorf("invalid address kind %q", value)
+	}
+
+	return kind, nil
+}
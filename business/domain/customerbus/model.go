@@ -0,0 +1,63 @@
+package customerbus
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer represents information about an individual customer.
+type Customer struct {
+	ID          uuid.UUID
+	UserID      *uuid.UUID // Optional link to the user account of the customer
+	Name        string
+	Email       mail.Address
+	Phone       string
+	Addresses   []Address
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Address represents a postal address of a customer.
+type Address struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Kind       AddressKind
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	ZipCode    string
+	Country    string
+}
+
+// NewCustomer is what we require from clients when adding a Customer.
+type NewCustomer struct {
+	UserID    *uuid.UUID
+	Name      string
+	Email     mail.Address
+	Phone     string
+	Addresses []NewAddress
+}
+
+// NewAddress is what we require from clients when adding an Address.
+type NewAddress struct {
+	Kind    AddressKind
+	Line1   string
+	Line2   string
+	City    string
+	State   string
+	ZipCode string
+	Country string
+}
+
+// UpdateCustomer contains information needed to update a customer. Fields
+// that are nil are left unchanged. Addresses replaces the full set.
+type UpdateCustomer struct {
+	UserID    *uuid.UUID
+	Name      *string
+	Email     *mail.Address
+	Phone     *string
+	Addresses *[]NewAddress
+}
@@ -0,0 +1,14 @@
+package customerbus
+
+import "github.com/AlmirSai/service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByName, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "customer_id"
+	OrderByName        = "name"
+	OrderByEmail       = "email"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,35 @@
+package customerbus
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlmirSai/service/business/sdk/rules"
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// newRules constructs the invariants a customer has to hold before it's
+// stored. They are checked for both new and updated customers.
+func newRules(storer Storer) *rules.Set[Customer] {
+	return rules.New("customer",
+		uniqueEmail(storer),
+	)
+}
+
+// uniqueEmail checks no other customer uses the email. The unique index
+// still guards against a concurrent write slipping past the lookup.
+func uniqueEmail(storer Storer) rules.Rule[Customer] {
+	return func(ctx context.Context, cus Customer) error {
+		found, err := storer.QueryByEmail(ctx, cus.Email)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return nil
+		case err != nil:
+			return err
+		case found.ID != cus.ID:
+			return rules.Violate(errs.AlreadyExists, "email", "email is not unique")
+		}
+
+		return nil
+	}
+}
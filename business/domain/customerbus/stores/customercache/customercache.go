@@ -0,0 +1,165 @@
+// Package customercache contains customer related CRUD functionality with
+// caching. Lookups by ID and email are served from memory, everything else
+// goes straight to the wrapped store.
+package customercache
+
+import (
+	"context"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/cache"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Store manages the set of APIs for customer data and caching.
+type Store struct {
+	log       *logger.Logger
+	storer    customerbus.Storer
+	customers *cache.Cache[customerbus.Customer]
+	emails    *cache.Cache[uuid.UUID]
+}
+
+// NewStore constructs the api for data and caching access. Cached customers
+// are evicted when the customer domain raises an updated or deleted event,
+// so the delegate must be the one given to the customer business.
+func NewStore(log *logger.Logger, delegate *delegate.Delegate, storer customerbus.Storer, ttl time.Duration) *Store {
+	mem := cache.NewMemory(cache.MemoryConfig{})
+
+	s := Store{
+		log:    log,
+		storer: storer,
+		customers: cache.New[customerbus.Customer](mem, cache.Config{
+			Prefix: "customer:id:",
+			TTL:    ttl,
+			Log:    log,
+		}),
+		emails: cache.New[uuid.UUID](mem, cache.Config{
+			Prefix: "customer:email:",
+			TTL:    ttl,
+			Log:    log,
+		}),
+	}
+
+	delegate.Register(customerbus.DomainName, customerbus.ActionUpdated, s.actionUpdated)
+	delegate.Register(customerbus.DomainName, customerbus.ActionDeleted, s.actionDeleted)
+
+	return &s
+}
+
+// Create inserts a new customer into the database.
+func (s *Store) Create(ctx context.Context, cus customerbus.Customer) error {
+	return s.storer.Create(ctx, cus)
+}
+
+// Update replaces a customer document in the database.
+func (s *Store) Update(ctx context.Context, cus customerbus.Customer) error {
+	return s.storer.Update(ctx, cus)
+}
+
+// Delete removes a customer from the database.
+func (s *Store) Delete(ctx context.Context, cus customerbus.Customer, now time.Time) error {
+	return s.storer.Delete(ctx, cus, now)
+}
+
+// Query retrieves a list of existing customers from the database.
+func (s *Store) Query(ctx context.Context, filter customerbus.QueryFilter, orderBy order.By, page page.Page) ([]customerbus.Customer, error) {
+	return s.storer.Query(ctx, filter, orderBy, page)
+}
+
+// Count returns the total number of customers in the DB.
+func (s *Store) Count(ctx context.Context, filter customerbus.QueryFilter) (int, error) {
+	return s.storer.Count(ctx, filter)
+}
+
+// QueryByID gets the specified customer from the cache or the database.
+func (s *Store) QueryByID(ctx context.Context, customerID uuid.UUID) (customerbus.Customer, error) {
+	return s.customers.GetOrLoad(ctx, customerID.String(), func(ctx context.Context) (customerbus.Customer, error) {
+		return s.storer.QueryByID(ctx, customerID)
+	})
+}
+
+// QueryByEmail gets the specified customer from the cache or the database.
+// The email is only mapped to an ID, so a customer that changed its email
+// since the mapping was cached is looked up again.
+func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (customerbus.Customer, error) {
+	customerID, err := s.emails.Get(ctx, email.Address)
+	if err == nil {
+		cus, err := s.QueryByID(ctx, customerID)
+		if err == nil && cus.Email.Address == email.Address {
+			return cus, nil
+		}
+
+		s.evictEmail(ctx, email.Address)
+	}
+
+	cus, err := s.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		return customerbus.Customer{}, err
+	}
+
+	if err := s.emails.Set(ctx, email.Address, cus.ID); err != nil {
+		s.log.Warn(ctx, "customercache", "operation", "set", "email", email.Address, "error", err)
+	}
+
+	if err := s.customers.Set(ctx, cus.ID.String(), cus); err != nil {
+		s.log.Warn(ctx, "customercache", "operation", "set", "customer_id", cus.ID, "error", err)
+	}
+
+	return cus, nil
+}
+
+// ArchiveDeleted moves deleted customers into the archive. Deleted customers
+// were already evicted when they were deleted.
+func (s *Store) ArchiveDeleted(ctx context.Context, before time.Time, now time.Time, limit int) (int, error) {
+	return s.storer.ArchiveDeleted(ctx, before, now, limit)
+}
+
+// Reencrypt rewrites the personal data of customers with the active key. The
+// cached customers hold plaintext, so they aren't affected.
+func (s *Store) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return s.storer.Reencrypt(ctx, limit)
+}
+
+// =============================================================================
+
+func (s *Store) actionUpdated(ctx context.Context, data delegate.Data) error {
+	var params customerbus.ActionUpdatedParms
+	if err := data.Decode(&params); err != nil {
+		return err
+	}
+
+	s.evict(ctx, params.CustomerID)
+
+	return nil
+}
+
+func (s *Store) actionDeleted(ctx context.Context, data delegate.Data) error {
+	var params customerbus.ActionDeletedParms
+	if err := data.Decode(&params); err != nil {
+		return err
+	}
+
+	s.evict(ctx, params.CustomerID)
+
+	return nil
+}
+
+// evict removes the customer from the cache. A failure is only logged, the
+// write the event belongs to already succeeded and the entry expires anyway.
+func (s *Store) evict(ctx context.Context, customerID uuid.UUID) {
+	if err := s.customers.Delete(ctx, customerID.String()); err != nil {
+		s.log.Warn(ctx, "customercache", "operation", "delete", "customer_id", customerID, "error", err)
+	}
+}
+
+func (s *Store) evictEmail(ctx context.Context, email string) {
+	if err := s.emails.Delete(ctx, email); err != nil {
+		s.log.Warn(ctx, "customercache", "operation", "delete", "email", email, "error", err)
+	}
+}
@@ -0,0 +1,492 @@
+// Package customerdb contains customer related CRUD functionality.
+package customerdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for customer database access.
+type Store struct {
+	log   *logger.Logger
+	db    *sqlx.DB
+	crypt *encrypt.Keyring
+}
+
+// NewStore constructs the api for data access. The email and phone of a
+// customer are encrypted with the keyring; a nil keyring stores them in
+// plaintext.
+func NewStore(log *logger.Logger, db *sqlx.DB, crypt *encrypt.Keyring) *Store {
+	return &Store{
+		log:   log,
+		db:    db,
+		crypt: crypt,
+	}
+}
+
+// Create inserts a new customer and its addresses into the database.
+func (s *Store) Create(ctx context.Context, cus customerbus.Customer) error {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const q = `
+	INSERT INTO customers
+		(customer_id, user_id, name, email, email_hash, phone, date_created, date_updated)
+	VALUES
+		(:customer_id, :user_id, :name, :email, :email_hash, :phone, :date_created, :date_updated)`
+
+	dbCus, err := toDBCustomer(s.crypt, cus)
+	if err != nil {
+		return err
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, tx, q, dbCus); err != nil {
+		if sqldb.IsUniqueViolation(err) {
+			return fmt.Errorf("insert customer: %w", customerbus.ErrUniqueEmail)
+		}
+		return fmt.Errorf("insert customer: %w", sqldb.Translate(err))
+	}
+
+	if err := insertAddresses(ctx, tx, cus.Addresses); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a customer document and its addresses in the database.
+func (s *Store) Update(ctx context.Context, cus customerbus.Customer) error {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const q = `
+	UPDATE
+		customers
+	SET
+		"user_id" = :user_id,
+		"name" = :name,
+		"email" = :email,
+		"email_hash" = :email_hash,
+		"phone" = :phone,
+		"date_updated" = :date_updated
+	WHERE
+		customer_id = :customer_id AND
+		date_deleted IS NULL`
+
+	dbCus, err := toDBCustomer(s.crypt, cus)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.NamedExecContext(ctx, q, dbCus)
+	if err != nil {
+		if sqldb.IsUniqueViolation(err) {
+			return fmt.Errorf("update customer: %w", customerbus.ErrUniqueEmail)
+		}
+		return fmt.Errorf("update customer: %w", sqldb.Translate(err))
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rowsaffected: %w", err)
+	}
+
+	if n == 0 {
+		return customerbus.ErrNotFound
+	}
+
+	const qd = `
+	DELETE FROM
+		customer_addresses
+	WHERE
+		customer_id = $1`
+
+	if _, err := tx.ExecContext(ctx, qd, cus.ID); err != nil {
+		return fmt.Errorf("delete addresses: %w", sqldb.Translate(err))
+	}
+
+	if err := insertAddresses(ctx, tx, cus.Addresses); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// Delete marks a customer as deleted. The row and its addresses stay until
+// ArchiveDeleted moves them to the archive. Customers referenced by orders
+// can't be removed.
+func (s *Store) Delete(ctx context.Context, cus customerbus.Customer, now time.Time) error {
+	exec := sqldb.Executor(ctx, s.db)
+
+	const qo = `
+	SELECT EXISTS (
+		SELECT 1 FROM orders WHERE customer_id = $1
+	)`
+
+	var hasOrders bool
+	if err := sqlx.GetContext(ctx, exec, &hasOrders, qo, cus.ID); err != nil {
+		return fmt.Errorf("select orders: %w", sqldb.Translate(err))
+	}
+
+	if hasOrders {
+		return fmt.Errorf("delete: %w", customerbus.ErrHasOrders)
+	}
+
+	const q = `
+	UPDATE
+		customers
+	SET
+		date_deleted = $2,
+		date_updated = $2
+	WHERE
+		customer_id = $1 AND
+		date_deleted IS NULL`
+
+	if _, err := exec.ExecContext(ctx, q, cus.ID, now.UTC()); err != nil {
+		return fmt.Errorf("execcontext: %w", sqldb.Translate(err))
+	}
+
+	return nil
+}
+
+// ArchiveDeleted moves up to limit customers deleted before the given time,
+// with their addresses, into the archive and removes them. Customers still
+// referenced by orders stay where they are. It returns the number archived.
+func (s *Store) ArchiveDeleted(ctx context.Context, before time.Time, now time.Time, limit int) (int, error) {
+	// The statements of a data modifying CTE see the same snapshot, so the
+	// addresses are still readable while the cascade removes them.
+	const q = `
+	WITH removed AS (
+		DELETE FROM
+			customers
+		WHERE
+			customer_id IN (
+				SELECT
+					c.customer_id
+				FROM
+					customers AS c
+				WHERE
+					c.date_deleted < $1 AND
+					NOT EXISTS (SELECT 1 FROM orders AS o WHERE o.customer_id = c.customer_id)
+				ORDER BY
+					c.date_deleted
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			*
+	)
+	INSERT INTO customers_archive
+		(customer_id, data, date_deleted, date_archived)
+	SELECT
+		r.customer_id,
+		to_jsonb(r) || jsonb_build_object('addresses', (
+			SELECT
+				COALESCE(jsonb_agg(to_jsonb(a)), '[]')
+			FROM
+				customer_addresses AS a
+			WHERE
+				a.customer_id = r.customer_id
+		)),
+		r.date_deleted,
+		$2
+	FROM
+		removed AS r`
+
+	res, err := sqldb.Executor(ctx, s.db).ExecContext(ctx, q, before.UTC(), now.UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("execcontext: %w", sqldb.Translate(err))
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rowsaffected: %w", err)
+	}
+
+	return int(n), nil
+}
+
+// Reencrypt rewrites up to limit customers whose email or phone isn't
+// encrypted with the active key, including rows written in plaintext before
+// encryption was enabled. It returns the number rewritten.
+func (s *Store) Reencrypt(ctx context.Context, limit int) (int, error) {
+	if s.crypt == nil {
+		return 0, nil
+	}
+
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	const q = `
+	SELECT
+		customer_id, user_id, name, email, email_hash, phone, date_created, date_updated
+	FROM
+		customers
+	WHERE
+		email_hash IS NULL OR
+		NOT starts_with(email, $1) OR
+		NOT starts_with(phone, $1)
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED`
+
+	var dbCuss []customer
+	if err := tx.SelectContext(ctx, &dbCuss, q, "enc:"+s.crypt.Active()+":", limit); err != nil {
+		return 0, fmt.Errorf("select customers: %w", sqldb.Translate(err))
+	}
+
+	const qu = `
+	UPDATE
+		customers
+	SET
+		"email" = :email,
+		"email_hash" = :email_hash,
+		"phone" = :phone
+	WHERE
+		customer_id = :customer_id`
+
+	for _, c := range dbCuss {
+		cus, err := toBusCustomer(s.crypt, c, nil)
+		if err != nil {
+			return 0, fmt.Errorf("customerID[%s]: %w", c.ID, err)
+		}
+
+		dbCus, err := toDBCustomer(s.crypt, cus)
+		if err != nil {
+			return 0, fmt.Errorf("customerID[%s]: %w", c.ID, err)
+		}
+
+		if err := sqldb.NamedExecContext(ctx, s.log, tx, qu, dbCus); err != nil {
+			return 0, fmt.Errorf("update customer: %w", sqldb.Translate(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	return len(dbCuss), nil
+}
+
+// Query retrieves a list of existing customers from the database.
+func (s *Store) Query(ctx context.Context, filter customerbus.QueryFilter, orderBy order.By, page page.Page) ([]customerbus.Customer, error) {
+	data := map[string]any{
+		"offset":        page.Offset(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		customer_id, user_id, name, email, phone, date_created, date_updated
+	FROM
+		customers`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(s.crypt, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbCuss []customer
+	if err := sqldb.NamedQuerySlice(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &dbCuss); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(dbCuss))
+	for i, c := range dbCuss {
+		ids[i] = c.ID
+	}
+
+	addrsByCustomer, err := s.queryAddresses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	cuss := make([]customerbus.Customer, len(dbCuss))
+	for i, c := range dbCuss {
+		cus, err := toBusCustomer(s.crypt, c, addrsByCustomer[c.ID])
+		if err != nil {
+			return nil, err
+		}
+		cuss[i] = cus
+	}
+
+	return cuss, nil
+}
+
+// Count returns the total number of customers in the DB.
+func (s *Store) Count(ctx context.Context, filter customerbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		customers`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(s.crypt, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified customer from the database.
+func (s *Store) QueryByID(ctx context.Context, customerID uuid.UUID) (customerbus.Customer, error) {
+	const q = `
+	SELECT
+		customer_id, user_id, name, email, phone, date_created, date_updated
+	FROM
+		customers
+	WHERE
+		customer_id = :customer_id AND
+		date_deleted IS NULL`
+
+	data := struct {
+		ID string `db:"customer_id"`
+	}{
+		ID: customerID.String(),
+	}
+
+	var dbCus customer
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbCus); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return customerbus.Customer{}, fmt.Errorf("namedquerystruct: %w", customerbus.ErrNotFound)
+		}
+		return customerbus.Customer{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	addrs, err := s.queryAddresses(ctx, []uuid.UUID{customerID})
+	if err != nil {
+		return customerbus.Customer{}, err
+	}
+
+	return toBusCustomer(s.crypt, dbCus, addrs[customerID])
+}
+
+// QueryByEmail gets the specified customer from the database by email.
+func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (customerbus.Customer, error) {
+	const q = `
+	SELECT
+		customer_id, user_id, name, email, phone, date_created, date_updated
+	FROM
+		customers
+	WHERE
+		(email_hash = :email_hash OR (email_hash IS NULL AND email = :email)) AND
+		date_deleted IS NULL`
+
+	// Rows written before encryption was enabled have no hash yet and still
+	// hold the email in plaintext.
+	data := struct {
+		Email     string         `db:"email"`
+		EmailHash sql.NullString `db:"email_hash"`
+	}{
+		Email:     email.Address,
+		EmailHash: emailHash(s.crypt, email.Address),
+	}
+
+	var dbCus customer
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbCus); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return customerbus.Customer{}, fmt.Errorf("namedquerystruct: %w", customerbus.ErrNotFound)
+		}
+		return customerbus.Customer{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	addrs, err := s.queryAddresses(ctx, []uuid.UUID{dbCus.ID})
+	if err != nil {
+		return customerbus.Customer{}, err
+	}
+
+	return toBusCustomer(s.crypt, dbCus, addrs[dbCus.ID])
+}
+
+// queryAddresses returns the addresses of the given customers grouped by
+// customer.
+func (s *Store) queryAddresses(ctx context.Context, customerIDs []uuid.UUID) (map[uuid.UUID][]address, error) {
+	if len(customerIDs) == 0 {
+		return map[uuid.UUID][]address{}, nil
+	}
+
+	const q = `
+	SELECT
+		address_id, customer_id, kind, line1, line2, city, state, zip_code, country
+	FROM
+		customer_addresses
+	WHERE
+		customer_id = ANY($1)
+	ORDER BY
+		kind, address_id`
+
+	var dbAddrs []address
+	if err := sqlx.SelectContext(ctx, sqldb.Executor(ctx, s.db), &dbAddrs, q, customerIDs); err != nil {
+		return nil, fmt.Errorf("select addresses: %w", err)
+	}
+
+	addrsByCustomer := make(map[uuid.UUID][]address, len(customerIDs))
+	for _, a := range dbAddrs {
+		addrsByCustomer[a.CustomerID] = append(addrsByCustomer[a.CustomerID], a)
+	}
+
+	return addrsByCustomer, nil
+}
+
+// insertAddresses adds the addresses of a customer within the transaction.
+func insertAddresses(ctx context.Context, tx *sqldb.Tx, addrs []customerbus.Address) error {
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	const q = `
+	INSERT INTO customer_addresses
+		(address_id, customer_id, kind, line1, line2, city, state, zip_code, country)
+	VALUES
+		(:address_id, :customer_id, :kind, :line1, :line2, :city, :state, :zip_code, :country)`
+
+	if _, err := tx.NamedExecContext(ctx, q, toDBAddresses(addrs)); err != nil {
+		return fmt.Errorf("insert addresses: %w", sqldb.Translate(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package customerdb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/foundation/encrypt"
+)
+
+func applyFilter(crypt *encrypt.Keyring, filter customerbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	// Deleted customers are kept until they're archived but never returned.
+	wc := []string{"date_deleted IS NULL"}
+
+	if filter.ID != nil {
+		data["customer_id"] = *filter.ID
+		wc = append(wc, "customer_id = :customer_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.Name != nil {
+		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
+		wc = append(wc, "name ILIKE :name")
+	}
+
+	if filter.Email != nil {
+		data["email"] = *filter.Email
+		data["email_hash"] = emailHash(crypt, *filter.Email)
+		wc = append(wc, "(email_hash = :email_hash OR (email_hash IS NULL AND email = :email))")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
+}
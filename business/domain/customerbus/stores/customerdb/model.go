@@ -0,0 +1,166 @@
+package customerdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/google/uuid"
+)
+
+// The email and phone columns hold personal data and are encrypted. The
+// column names are bound to the ciphertext as associated data.
+const (
+	emailColumn = "customers.email"
+	phoneColumn = "customers.phone"
+)
+
+type customer struct {
+	ID          uuid.UUID      `db:"customer_id"`
+	UserID      uuid.NullUUID  `db:"user_id"`
+	Name        string         `db:"name"`
+	Email       string         `db:"email"`
+	EmailHash   sql.NullString `db:"email_hash"`
+	Phone       sql.NullString `db:"phone"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBCustomer(crypt *encrypt.Keyring, cus customerbus.Customer) (customer, error) {
+	var userID uuid.NullUUID
+	if cus.UserID != nil {
+		userID = uuid.NullUUID{UUID: *cus.UserID, Valid: true}
+	}
+
+	email, err := crypt.Encrypt(cus.Email.Address, emailColumn)
+	if err != nil {
+		return customer{}, fmt.Errorf("encrypt email: %w", err)
+	}
+
+	phone, err := crypt.Encrypt(cus.Phone, phoneColumn)
+	if err != nil {
+		return customer{}, fmt.Errorf("encrypt phone: %w", err)
+	}
+
+	db := customer{
+		ID:        cus.ID,
+		UserID:    userID,
+		Name:      cus.Name,
+		Email:     email,
+		EmailHash: emailHash(crypt, cus.Email.Address),
+		Phone: sql.NullString{
+			String: phone,
+			Valid:  phone != "",
+		},
+		DateCreated: cus.DateCreated.UTC(),
+		DateUpdated: cus.DateUpdated.UTC(),
+	}
+
+	return db, nil
+}
+
+func toBusCustomer(crypt *encrypt.Keyring, db customer, addrs []address) (customerbus.Customer, error) {
+	var userID *uuid.UUID
+	if db.UserID.Valid {
+		userID = &db.UserID.UUID
+	}
+
+	email, err := crypt.Decrypt(db.Email, emailColumn)
+	if err != nil {
+		return customerbus.Customer{}, fmt.Errorf("decrypt email: %w", err)
+	}
+
+	phone, err := crypt.Decrypt(db.Phone.String, phoneColumn)
+	if err != nil {
+		return customerbus.Customer{}, fmt.Errorf("decrypt phone: %w", err)
+	}
+
+	busAddrs, err := toBusAddresses(addrs)
+	if err != nil {
+		return customerbus.Customer{}, err
+	}
+
+	cus := customerbus.Customer{
+		ID:          db.ID,
+		UserID:      userID,
+		Name:        db.Name,
+		Email:       mail.Address{Name: db.Name, Address: email},
+		Phone:       phone,
+		Addresses:   busAddrs,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return cus, nil
+}
+
+// emailHash returns the blind index of an email. It's null when encryption is
+// disabled, which makes lookups fall back to the plaintext column.
+func emailHash(crypt *encrypt.Keyring, email string) sql.NullString {
+	hash := crypt.BlindIndex(email)
+	return sql.NullString{
+		String: hash,
+		Valid:  hash != "",
+	}
+}
+
+// =============================================================================
+
+type address struct {
+	ID         uuid.UUID      `db:"address_id"`
+	CustomerID uuid.UUID      `db:"customer_id"`
+	Kind       string         `db:"kind"`
+	Line1      string         `db:"line1"`
+	Line2      sql.NullString `db:"line2"`
+	City       string         `db:"city"`
+	State      string         `db:"state"`
+	ZipCode    string         `db:"zip_code"`
+	Country    string         `db:"country"`
+}
+
+func toDBAddresses(addrs []customerbus.Address) []address {
+	dbAddrs := make([]address, len(addrs))
+	for i, a := range addrs {
+		dbAddrs[i] = address{
+			ID:         a.ID,
+			CustomerID: a.CustomerID,
+			Kind:       a.Kind.String(),
+			Line1:      a.Line1,
+			Line2: sql.NullString{
+				String: a.Line2,
+				Valid:  a.Line2 != "",
+			},
+			City:    a.City,
+			State:   a.State,
+			ZipCode: a.ZipCode,
+			Country: a.Country,
+		}
+	}
+	return dbAddrs
+}
+
+func toBusAddresses(dbAddrs []address) ([]customerbus.Address, error) {
+	addrs := make([]customerbus.Address, len(dbAddrs))
+	for i, a := range dbAddrs {
+		kind, err := customerbus.ParseAddressKind(a.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("parse kind: %w", err)
+		}
+
+		addrs[i] = customerbus.Address{
+			ID:         a.ID,
+			CustomerID: a.CustomerID,
+			Kind:       kind,
+			Line1:      a.Line1,
+			Line2:      a.Line2.String,
+			City:       a.City,
+			State:      a.State,
+			ZipCode:    a.ZipCode,
+			Country:    a.Country,
+		}
+	}
+	return addrs, nil
+}
@@ -0,0 +1,17 @@
+package customerdb
+
+import (
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	customerbus.OrderByID:          "customer_id",
+	customerbus.OrderByName:        "name",
+	customerbus.OrderByEmail:       "email",
+	customerbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return order.Clause(orderBy, orderByFields, "customer_id")
+}
@@ -0,0 +1,182 @@
+// Package inventorybus provides business access to inventory domain.
+package inventorybus
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound            = errs.Newf(errs.NotFound, "stock not found")
+	ErrInsufficientStock   = errs.Newf(errs.FailedPrecondition, "insufficient stock")
+	ErrReservationExpired  = errs.Newf(errs.FailedPrecondition, "reservation expired")
+	ErrOnHandBelowReserved = errs.Newf(errs.FailedPrecondition, "on hand quantity below reserved quantity")
+)
+
+// sweepLimit bounds how many expired reservations a single sweep releases so
+// a backlog doesn't hold locks for long.
+const sweepLimit = 500
+
+//go:generate moq -pkg inventorymock -out inventorymock/inventorymock.go . Storer
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Upsert(ctx context.Context, stock Stock) error
+	QueryBySKU(ctx context.Context, sku string) (Stock, error)
+	Reserve(ctx context.Context, reservations []Reservation) error
+	Commit(ctx context.Context, orderID uuid.UUID, now time.Time) (int, error)
+	Release(ctx context.Context, orderID uuid.UUID) (int, error)
+	ReleaseExpired(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// Business manages the set of APIs for inventory access.
+type Business struct {
+	log     *logger.Logger
+	storer  Storer
+	holdFor time.Duration
+}
+
+// NewBusiness constructs an inventory business API for use. Reservations are
+// held for the holdFor duration before the sweep releases them.
+func NewBusiness(log *logger.Logger, storer Storer, holdFor time.Duration) *Business {
+	return &Business{
+		log:     log,
+		storer:  storer,
+		holdFor: holdFor,
+	}
+}
+
+// SetStock creates or replaces the stock level of a SKU. The on hand quantity
+// can't drop below what is currently reserved.
+func (b *Business) SetStock(ctx context.Context, sku string, us UpdateStock) (Stock, error) {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.setstock")
+	defer span.End()
+
+	stock := Stock{
+		SKU:         sku,
+		Name:        us.Name,
+		OnHand:      us.OnHand,
+		DateUpdated: time.Now(),
+	}
+
+	if err := b.storer.Upsert(ctx, stock); err != nil {
+		return Stock{}, fmt.Errorf("upsert: %w", err)
+	}
+
+	return b.QueryBySKU(ctx, sku)
+}
+
+// QueryBySKU finds the stock of the specified SKU.
+func (b *Business) QueryBySKU(ctx context.Context, sku string) (Stock, error) {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.querybysku")
+	defer span.End()
+
+	stock, err := b.storer.QueryBySKU(ctx, sku)
+	if err != nil {
+		return Stock{}, fmt.Errorf("query: sku[%s]: %w", sku, err)
+	}
+
+	return stock, nil
+}
+
+// Reserve holds stock for every line of an order. Either all lines are
+// reserved or none are, and ErrInsufficientStock is returned when any SKU
+// can't cover the requested quantity.
+func (b *Business) Reserve(ctx context.Context, orderID uuid.UUID, lines []Line) ([]Reservation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.reserve")
+	defer span.End()
+
+	now := time.Now()
+
+	// Lines for the same SKU are merged and sorted so concurrent checkouts
+	// always lock inventory rows in the same order and can't deadlock.
+	merged := make(map[string]int)
+	for _, l := range lines {
+		merged[l.SKU] += l.Quantity
+	}
+
+	reservations := make([]Reservation, 0, len(merged))
+	for sku, qty := range merged {
+		reservations = append(reservations, Reservation{
+			ID:          id.New(),
+			OrderID:     orderID,
+			SKU:         sku,
+			Quantity:    qty,
+			Status:      ReservationHeld,
+			ExpiresAt:   now.Add(b.holdFor),
+			DateCreated: now,
+		})
+	}
+
+	slices.SortFunc(reservations, func(a, b Reservation) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+
+	if err := b.storer.Reserve(ctx, reservations); err != nil {
+		return nil, fmt.Errorf("reserve: orderID[%s]: %w", orderID, err)
+	}
+
+	return reservations, nil
+}
+
+// Commit turns the held reservations of an order into a stock deduction. It
+// returns ErrReservationExpired when nothing is held anymore, which happens
+// once the reservations pass their expiry.
+func (b *Business) Commit(ctx context.Context, orderID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.commit")
+	defer span.End()
+
+	n, err := b.storer.Commit(ctx, orderID, time.Now())
+	if err != nil {
+		return fmt.Errorf("commit: orderID[%s]: %w", orderID, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("commit: orderID[%s]: %w", orderID, ErrReservationExpired)
+	}
+
+	return nil
+}
+
+// Release gives back the stock of an order, whether it was only held or
+// already committed.
+func (b *Business) Release(ctx context.Context, orderID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.release")
+	defer span.End()
+
+	if _, err := b.storer.Release(ctx, orderID); err != nil {
+		return fmt.Errorf("release: orderID[%s]: %w", orderID, err)
+	}
+
+	return nil
+}
+
+// ReleaseExpired releases held reservations that passed their expiry. It's
+// intended to run periodically from the scheduler and is safe to run from
+// several instances at once.
+func (b *Business) ReleaseExpired(ctx context.Context) error {
+	ctx, span := otel.AddSpan(ctx, "business.inventorybus.releaseexpired")
+	defer span.End()
+
+	n, err := b.storer.ReleaseExpired(ctx, time.Now(), sweepLimit)
+	if err != nil {
+		return fmt.Errorf("release expired: %w", err)
+	}
+
+	if n > 0 {
+		b.log.Info(ctx, "inventory", "status", "released expired reservations", "count", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package inventorybus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock represents the inventory level of a single SKU.
+type Stock struct {
+	SKU         string
+	Name        string
+	OnHand      int
+	Reserved    int
+	DateUpdated time.Time
+}
+
+// Available returns the quantity that can still be reserved.
+func (s Stock) Available() int {
+	return s.OnHand - s.Reserved
+}
+
+// Reservation holds stock of one SKU for an order until it's committed,
+// released or expires.
+type Reservation struct {
+	ID          uuid.UUID
+	OrderID     uuid.UUID
+	SKU         string
+	Quantity    int
+	Status      ReservationStatus
+	ExpiresAt   time.Time
+	DateCreated time.Time
+}
+
+// Line is a quantity of a SKU requested by an order.
+type Line struct {
+	SKU      string
+	Quantity int
+}
+
+// UpdateStock contains the information needed to set the stock of a SKU.
+type UpdateStock struct {
+	Name   string
+	OnHand int
+}
@@ -0,0 +1,54 @@
+package inventorybus
+
+import "fmt"
+
+// The set of reservation statuses that can be used.
+var (
+	ReservationHeld      = newReservationStatus("HELD")
+	ReservationCommitted = newReservationStatus("COMMITTED")
+	ReservationReleased  = newReservationStatus("RELEASED")
+)
+
+// =============================================================================
+
+// Set of known reservation statuses.
+var reservationStatuses = make(map[string]ReservationStatus)
+
+// ReservationStatus represents a state in the reservation lifecycle.
+type ReservationStatus struct {
+	value string
+}
+
+func newReservationStatus(status string) ReservationStatus {
+	s := ReservationStatus{status}
+	reservationStatuses[status] = s
+	return s
+}
+
+// String returns the name of the status.
+func (s ReservationStatus) String() string {
+	return s.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s ReservationStatus) Equal(s2 ReservationStatus) bool {
+	return s.value == s2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (s ReservationStatus) MarshalText() ([]byte, error) {
+	return []byte(s.value), nil
+}
+
+// =============================================================================
+
+// ParseReservationStatus parses the string value and returns a status if one
+// exists.
+func ParseReservationStatus(value string) (ReservationStatus, error) {
+	status, exists := reservationStatuses[value]
+	if !exists {
+		return ReservationStatus{}, fmt.Errorf("invalid reservation status %q", value)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,264 @@
+// Package inventorydb contains inventory related CRUD functionality.
+package inventorydb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for inventory database access.
+type Store struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Upsert inserts the stock of a SKU or replaces its name and on hand
+// quantity. The reserved quantity is only ever changed by reservations.
+func (s *Store) Upsert(ctx context.Context, st inventorybus.Stock) error {
+	const q = `
+	INSERT INTO inventory
+		(sku, name, on_hand, reserved, date_updated)
+	VALUES
+		(:sku, :name, :on_hand, 0, :date_updated)
+	ON CONFLICT (sku) DO UPDATE SET
+		"name" = EXCLUDED.name,
+		"on_hand" = EXCLUDED.on_hand,
+		"date_updated" = EXCLUDED.date_updated`
+
+	if _, err := sqlx.NamedExecContext(ctx, sqldb.Executor(ctx, s.db), q, toDBStock(st)); err != nil {
+		if sqldb.IsCheckViolation(err) {
+			return fmt.Errorf("namedexeccontext: %w", inventorybus.ErrOnHandBelowReserved)
+		}
+		return fmt.Errorf("namedexeccontext: %w", sqldb.Translate(err))
+	}
+
+	return nil
+}
+
+// QueryBySKU gets the stock of the specified SKU from the database.
+func (s *Store) QueryBySKU(ctx context.Context, sku string) (inventorybus.Stock, error) {
+	const q = `
+	SELECT
+		sku, name, on_hand, reserved, date_updated
+	FROM
+		inventory
+	WHERE
+		sku = $1`
+
+	var dbStock stock
+	if err := sqlx.GetContext(ctx, sqldb.Executor(ctx, s.db), &dbStock, q, sku); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return inventorybus.Stock{}, fmt.Errorf("getcontext: %w", inventorybus.ErrNotFound)
+		}
+		return inventorybus.Stock{}, fmt.Errorf("getcontext: %w", err)
+	}
+
+	return toBusStock(dbStock), nil
+}
+
+// Reserve holds stock for the reservations in a single transaction. The
+// inventory rows of every SKU are locked first, in SKU order so concurrent
+// checkouts can't deadlock, and the availability is checked against the
+// locked quantities so they can't oversell. The reservations must be sorted
+// by SKU.
+func (s *Store) Reserve(ctx context.Context, reservations []inventorybus.Reservation) error {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	skus := make([]string, len(reservations))
+	for i, r := range reservations {
+		skus[i] = r.SKU
+	}
+
+	const qs = `
+	SELECT
+		sku, name, on_hand, reserved, date_updated
+	FROM
+		inventory
+	WHERE
+		sku = ANY(:skus)
+	ORDER BY
+		sku`
+
+	var locked []stock
+	if err := sqldb.NamedQueryForUpdate(ctx, s.log, tx, sqldb.LockWait, qs, map[string]any{"skus": skus}, &locked); err != nil {
+		return fmt.Errorf("lock inventory: %w", err)
+	}
+
+	available := make(map[string]int, len(locked))
+	for _, st := range locked {
+		available[st.SKU] = st.OnHand - st.Reserved
+	}
+
+	const qu = `
+	UPDATE
+		inventory
+	SET
+		"reserved" = reserved + $2,
+		"date_updated" = $3
+	WHERE
+		sku = $1`
+
+	const qi = `
+	INSERT INTO reservations
+		(reservation_id, order_id, sku, quantity, status, expires_at, date_created)
+	VALUES
+		(:reservation_id, :order_id, :sku, :quantity, :status, :expires_at, :date_created)`
+
+	for _, r := range reservations {
+		// A SKU without stock is treated like one that ran out.
+		if available[r.SKU] < r.Quantity {
+			return fmt.Errorf("sku[%s]: %w", r.SKU, inventorybus.ErrInsufficientStock)
+		}
+
+		if _, err := tx.ExecContext(ctx, qu, r.SKU, r.Quantity, r.DateCreated.UTC()); err != nil {
+			return fmt.Errorf("reserve sku[%s]: %w", r.SKU, sqldb.Translate(err))
+		}
+
+		if _, err := tx.NamedExecContext(ctx, qi, toDBReservation(r)); err != nil {
+			return fmt.Errorf("insert reservation: %w", sqldb.Translate(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// Commit deducts the unexpired held reservations of an order from the stock
+// and returns the number of reservations committed.
+func (s *Store) Commit(ctx context.Context, orderID uuid.UUID, now time.Time) (int, error) {
+	const q = `
+	UPDATE
+		reservations
+	SET
+		"status" = $3
+	WHERE
+		order_id = $1 AND status = $4 AND expires_at > $2
+	RETURNING
+		reservation_id, order_id, sku, quantity, status, expires_at, date_created`
+
+	args := []any{orderID, now.UTC(), inventorybus.ReservationCommitted.String(), inventorybus.ReservationHeld.String()}
+
+	return s.settle(ctx, q, args, -1, -1)
+}
+
+// Release gives back the stock of an order's reservations, dropping held
+// quantities and restocking committed ones, and returns the number of
+// reservations released.
+func (s *Store) Release(ctx context.Context, orderID uuid.UUID) (int, error) {
+	const q = `
+	UPDATE
+		reservations
+	SET
+		"status" = $2
+	WHERE
+		order_id = $1 AND status = $3
+	RETURNING
+		reservation_id, order_id, sku, quantity, status, expires_at, date_created`
+
+	released := inventorybus.ReservationReleased.String()
+
+	held, err := s.settle(ctx, q, []any{orderID, released, inventorybus.ReservationHeld.String()}, 0, -1)
+	if err != nil {
+		return 0, fmt.Errorf("release held: %w", err)
+	}
+
+	committed, err := s.settle(ctx, q, []any{orderID, released, inventorybus.ReservationCommitted.String()}, 1, 0)
+	if err != nil {
+		return 0, fmt.Errorf("release committed: %w", err)
+	}
+
+	return held + committed, nil
+}
+
+// ReleaseExpired releases up to limit held reservations that expired before
+// now. Rows locked by another sweep or an in-flight commit are skipped, so
+// several instances can run the sweep concurrently.
+func (s *Store) ReleaseExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	const q = `
+	UPDATE
+		reservations
+	SET
+		"status" = $3
+	WHERE
+		reservation_id IN (
+			SELECT
+				reservation_id
+			FROM
+				reservations
+			WHERE
+				status = $4 AND expires_at <= $1
+			ORDER BY
+				expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		reservation_id, order_id, sku, quantity, status, expires_at, date_created`
+
+	args := []any{now.UTC(), limit, inventorybus.ReservationReleased.String(), inventorybus.ReservationHeld.String()}
+
+	return s.settle(ctx, q, args, 0, -1)
+}
+
+// settle runs a reservation status change and applies the quantities of the
+// affected reservations to the inventory, multiplied by the on hand and
+// reserved factors, in the same transaction.
+func (s *Store) settle(ctx context.Context, q string, args []any, onHand int, reserved int) (int, error) {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var rows []reservation
+	if err := tx.SelectContext(ctx, &rows, q, args...); err != nil {
+		return 0, fmt.Errorf("update reservations: %w", err)
+	}
+
+	const qu = `
+	UPDATE
+		inventory
+	SET
+		"on_hand" = on_hand + $2,
+		"reserved" = reserved + $3,
+		"date_updated" = $4
+	WHERE
+		sku = $1`
+
+	now := time.Now().UTC()
+	for _, d := range toDeltas(rows, onHand, reserved) {
+		if _, err := tx.ExecContext(ctx, qu, d.SKU, d.OnHand, d.Reserved, now); err != nil {
+			return 0, fmt.Errorf("update inventory sku[%s]: %w", d.SKU, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	return len(rows), nil
+}
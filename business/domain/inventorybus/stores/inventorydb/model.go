@@ -0,0 +1,96 @@
+package inventorydb
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/google/uuid"
+)
+
+type stock struct {
+	SKU         string    `db:"sku"`
+	Name        string    `db:"name"`
+	OnHand      int       `db:"on_hand"`
+	Reserved    int       `db:"reserved"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBStock(s inventorybus.Stock) stock {
+	return stock{
+		SKU:         s.SKU,
+		Name:        s.Name,
+		OnHand:      s.OnHand,
+		Reserved:    s.Reserved,
+		DateUpdated: s.DateUpdated.UTC(),
+	}
+}
+
+func toBusStock(db stock) inventorybus.Stock {
+	return inventorybus.Stock{
+		SKU:         db.SKU,
+		Name:        db.Name,
+		OnHand:      db.OnHand,
+		Reserved:    db.Reserved,
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+// =============================================================================
+
+type reservation struct {
+	ID          uuid.UUID `db:"reservation_id"`
+	OrderID     uuid.UUID `db:"order_id"`
+	SKU         string    `db:"sku"`
+	Quantity    int       `db:"quantity"`
+	Status      string    `db:"status"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBReservation(r inventorybus.Reservation) reservation {
+	return reservation{
+		ID:          r.ID,
+		OrderID:     r.OrderID,
+		SKU:         r.SKU,
+		Quantity:    r.Quantity,
+		Status:      r.Status.String(),
+		ExpiresAt:   r.ExpiresAt.UTC(),
+		DateCreated: r.DateCreated.UTC(),
+	}
+}
+
+// =============================================================================
+
+// delta is a change applied to the inventory row of a SKU.
+type delta struct {
+	SKU      string
+	OnHand   int
+	Reserved int
+}
+
+// toDeltas aggregates the quantity of each SKU into a delta sorted by SKU, so
+// rows are always locked in the same order.
+func toDeltas(rows []reservation, onHand int, reserved int) []delta {
+	bySKU := make(map[string]int)
+	var skus []string
+	for _, r := range rows {
+		if _, exists := bySKU[r.SKU]; !exists {
+			skus = append(skus, r.SKU)
+		}
+		bySKU[r.SKU] += r.Quantity
+	}
+
+	slices.Sort(skus)
+
+	deltas := make([]delta, len(skus))
+	for i, sku := range skus {
+		deltas[i] = delta{
+			SKU:      sku,
+			OnHand:   onHand * bySKU[sku],
+			Reserved: reserved * bySKU[sku],
+		}
+	}
+
+	return deltas
+}
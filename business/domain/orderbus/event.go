@@ -0,0 +1,51 @@
+package orderbus
+
+import (
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// DomainName represents the name of this domain for delegate events.
+const DomainName = "order"
+
+// Set of delegate actions raised by this domain. The params of every action
+// carry snapshots of the order so subscribers don't have to query it.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+)
+
+// ActionCreatedParms represents the parameters of the created action.
+type ActionCreatedParms struct {
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	After      Order
+}
+
+// ActionCreatedData constructs the data for the created action.
+func ActionCreatedData(ord Order) (delegate.Data, error) {
+	return delegate.NewData(DomainName, ActionCreated, ActionCreatedParms{
+		OrderID:    ord.ID,
+		CustomerID: ord.CustomerID,
+		After:      ord,
+	})
+}
+
+// ActionUpdatedParms represents the parameters of the updated action, raised
+// when the order moves to another status.
+type ActionUpdatedParms struct {
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	Before     Order
+	After      Order
+}
+
+// ActionUpdatedData constructs the data for the updated action.
+func ActionUpdatedData(before Order, after Order) (delegate.Data, error) {
+	return delegate.NewData(DomainName, ActionUpdated, ActionUpdatedParms{
+		OrderID:    after.ID,
+		CustomerID: after.CustomerID,
+		Before:     before,
+		After:      after,
+	})
+}
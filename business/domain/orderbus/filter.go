@@ -0,0 +1,18 @@
+package orderbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field means the query isn't filtered on it.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	UserID           *uuid.UUID
+	CustomerID       *uuid.UUID
+	Status           *Status
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
@@ -0,0 +1,63 @@
+package orderbus
+
+import (
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/google/uuid"
+)
+
+// Order represents an order header with its line items. The amounts are
+// fixed when the order is priced at creation.
+type Order struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	CustomerID      uuid.UUID // Zero for orders placed before customer records existed
+	Status          Status
+	Currency        money.Currency
+	Subtotal        money.Money
+	Discount        money.Money
+	Tax             money.Money
+	Total           money.Money
+	TaxJurisdiction string
+	IdempotencyKey  string // Client supplied, empty when none was sent
+	Items           []Item
+	DateCreated     time.Time
+	DateUpdated     time.Time
+}
+
+// Item represents a single line item of an order.
+type Item struct {
+	ID        uuid.UUID
+	OrderID   uuid.UUID
+	SKU       string
+	Name      string
+	Quantity  int
+	UnitPrice money.Money
+}
+
+// Total returns the line total before discount and tax.
+func (i Item) Total() money.Money {
+	return money.New(int64(i.Quantity)*i.UnitPrice.Minor(), i.UnitPrice.Currency())
+}
+
+// NewOrder is what we require from clients when adding an Order. A retried
+// request carrying the same IdempotencyKey for the user gets the order the
+// first request created.
+type NewOrder struct {
+	UserID         uuid.UUID
+	CustomerID     uuid.UUID
+	Currency       money.Currency
+	Discount       pricing.Rate
+	IdempotencyKey string
+	Items          []NewItem
+}
+
+// NewItem is what we require from clients when adding an Item.
+type NewItem struct {
+	SKU       string
+	Name      string
+	Quantity  int
+	UnitPrice money.Money // In the currency of the order
+}
@@ -0,0 +1,15 @@
+package orderbus
+
+import "github.com/AlmirSai/service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "order_id"
+	OrderByUserID      = "user_id"
+	OrderByStatus      = "status"
+	OrderByTotal       = "total"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,375 @@
+// Package orderbus provides business access to order domain.
+package orderbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound          = errs.Newf(errs.NotFound, "order not found")
+	ErrNoItems           = errs.Newf(errs.InvalidArgument, "order must contain at least one item")
+	ErrInvalidTransition = errs.Newf(errs.FailedPrecondition, "order status transition not allowed")
+	ErrCurrency          = errs.Newf(errs.InvalidArgument, "item price must be in the currency of the order")
+	ErrIdempotencyKey    = errs.Newf(errs.AlreadyExists, "idempotency key already used")
+	ErrIdempotencyReuse  = errs.Newf(errs.FailedPrecondition, "idempotency key was used for a different order")
+)
+
+//go:generate moq -pkg ordermock -out ordermock/ordermock.go . Storer CustomerFinder Inventory
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, ord Order) error
+	UpdateStatus(ctx context.Context, ord Order) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+	QueryByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (Order, error)
+}
+
+// CustomerFinder declares the behavior this package needs from the customer
+// domain.
+type CustomerFinder interface {
+	QueryByID(ctx context.Context, customerID uuid.UUID) (customerbus.Customer, error)
+}
+
+// Inventory declares the behavior this package needs from the inventory
+// domain to hold stock for an order.
+type Inventory interface {
+	Reserve(ctx context.Context, orderID uuid.UUID, lines []inventorybus.Line) ([]inventorybus.Reservation, error)
+	Commit(ctx context.Context, orderID uuid.UUID) error
+	Release(ctx context.Context, orderID uuid.UUID) error
+}
+
+// Business manages the set of APIs for order access.
+type Business struct {
+	log          *logger.Logger
+	delegate     *delegate.Delegate
+	tran         sqldb.Transactor
+	customerBus  CustomerFinder
+	inventoryBus Inventory
+	pricing      *pricing.Calculator
+	storer       Storer
+}
+
+// NewBusiness constructs an order business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, tran sqldb.Transactor, customerBus CustomerFinder, inventoryBus Inventory, calc *pricing.Calculator, storer Storer) *Business {
+	return &Business{
+		log:          log,
+		delegate:     delegate,
+		tran:         tran,
+		customerBus:  customerBus,
+		inventoryBus: inventoryBus,
+		pricing:      calc,
+		storer:       storer,
+	}
+}
+
+// Create adds a new order with its line items to the system. The order is
+// priced with the tax of the customer's jurisdiction, then stock for the items
+// is reserved and the order is refused when it can't be covered. When the
+// idempotency key of the new order was used before by the same user, the
+// order created then is returned and nothing new is created; the bool
+// reports whether the order was created by this call. Reusing a key for an
+// order that differs fails with ErrIdempotencyReuse.
+func (b *Business) Create(ctx context.Context, no NewOrder) (Order, bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.create")
+	defer span.End()
+
+	if no.IdempotencyKey != "" {
+		ord, err := b.storer.QueryByIdempotencyKey(ctx, no.UserID, no.IdempotencyKey)
+		switch {
+		case err == nil:
+			return b.replay(ord, no)
+		case !errors.Is(err, ErrNotFound):
+			return Order{}, false, fmt.Errorf("idempotency key: %w", err)
+		}
+	}
+
+	if len(no.Items) == 0 {
+		return Order{}, false, ErrNoItems
+	}
+
+	cus, err := b.customerBus.QueryByID(ctx, no.CustomerID)
+	if err != nil {
+		// An unknown customer is a problem with the request, not a missing order.
+		if errors.Is(err, customerbus.ErrNotFound) {
+			return Order{}, false, errs.New(errs.InvalidArgument, fmt.Errorf("customer: %w", err))
+		}
+		return Order{}, false, fmt.Errorf("customer: %w", err)
+	}
+
+	now := time.Now()
+	orderID := id.New()
+
+	lines := make([]inventorybus.Line, len(no.Items))
+	priced := make([]pricing.Line, len(no.Items))
+	items := make([]Item, len(no.Items))
+	for i, ni := range no.Items {
+		if !ni.UnitPrice.Currency().Equal(no.Currency) {
+			return Order{}, false, fmt.Errorf("item[%d]: %w", i, ErrCurrency)
+		}
+
+		lines[i] = inventorybus.Line{
+			SKU:      ni.SKU,
+			Quantity: ni.Quantity,
+		}
+		priced[i] = pricing.Line{
+			Quantity:  ni.Quantity,
+			UnitPrice: ni.UnitPrice.Minor(),
+		}
+
+		items[i] = Item{
+			ID:        id.New(),
+			OrderID:   orderID,
+			SKU:       ni.SKU,
+			Name:      ni.Name,
+			Quantity:  ni.Quantity,
+			UnitPrice: ni.UnitPrice,
+		}
+	}
+
+	quote, err := b.pricing.Price(priced, no.Discount, b.pricing.Jurisdiction(jurisdictionCodes(cus)...))
+	if err != nil {
+		return Order{}, false, fmt.Errorf("price: %w", err)
+	}
+
+	ord := Order{
+		ID:              orderID,
+		UserID:          no.UserID,
+		CustomerID:      no.CustomerID,
+		Status:          StatusPending,
+		Currency:        no.Currency,
+		Subtotal:        money.New(quote.Subtotal, no.Currency),
+		Discount:        money.New(quote.Discount, no.Currency),
+		Tax:             money.New(quote.Tax, no.Currency),
+		Total:           money.New(quote.Total, no.Currency),
+		TaxJurisdiction: quote.Jurisdiction,
+		IdempotencyKey:  no.IdempotencyKey,
+		Items:           items,
+		DateCreated:     now,
+		DateUpdated:     now,
+	}
+
+	if err := createRules.Check(ctx, ord); err != nil {
+		return Order{}, false, err
+	}
+
+	// The reservation and the order are written in one transaction, so a
+	// failure to store the order doesn't leave stock held.
+	err = b.tran.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := b.inventoryBus.Reserve(ctx, orderID, lines); err != nil {
+			return fmt.Errorf("inventory: %w", err)
+		}
+
+		if err := b.storer.Create(ctx, ord); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		data, err := ActionCreatedData(ord)
+		if err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		if err := b.delegate.Call(ctx, data); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		// A concurrent request with the same key stored its order first.
+		if no.IdempotencyKey != "" && errors.Is(err, ErrIdempotencyKey) {
+			ord, err := b.storer.QueryByIdempotencyKey(ctx, no.UserID, no.IdempotencyKey)
+			if err != nil {
+				return Order{}, false, fmt.Errorf("idempotency key: %w", err)
+			}
+			return b.replay(ord, no)
+		}
+		return Order{}, false, err
+	}
+
+	return ord, true, nil
+}
+
+// replay returns the order stored for an idempotency key when the new order
+// asks for the same thing, so a retry can't silently get a different order.
+func (b *Business) replay(ord Order, no NewOrder) (Order, bool, error) {
+	if ord.CustomerID != no.CustomerID || !ord.Currency.Equal(no.Currency) || len(ord.Items) != len(no.Items) {
+		return Order{}, false, ErrIdempotencyReuse
+	}
+
+	// The stored items come back sorted, so they're matched as a multiset.
+	type itemKey struct {
+		sku       string
+		name      string
+		quantity  int
+		unitPrice int64
+	}
+
+	items := make(map[itemKey]int, len(no.Items))
+	priced := make([]pricing.Line, len(no.Items))
+	for i, ni := range no.Items {
+		items[itemKey{ni.SKU, ni.Name, ni.Quantity, ni.UnitPrice.Minor()}]++
+
+		priced[i] = pricing.Line{
+			Quantity:  ni.Quantity,
+			UnitPrice: ni.UnitPrice.Minor(),
+		}
+	}
+
+	for _, item := range ord.Items {
+		k := itemKey{item.SKU, item.Name, item.Quantity, item.UnitPrice.Minor()}
+		if items[k] == 0 {
+			return Order{}, false, ErrIdempotencyReuse
+		}
+		items[k]--
+	}
+
+	// The discount is independent of the tax, so it can be compared without
+	// the jurisdiction of the customer.
+	quote, err := b.pricing.Price(priced, no.Discount, pricing.Jurisdiction{})
+	if err != nil || quote.Discount != ord.Discount.Minor() {
+		return Order{}, false, ErrIdempotencyReuse
+	}
+
+	return ord, false, nil
+}
+
+// Transition moves the order to the next status if the lifecycle allows it.
+// Paying for an order commits its reserved stock and cancelling it gives the
+// stock back.
+func (b *Business) Transition(ctx context.Context, ord Order, next Status) (Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.transition")
+	defer span.End()
+
+	if !ord.Status.CanTransitionTo(next) {
+		return Order{}, fmt.Errorf("transition %s -> %s: %w", ord.Status, next, ErrInvalidTransition)
+	}
+
+	before := ord
+
+	ord.Status = next
+	ord.DateUpdated = time.Now()
+
+	err := b.tran.WithTx(ctx, func(ctx context.Context) error {
+		if next == StatusPaid {
+			if err := b.inventoryBus.Commit(ctx, ord.ID); err != nil {
+				return fmt.Errorf("inventory: %w", err)
+			}
+		}
+
+		if err := b.storer.UpdateStatus(ctx, ord); err != nil {
+			return fmt.Errorf("update status: %w", err)
+		}
+
+		if next == StatusCancelled {
+			b.release(ctx, ord.ID)
+		}
+
+		data, err := ActionUpdatedData(before, ord)
+		if err != nil {
+			return fmt.Errorf("update status: %w", err)
+		}
+
+		if err := b.delegate.Call(ctx, data); err != nil {
+			return fmt.Errorf("update status: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Cancel cancels an order that hasn't shipped yet.
+func (b *Business) Cancel(ctx context.Context, ord Order) (Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.cancel")
+	defer span.End()
+
+	return b.Transition(ctx, ord, StatusCancelled)
+}
+
+// Query retrieves a list of existing orders.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.query")
+	defer span.End()
+
+	orders, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return orders, nil
+}
+
+// Count returns the total number of orders.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the order by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.querybyid")
+	defer span.End()
+
+	ord, err := b.storer.QueryByID(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("query: orderID[%s]: %w", orderID, err)
+	}
+
+	return ord, nil
+}
+
+// release gives back the stock of an order. A failure is logged rather than
+// returned, the order stays cancelled and the stock is left for an operator
+// to reconcile.
+func (b *Business) release(ctx context.Context, orderID uuid.UUID) {
+	if err := b.inventoryBus.Release(ctx, orderID); err != nil {
+		b.log.Error(ctx, "order", "status", "releasing inventory", "orderID", orderID, "error", err)
+	}
+}
+
+// jurisdictionCodes lists the tax jurisdictions of a customer from the most
+// to the least specific. The shipping address wins over the billing address.
+func jurisdictionCodes(cus customerbus.Customer) []string {
+	var codes []string
+	for _, kind := range []customerbus.AddressKind{customerbus.AddressShipping, customerbus.AddressBilling} {
+		for _, a := range cus.Addresses {
+			if a.Kind != kind {
+				continue
+			}
+			if a.State != "" {
+				codes = append(codes, a.Country+"-"+a.State)
+			}
+			codes = append(codes, a.Country)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,257 @@
+package orderbus_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/domain/orderbus/ordermock"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+func Test_CreateIdempotent(t *testing.T) {
+	t.Parallel()
+
+	b, storer, inventory := newBusiness(t)
+
+	usd, err := money.ParseCurrency("USD")
+	if err != nil {
+		t.Fatalf("should be able to parse the currency: %s", err)
+	}
+
+	no := orderbus.NewOrder{
+		UserID:         uuid.New(),
+		CustomerID:     uuid.New(),
+		Currency:       usd,
+		IdempotencyKey: "checkout-42",
+		Items: []orderbus.NewItem{
+			{SKU: "SKU-1", Name: "Widget", Quantity: 2, UnitPrice: money.New(1000, usd)},
+		},
+	}
+
+	first, created, err := b.Create(context.Background(), no)
+	if err != nil {
+		t.Fatalf("should be able to create the order: %s", err)
+	}
+
+	if !created {
+		t.Errorf("should report the first order as created")
+	}
+
+	second, created, err := b.Create(context.Background(), no)
+	if err != nil {
+		t.Fatalf("should be able to send the same key again: %s", err)
+	}
+
+	if created {
+		t.Errorf("should report the replayed order as not created")
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("should get the first order back, got %s, exp %s", second.ID, first.ID)
+	}
+
+	if n := len(storer.CreateCalls()); n != 1 {
+		t.Errorf("should store one order, got %d", n)
+	}
+
+	if n := len(inventory.ReserveCalls()); n != 1 {
+		t.Errorf("should reserve stock once, got %d", n)
+	}
+
+	// The key is scoped to the user, another user gets a new order.
+	no.UserID = uuid.New()
+	third, created, err := b.Create(context.Background(), no)
+	if err != nil {
+		t.Fatalf("should be able to create the order: %s", err)
+	}
+
+	if !created {
+		t.Errorf("should report the order of another user as created")
+	}
+
+	if third.ID == first.ID {
+		t.Errorf("should create a new order for another user")
+	}
+}
+
+func Test_CreateIdempotentReuse(t *testing.T) {
+	t.Parallel()
+
+	usd, err := money.ParseCurrency("USD")
+	if err != nil {
+		t.Fatalf("should be able to parse the currency: %s", err)
+	}
+
+	eur, err := money.ParseCurrency("EUR")
+	if err != nil {
+		t.Fatalf("should be able to parse the currency: %s", err)
+	}
+
+	half, err := pricing.ParseRate("50%")
+	if err != nil {
+		t.Fatalf("should be able to parse the rate: %s", err)
+	}
+
+	base := func() orderbus.NewOrder {
+		return orderbus.NewOrder{
+			UserID:         uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f"),
+			CustomerID:     uuid.MustParse("e8a4a1f2-6f0d-4a53-8f4b-0d0c2b18b702"),
+			Currency:       usd,
+			IdempotencyKey: "checkout-42",
+			Items: []orderbus.NewItem{
+				{SKU: "SKU-1", Name: "Widget", Quantity: 2, UnitPrice: money.New(1000, usd)},
+				{SKU: "SKU-2", Name: "Gadget", Quantity: 1, UnitPrice: money.New(500, usd)},
+			},
+		}
+	}
+
+	table := []struct {
+		name   string
+		change func(no *orderbus.NewOrder)
+		reuse  bool
+	}{
+		{
+			name:   "same",
+			change: func(no *orderbus.NewOrder) {},
+		},
+		{
+			name: "items-reordered",
+			change: func(no *orderbus.NewOrder) {
+				no.Items[0], no.Items[1] = no.Items[1], no.Items[0]
+			},
+		},
+		{
+			name:   "customer",
+			change: func(no *orderbus.NewOrder) { no.CustomerID = uuid.New() },
+			reuse:  true,
+		},
+		{
+			name: "currency",
+			change: func(no *orderbus.NewOrder) {
+				no.Currency = eur
+				for i := range no.Items {
+					no.Items[i].UnitPrice = money.New(no.Items[i].UnitPrice.Minor(), eur)
+				}
+			},
+			reuse: true,
+		},
+		{
+			name:   "quantity",
+			change: func(no *orderbus.NewOrder) { no.Items[0].Quantity = 3 },
+			reuse:  true,
+		},
+		{
+			name:   "missing-item",
+			change: func(no *orderbus.NewOrder) { no.Items = no.Items[:1] },
+			reuse:  true,
+		},
+		{
+			name:   "discount",
+			change: func(no *orderbus.NewOrder) { no.Discount = half },
+			reuse:  true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _, _ := newBusiness(t)
+
+			first, _, err := b.Create(context.Background(), base())
+			if err != nil {
+				t.Fatalf("should be able to create the order: %s", err)
+			}
+
+			no := base()
+			tt.change(&no)
+
+			got, created, err := b.Create(context.Background(), no)
+			switch {
+			case tt.reuse:
+				if !errors.Is(err, orderbus.ErrIdempotencyReuse) {
+					t.Fatalf("should refuse to reuse the key, got %v", err)
+				}
+
+			case err != nil:
+				t.Fatalf("should be able to send the same order again: %s", err)
+
+			case created || got.ID != first.ID:
+				t.Errorf("should get the first order back, got %s created %t, exp %s", got.ID, created, first.ID)
+			}
+		})
+	}
+}
+
+// =============================================================================
+
+// newBusiness constructs the order business with an in-memory store that
+// enforces the idempotency key constraint like the database does.
+func newBusiness(t *testing.T) (*orderbus.Business, *ordermock.StorerMock, *ordermock.InventoryMock) {
+	t.Helper()
+
+	type userKey struct {
+		userID uuid.UUID
+		key    string
+	}
+
+	var mu sync.Mutex
+	orders := map[userKey]orderbus.Order{}
+
+	storer := ordermock.StorerMock{
+		CreateFunc: func(ctx context.Context, ord orderbus.Order) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ord.IdempotencyKey != "" {
+				k := userKey{ord.UserID, ord.IdempotencyKey}
+				if _, exists := orders[k]; exists {
+					return orderbus.ErrIdempotencyKey
+				}
+				orders[k] = ord
+			}
+			return nil
+		},
+		QueryByIdempotencyKeyFunc: func(ctx context.Context, userID uuid.UUID, key string) (orderbus.Order, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			ord, exists := orders[userKey{userID, key}]
+			if !exists {
+				return orderbus.Order{}, orderbus.ErrNotFound
+			}
+			return ord, nil
+		},
+	}
+
+	customers := ordermock.CustomerFinderMock{
+		QueryByIDFunc: func(ctx context.Context, customerID uuid.UUID) (customerbus.Customer, error) {
+			return customerbus.Customer{ID: customerID}, nil
+		},
+	}
+
+	inventory := ordermock.InventoryMock{
+		ReserveFunc: func(ctx context.Context, orderID uuid.UUID, lines []inventorybus.Line) ([]inventorybus.Reservation, error) {
+			return nil, nil
+		},
+	}
+
+	log := logger.NewNop()
+	b := orderbus.NewBusiness(log, delegate.New(log), tran{}, &customers, &inventory, pricing.New(nil), &storer)
+
+	return b, &storer, &inventory
+}
+
+// tran runs the function without a transaction.
+type tran struct{}
+
+func (tran) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,44 @@
+package orderbus
+
+import (
+	"context"
+
+	"github.com/AlmirSai/service/business/sdk/rules"
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// createRules are the invariants a priced order has to hold before it's
+// stored.
+var createRules = rules.New("order",
+	subtotalMatchesItems,
+	totalMatchesAmounts,
+)
+
+// subtotalMatchesItems checks the subtotal is the sum of the line totals.
+func subtotalMatchesItems(_ context.Context, ord Order) error {
+	var sum int64
+	for _, it := range ord.Items {
+		sum += it.Total().Minor()
+	}
+
+	if ord.Subtotal.Minor() != sum {
+		return rules.Violate(errs.InvalidArgument, "subtotal", "subtotal %d doesn't match the line items total %d", ord.Subtotal.Minor(), sum)
+	}
+
+	return nil
+}
+
+// totalMatchesAmounts checks the total is the subtotal less the discount plus
+// the tax, and that the discount doesn't exceed the subtotal.
+func totalMatchesAmounts(_ context.Context, ord Order) error {
+	if ord.Discount.Minor() > ord.Subtotal.Minor() {
+		return rules.Violate(errs.InvalidArgument, "discount", "discount %d exceeds the subtotal %d", ord.Discount.Minor(), ord.Subtotal.Minor())
+	}
+
+	want := ord.Subtotal.Minor() - ord.Discount.Minor() + ord.Tax.Minor()
+	if ord.Total.Minor() != want {
+		return rules.Violate(errs.InvalidArgument, "total", "total %d doesn't match subtotal less discount plus tax %d", ord.Total.Minor(), want)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package orderbus
+
+import "fmt"
+
+// The set of statuses an order can be in.
+var (
+	StatusPending   = newStatus("PENDING")
+	StatusPaid      = newStatus("PAID")
+	StatusShipped   = newStatus("SHIPPED")
+	StatusDelivered = newStatus("DELIVERED")
+	StatusCancelled = newStatus("CANCELLED")
+)
+
+// transitions lists the statuses each status is allowed to move to.
+var transitions = map[Status][]Status{
+	StatusPending: {StatusPaid, StatusCancelled},
+	StatusPaid:    {StatusShipped, StatusCancelled},
+	StatusShipped: {StatusDelivered},
+}
+
+// =============================================================================
+
+// Set of known statuses.
+var statuses = make(map[string]Status)
+
+// Status represents a status in the order lifecycle.
+type Status struct {
+	value string
+}
+
+func newStatus(status string) Status {
+	s := Status{status}
+	statuses[status] = s
+	return s
+}
+
+// String returns the name of the status.
+func (s Status) String() string {
+	return s.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Status) Equal(s2 Status) bool {
+	return s.value == s2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.value), nil
+}
+
+// UnmarshalText provides support for decoding a status that was marshaled.
+func (s *Status) UnmarshalText(data []byte) error {
+	status, err := ParseStatus(string(data))
+	if err != nil {
+		return err
+	}
+
+	*s = status
+
+	return nil
+}
+
+// CanTransitionTo reports whether an order may move from s to the next status.
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, t := range transitions[s] {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+
+// ParseStatus parses the string value and returns a status if one exists.
+func ParseStatus(value string) (Status, error) {
+	status, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid status %q", value)
+	}
+
+	return status, nil
+}
+
+// MustParseStatus parses the string value and returns a status if one exists.
+// If an error occurs the function panics.
+func MustParseStatus(value string) Status {
+	status, err := ParseStatus(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return status
+}
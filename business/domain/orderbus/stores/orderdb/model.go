@@ -0,0 +1,117 @@
+package orderdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/google/uuid"
+)
+
+type dbOrder struct {
+	ID              uuid.UUID      `db:"order_id"`
+	UserID          uuid.UUID      `db:"user_id"`
+	CustomerID      uuid.NullUUID  `db:"customer_id"`
+	Status          string         `db:"status"`
+	Currency        string         `db:"currency"`
+	Subtotal        int64          `db:"subtotal"`
+	Discount        int64          `db:"discount"`
+	Tax             int64          `db:"tax"`
+	Total           int64          `db:"total"`
+	TaxJurisdiction string         `db:"tax_jurisdiction"`
+	IdempotencyKey  sql.NullString `db:"idempotency_key"`
+	DateCreated     time.Time      `db:"date_created"`
+	DateUpdated     time.Time      `db:"date_updated"`
+}
+
+func toDBOrder(ord orderbus.Order) dbOrder {
+	return dbOrder{
+		ID:              ord.ID,
+		UserID:          ord.UserID,
+		CustomerID:      uuid.NullUUID{UUID: ord.CustomerID, Valid: ord.CustomerID != uuid.Nil},
+		Status:          ord.Status.String(),
+		Currency:        ord.Currency.String(),
+		Subtotal:        ord.Subtotal.Minor(),
+		Discount:        ord.Discount.Minor(),
+		Tax:             ord.Tax.Minor(),
+		Total:           ord.Total.Minor(),
+		TaxJurisdiction: ord.TaxJurisdiction,
+		IdempotencyKey:  sql.NullString{String: ord.IdempotencyKey, Valid: ord.IdempotencyKey != ""},
+		DateCreated:     ord.DateCreated.UTC(),
+		DateUpdated:     ord.DateUpdated.UTC(),
+	}
+}
+
+func toBusOrder(db dbOrder, items []item) (orderbus.Order, error) {
+	status, err := orderbus.ParseStatus(db.Status)
+	if err != nil {
+		return orderbus.Order{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return orderbus.Order{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	ord := orderbus.Order{
+		ID:              db.ID,
+		UserID:          db.UserID,
+		CustomerID:      db.CustomerID.UUID,
+		Status:          status,
+		Currency:        currency,
+		Subtotal:        money.New(db.Subtotal, currency),
+		Discount:        money.New(db.Discount, currency),
+		Tax:             money.New(db.Tax, currency),
+		Total:           money.New(db.Total, currency),
+		TaxJurisdiction: db.TaxJurisdiction,
+		IdempotencyKey:  db.IdempotencyKey.String,
+		Items:           toBusItems(items, currency),
+		DateCreated:     db.DateCreated.In(time.Local),
+		DateUpdated:     db.DateUpdated.In(time.Local),
+	}
+
+	return ord, nil
+}
+
+// =============================================================================
+
+type item struct {
+	ID        uuid.UUID `db:"order_item_id"`
+	OrderID   uuid.UUID `db:"order_id"`
+	SKU       string    `db:"sku"`
+	Name      string    `db:"name"`
+	Quantity  int       `db:"quantity"`
+	UnitPrice int64     `db:"unit_price"`
+}
+
+func toDBItems(items []orderbus.Item) []item {
+	dbItems := make([]item, len(items))
+	for i, it := range items {
+		dbItems[i] = item{
+			ID:        it.ID,
+			OrderID:   it.OrderID,
+			SKU:       it.SKU,
+			Name:      it.Name,
+			Quantity:  it.Quantity,
+			UnitPrice: it.UnitPrice.Minor(),
+		}
+	}
+	return dbItems
+}
+
+func toBusItems(dbItems []item, currency money.Currency) []orderbus.Item {
+	items := make([]orderbus.Item, len(dbItems))
+	for i, it := range dbItems {
+		items[i] = orderbus.Item{
+			ID:        it.ID,
+			OrderID:   it.OrderID,
+			SKU:       it.SKU,
+			Name:      it.Name,
+			Quantity:  it.Quantity,
+			UnitPrice: money.New(it.UnitPrice, currency),
+		}
+	}
+	return items
+}
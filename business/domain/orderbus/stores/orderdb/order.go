@@ -0,0 +1,18 @@
+package orderdb
+
+import (
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	orderbus.OrderByID:          "order_id",
+	orderbus.OrderByUserID:      "user_id",
+	orderbus.OrderByStatus:      "status",
+	orderbus.OrderByTotal:       "total",
+	orderbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return order.Clause(orderBy, orderByFields, "order_id")
+}
@@ -0,0 +1,270 @@
+// Package orderdb contains order related CRUD functionality.
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for order database access.
+type Store struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new order and its line items into the database.
+func (s *Store) Create(ctx context.Context, ord orderbus.Order) error {
+	tx, err := sqldb.BeginTx(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const q = `
+	INSERT INTO orders
+		(order_id, user_id, customer_id, status, currency, subtotal, discount, tax, total, tax_jurisdiction, idempotency_key, date_created, date_updated)
+	VALUES
+		(:order_id, :user_id, :customer_id, :status, :currency, :subtotal, :discount, :tax, :total, :tax_jurisdiction, :idempotency_key, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, tx, q, toDBOrder(ord)); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "orders_idempotency_key" {
+			return fmt.Errorf("insert order: %w", orderbus.ErrIdempotencyKey)
+		}
+		return fmt.Errorf("insert order: %w", sqldb.Translate(err))
+	}
+
+	const qi = `
+	INSERT INTO order_items
+		(order_item_id, order_id, sku, name, quantity, unit_price)
+	VALUES
+		(:order_item_id, :order_id, :sku, :name, :quantity, :unit_price)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, tx, qi, toDBItems(ord.Items)); err != nil {
+		return fmt.Errorf("insert items: %w", sqldb.Translate(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus replaces the status of an order in the database.
+func (s *Store) UpdateStatus(ctx context.Context, ord orderbus.Order) error {
+	const q = `
+	UPDATE
+		orders
+	SET
+		"status" = :status,
+		"date_updated" = :date_updated
+	WHERE
+		order_id = :order_id`
+
+	res, err := sqlx.NamedExecContext(ctx, sqldb.Executor(ctx, s.db), q, toDBOrder(ord))
+	if err != nil {
+		return fmt.Errorf("namedexeccontext: %w", sqldb.Translate(err))
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rowsaffected: %w", err)
+	}
+
+	if n == 0 {
+		return orderbus.ErrNotFound
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing orders from the database.
+func (s *Store) Query(ctx context.Context, filter orderbus.QueryFilter, orderBy order.By, page page.Page) ([]orderbus.Order, error) {
+	data := map[string]any{
+		"offset":        page.Offset(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		order_id, user_id, customer_id, status, currency, subtotal, discount, tax, total, tax_jurisdiction, idempotency_key, date_created, date_updated
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []dbOrder
+	if err := sqldb.NamedQuerySlice(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(dbOrds))
+	for i, o := range dbOrds {
+		ids[i] = o.ID
+	}
+
+	itemsByOrder, err := s.queryItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ords := make([]orderbus.Order, len(dbOrds))
+	for i, o := range dbOrds {
+		ord, err := toBusOrder(o, itemsByOrder[o.ID])
+		if err != nil {
+			return nil, err
+		}
+		ords[i] = ord
+	}
+
+	return ords, nil
+}
+
+// Count returns the total number of orders in the DB.
+func (s *Store) Count(ctx context.Context, filter orderbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified order from the database.
+func (s *Store) QueryByID(ctx context.Context, orderID uuid.UUID) (orderbus.Order, error) {
+	const q = `
+	SELECT
+		order_id, user_id, customer_id, status, currency, subtotal, discount, tax, total, tax_jurisdiction, idempotency_key, date_created, date_updated
+	FROM
+		orders
+	WHERE
+		order_id = :order_id`
+
+	data := struct {
+		ID string `db:"order_id"`
+	}{
+		ID: orderID.String(),
+	}
+
+	var dbOrd dbOrder
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbOrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orderbus.Order{}, fmt.Errorf("namedquerystruct: %w", orderbus.ErrNotFound)
+		}
+		return orderbus.Order{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	items, err := s.queryItems(ctx, []uuid.UUID{orderID})
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return toBusOrder(dbOrd, items[orderID])
+}
+
+// QueryByIdempotencyKey gets the order the user created with the idempotency
+// key from the database.
+func (s *Store) QueryByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (orderbus.Order, error) {
+	const q = `
+	SELECT
+		order_id, user_id, customer_id, status, currency, subtotal, discount, tax, total, tax_jurisdiction, idempotency_key, date_created, date_updated
+	FROM
+		orders
+	WHERE
+		user_id = :user_id AND
+		idempotency_key = :idempotency_key`
+
+	data := struct {
+		UserID string `db:"user_id"`
+		Key    string `db:"idempotency_key"`
+	}{
+		UserID: userID.String(),
+		Key:    key,
+	}
+
+	var dbOrd dbOrder
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbOrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orderbus.Order{}, fmt.Errorf("namedquerystruct: %w", orderbus.ErrNotFound)
+		}
+		return orderbus.Order{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	items, err := s.queryItems(ctx, []uuid.UUID{dbOrd.ID})
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return toBusOrder(dbOrd, items[dbOrd.ID])
+}
+
+// queryItems returns the line items of the given orders grouped by order.
+func (s *Store) queryItems(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]item, error) {
+	if len(orderIDs) == 0 {
+		return map[uuid.UUID][]item{}, nil
+	}
+
+	const q = `
+	SELECT
+		order_item_id, order_id, sku, name, quantity, unit_price
+	FROM
+		order_items
+	WHERE
+		order_id = ANY($1)
+	ORDER BY
+		sku, order_item_id`
+
+	var dbItems []item
+	if err := sqlx.SelectContext(ctx, sqldb.Executor(ctx, s.db), &dbItems, q, orderIDs); err != nil {
+		return nil, fmt.Errorf("select items: %w", err)
+	}
+
+	itemsByOrder := make(map[uuid.UUID][]item, len(orderIDs))
+	for _, it := range dbItems {
+		itemsByOrder[it.OrderID] = append(itemsByOrder[it.OrderID], it)
+	}
+
+	return itemsByOrder, nil
+}
@@ -0,0 +1,28 @@
+package userbus
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User represents information about an individual user.
+type User struct {
+	ID           uuid.UUID
+	Name         string
+	Email        mail.Address
+	Roles        []Role
+	PasswordHash []byte
+	Enabled      bool
+	DateCreated  time.Time
+	DateUpdated  time.Time
+}
+
+// NewUser contains information needed to create a new user.
+type NewUser struct {
+	Name     string
+	Email    mail.Address
+	Roles    []Role
+	Password string
+}
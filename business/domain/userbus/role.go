@@ -0,0 +1,88 @@
+package userbus
+
+import "fmt"
+
+// The set of roles that can be used.
+var (
+	RoleAdmin = newRole("ADMIN")
+	RoleUser  = newRole("USER")
+)
+
+// =============================================================================
+
+// Set of known roles.
+var roles = make(map[string]Role)
+
+// Role represents a role in the system.
+type Role struct {
+	value string
+}
+
+func newRole(role string) Role {
+	r := Role{role}
+	roles[role] = r
+	return r
+}
+
+// String returns the name of the role.
+func (r Role) String() string {
+	return r.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Role) Equal(r2 Role) bool {
+	return r.value == r2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.value), nil
+}
+
+// UnmarshalText provides support for decoding a role that was marshaled.
+func (r *Role) UnmarshalText(data []byte) error {
+	role, err := ParseRole(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = role
+
+	return nil
+}
+
+// =============================================================================
+
+// ParseRole parses the string value and returns a role if one exists.
+func ParseRole(value string) (Role, error) {
+	role, exists := roles[value]
+	if !exists {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	return role, nil
+}
+
+// ParseRoles parses the string values and returns the roles.
+func ParseRoles(values []string) ([]Role, error) {
+	usrRoles := make([]Role, len(values))
+	for i, value := range values {
+		role, err := ParseRole(value)
+		if err != nil {
+			return nil, err
+		}
+		usrRoles[i] = role
+	}
+
+	return usrRoles, nil
+}
+
+// RolesToStrings returns the names of the roles.
+func RolesToStrings(usrRoles []Role) []string {
+	names := make([]string, len(usrRoles))
+	for i, role := range usrRoles {
+		names[i] = role.String()
+	}
+
+	return names
+}
@@ -0,0 +1,66 @@
+package userdb
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/userbus"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type user struct {
+	ID           uuid.UUID `db:"user_id"`
+	Name         string    `db:"name"`
+	Email        string    `db:"email"`
+	Roles        roles     `db:"roles"`
+	PasswordHash []byte    `db:"password_hash"`
+	Enabled      bool      `db:"enabled"`
+	DateCreated  time.Time `db:"date_created"`
+	DateUpdated  time.Time `db:"date_updated"`
+}
+
+func toDBUser(usr userbus.User) user {
+	return user{
+		ID:           usr.ID,
+		Name:         usr.Name,
+		Email:        usr.Email.Address,
+		Roles:        userbus.RolesToStrings(usr.Roles),
+		PasswordHash: usr.PasswordHash,
+		Enabled:      usr.Enabled,
+		DateCreated:  usr.DateCreated.UTC(),
+		DateUpdated:  usr.DateUpdated.UTC(),
+	}
+}
+
+func toBusUser(db user) (userbus.User, error) {
+	usrRoles, err := userbus.ParseRoles(db.Roles)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("parse roles: %w", err)
+	}
+
+	usr := userbus.User{
+		ID:           db.ID,
+		Name:         db.Name,
+		Email:        mail.Address{Name: db.Name, Address: db.Email},
+		Roles:        usrRoles,
+		PasswordHash: db.PasswordHash,
+		Enabled:      db.Enabled,
+		DateCreated:  db.DateCreated.In(time.Local),
+		DateUpdated:  db.DateUpdated.In(time.Local),
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+// roles scans the TEXT[] roles column. The driver encodes a []string as an
+// array on its own, but database/sql can't scan an array back without help.
+type roles []string
+
+// Scan implements the sql.Scanner interface.
+func (r *roles) Scan(src any) error {
+	return pgtype.NewMap().SQLScanner((*[]string)(r)).Scan(src)
+}
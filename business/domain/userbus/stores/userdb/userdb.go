@@ -0,0 +1,101 @@
+// Package userdb contains user related CRUD functionality.
+package userdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+
+	"github.com/AlmirSai/service/business/domain/userbus"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for user database access.
+type Store struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new user into the database.
+func (s *Store) Create(ctx context.Context, usr userbus.User) error {
+	const q = `
+	INSERT INTO users
+		(user_id, name, email, roles, password_hash, enabled, date_created, date_updated)
+	VALUES
+		(:user_id, :name, :email, :roles, :password_hash, :enabled, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, sqldb.Executor(ctx, s.db), q, toDBUser(usr)); err != nil {
+		if sqldb.IsUniqueViolation(err) {
+			return fmt.Errorf("namedexeccontext: %w", userbus.ErrUniqueEmail)
+		}
+		return fmt.Errorf("namedexeccontext: %w", sqldb.Translate(err))
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified user from the database.
+func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	const q = `
+	SELECT
+		user_id, name, email, roles, password_hash, enabled, date_created, date_updated
+	FROM
+		users
+	WHERE
+		user_id = :user_id`
+
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	var dbUsr user
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbUsr); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.User{}, fmt.Errorf("namedquerystruct: %w", userbus.ErrNotFound)
+		}
+		return userbus.User{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusUser(dbUsr)
+}
+
+// QueryByEmail gets the specified user from the database by email.
+func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
+	const q = `
+	SELECT
+		user_id, name, email, roles, password_hash, enabled, date_created, date_updated
+	FROM
+		users
+	WHERE
+		email = :email`
+
+	data := struct {
+		Email string `db:"email"`
+	}{
+		Email: email.Address,
+	}
+
+	var dbUsr user
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), q, data, &dbUsr); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.User{}, fmt.Errorf("namedquerystruct: %w", userbus.ErrNotFound)
+		}
+		return userbus.User{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusUser(dbUsr)
+}
@@ -0,0 +1,123 @@
+// Package userbus provides business access to user domain.
+package userbus
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound    = errs.Newf(errs.NotFound, "user not found")
+	ErrUniqueEmail = errs.Newf(errs.AlreadyExists, "email is not unique")
+	ErrNoRoles     = errs.Newf(errs.InvalidArgument, "user must have at least one role")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	Create(ctx context.Context, usr User) error
+	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
+	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
+}
+
+// Business manages the set of APIs for user access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a user business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create adds a new user to the system. The password is stored as a bcrypt
+// hash, never in plaintext.
+func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.create")
+	defer span.End()
+
+	if len(nu.Roles) == 0 {
+		return User{}, fmt.Errorf("create: %w", ErrNoRoles)
+	}
+
+	hash, err := HashPassword(nu.Password)
+	if err != nil {
+		return User{}, fmt.Errorf("create: %w", err)
+	}
+
+	now := time.Now()
+
+	usr := User{
+		ID:           id.New(),
+		Name:         nu.Name,
+		Email:        nu.Email,
+		Roles:        nu.Roles,
+		PasswordHash: hash,
+		Enabled:      true,
+		DateCreated:  now,
+		DateUpdated:  now,
+	}
+
+	if err := b.storer.Create(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("create: %w", err)
+	}
+
+	return usr, nil
+}
+
+// QueryByID finds the user by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, userID uuid.UUID) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.querybyid")
+	defer span.End()
+
+	usr, err := b.storer.QueryByID(ctx, userID)
+	if err != nil {
+		return User{}, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return usr, nil
+}
+
+// QueryByEmail finds the user by a specified user email.
+func (b *Business) QueryByEmail(ctx context.Context, email mail.Address) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.querybyemail")
+	defer span.End()
+
+	usr, err := b.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		return User{}, fmt.Errorf("query: email[%s]: %w", email.Address, err)
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+// HashPassword returns the bcrypt hash of the password, the form in which
+// passwords are stored.
+func HashPassword(password string) ([]byte, error) {
+	if password == "" {
+		return nil, errs.Newf(errs.InvalidArgument, "password is required")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("generatefrompassword: %w", err)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,20 @@
+package vorderbus
+
+import (
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// A nil field means the query isn't filtered on it.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	UserID           *uuid.UUID
+	CustomerID       *uuid.UUID
+	CustomerName     *string
+	Status           *orderbus.Status
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
@@ -0,0 +1,26 @@
+package vorderbus
+
+import (
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/google/uuid"
+)
+
+// Order represents a row of the order view. The customer fields are empty for
+// orders placed before customer records existed.
+type Order struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	CustomerID    uuid.UUID
+	CustomerName  string
+	CustomerEmail string
+	Status        orderbus.Status
+	Currency      money.Currency
+	Total         money.Money
+	ItemCount     int
+	Units         int
+	DateCreated   time.Time
+	DateUpdated   time.Time
+}
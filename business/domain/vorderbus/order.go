@@ -0,0 +1,16 @@
+package vorderbus
+
+import "github.com/AlmirSai/service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID           = "order_id"
+	OrderByCustomerName = "customer_name"
+	OrderByStatus       = "status"
+	OrderByTotal        = "total"
+	OrderByItemCount    = "item_count"
+	OrderByDateCreated  = "date_created"
+)
@@ -0,0 +1,53 @@
+package vorderdb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+)
+
+func applyFilter(filter vorderbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ID != nil {
+		data["order_id"] = *filter.ID
+		wc = append(wc, "order_id = :order_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.CustomerID != nil {
+		data["customer_id"] = *filter.CustomerID
+		wc = append(wc, "customer_id = :customer_id")
+	}
+
+	if filter.CustomerName != nil {
+		data["customer_name"] = fmt.Sprintf("%%%s%%", *filter.CustomerName)
+		wc = append(wc, "customer_name ILIKE :customer_name")
+	}
+
+	if filter.Status != nil {
+		data["status"] = filter.Status.String()
+		wc = append(wc, "status = :status")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
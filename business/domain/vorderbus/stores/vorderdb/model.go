@@ -0,0 +1,78 @@
+package vorderdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/sdk/money"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/google/uuid"
+)
+
+type dbOrder struct {
+	ID            uuid.UUID      `db:"order_id"`
+	UserID        uuid.UUID      `db:"user_id"`
+	CustomerID    uuid.NullUUID  `db:"customer_id"`
+	CustomerName  sql.NullString `db:"customer_name"`
+	CustomerEmail sql.NullString `db:"customer_email"`
+	Status        string         `db:"status"`
+	Currency      string         `db:"currency"`
+	Total         int64          `db:"total"`
+	ItemCount     int            `db:"item_count"`
+	Units         int            `db:"units"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+}
+
+// emailColumn is the associated data the customer store encrypts the email
+// with.
+const emailColumn = "customers.email"
+
+func toBusOrder(crypt *encrypt.Keyring, db dbOrder) (vorderbus.Order, error) {
+	status, err := orderbus.ParseStatus(db.Status)
+	if err != nil {
+		return vorderbus.Order{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return vorderbus.Order{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	email, err := crypt.Decrypt(db.CustomerEmail.String, emailColumn)
+	if err != nil {
+		return vorderbus.Order{}, fmt.Errorf("decrypt email: %w", err)
+	}
+
+	ord := vorderbus.Order{
+		ID:            db.ID,
+		UserID:        db.UserID,
+		CustomerID:    db.CustomerID.UUID,
+		CustomerName:  db.CustomerName.String,
+		CustomerEmail: email,
+		Status:        status,
+		Currency:      currency,
+		Total:         money.New(db.Total, currency),
+		ItemCount:     db.ItemCount,
+		Units:         db.Units,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	return ord, nil
+}
+
+func toBusOrders(crypt *encrypt.Keyring, dbOrds []dbOrder) ([]vorderbus.Order, error) {
+	ords := make([]vorderbus.Order, len(dbOrds))
+	for i, o := range dbOrds {
+		ord, err := toBusOrder(crypt, o)
+		if err != nil {
+			return nil, err
+		}
+		ords[i] = ord
+	}
+	return ords, nil
+}
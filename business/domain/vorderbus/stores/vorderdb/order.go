@@ -0,0 +1,19 @@
+package vorderdb
+
+import (
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	vorderbus.OrderByID:           "order_id",
+	vorderbus.OrderByCustomerName: "customer_name",
+	vorderbus.OrderByStatus:       "status",
+	vorderbus.OrderByTotal:        "total",
+	vorderbus.OrderByItemCount:    "item_count",
+	vorderbus.OrderByDateCreated:  "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return order.Clause(orderBy, orderByFields, "order_id")
+}
@@ -0,0 +1,88 @@
+// Package vorderdb provides access to the order view.
+package vorderdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for order view database access.
+type Store struct {
+	log   *logger.Logger
+	db    *sqlx.DB
+	crypt *encrypt.Keyring
+}
+
+// NewStore constructs the api for data access. The keyring decrypts the
+// customer email, it has to be the one the customer store encrypts with.
+func NewStore(log *logger.Logger, db *sqlx.DB, crypt *encrypt.Keyring) *Store {
+	return &Store{
+		log:   log,
+		db:    db,
+		crypt: crypt,
+	}
+}
+
+// Query retrieves a list of existing orders from the view.
+func (s *Store) Query(ctx context.Context, filter vorderbus.QueryFilter, orderBy order.By, page page.Page) ([]vorderbus.Order, error) {
+	data := map[string]any{
+		"offset":        page.Offset(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		order_id, user_id, customer_id, customer_name, customer_email, status, currency, total, item_count, units, date_created, date_updated
+	FROM
+		view_orders`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []dbOrder
+	if err := sqldb.NamedQuerySlice(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusOrders(s.crypt, dbOrds)
+}
+
+// Count returns the total number of orders in the view.
+func (s *Store) Count(ctx context.Context, filter vorderbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		view_orders`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, sqldb.Executor(ctx, s.db), buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,57 @@
+// Package vorderbus provides business access to the order view. The view
+// joins orders with their customer and summarizes the line items so list
+// screens get everything they show in a single query.
+package vorderbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlmirSai/service/business/sdk/order"
+	"github.com/AlmirSai/service/business/sdk/page"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+)
+
+//go:generate moq -pkg vordermock -out vordermock/vordermock.go . Storer
+
+// Storer interface declares the behavior this package needs to retrieve data.
+type Storer interface {
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
+
+// Business manages the set of APIs for order view access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an order view business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Query retrieves a list of existing orders with their customer summary.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.vorderbus.query")
+	defer span.End()
+
+	orders, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return orders, nil
+}
+
+// Count returns the total number of orders in the view.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.vorderbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
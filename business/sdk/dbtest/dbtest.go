@@ -0,0 +1,232 @@
+// Package dbtest contains supporting code for running tests that hit the DB.
+package dbtest
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/business/domain/auditbus"
+	"github.com/AlmirSai/service/business/domain/auditbus/stores/auditdb"
+	"github.com/AlmirSai/service/business/domain/customerbus"
+	"github.com/AlmirSai/service/business/domain/customerbus/stores/customerdb"
+	"github.com/AlmirSai/service/business/domain/inventorybus"
+	"github.com/AlmirSai/service/business/domain/inventorybus/stores/inventorydb"
+	"github.com/AlmirSai/service/business/domain/orderbus"
+	"github.com/AlmirSai/service/business/domain/orderbus/stores/orderdb"
+	"github.com/AlmirSai/service/business/domain/vorderbus"
+	"github.com/AlmirSai/service/business/domain/vorderbus/stores/vorderdb"
+	"github.com/AlmirSai/service/business/sdk/delegate"
+	"github.com/AlmirSai/service/business/sdk/migrate"
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/docker"
+	"github.com/AlmirSai/service/foundation/encrypt"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// containerName is shared by every test binary so the Postgres container is
+// started once and reused. It's left running between runs on purpose.
+const containerName = "servicetest"
+
+// BusDomain represents all the business domain apis needed for testing.
+type BusDomain struct {
+	Audit     *auditbus.Business
+	Customer  *customerbus.Business
+	Inventory *inventorybus.Business
+	Order     *orderbus.Business
+	VOrder    *vorderbus.Business
+}
+
+func newBusDomains(log *logger.Logger, db *sqlx.DB, crypt *encrypt.Keyring) BusDomain {
+	delegate := delegate.New(log)
+	auditBus := auditbus.NewBusiness(log, delegate, auditdb.NewStore(log, db))
+	customerBus := customerbus.NewBusiness(log, delegate, customerdb.NewStore(log, db, crypt))
+	inventoryBus := inventorybus.NewBusiness(log, inventorydb.NewStore(log, db), time.Hour)
+	orderBus := orderbus.NewBusiness(log, delegate, sqldb.NewTran(db), customerBus, inventoryBus, pricing.New(nil), orderdb.NewStore(log, db))
+	vorderBus := vorderbus.NewBusiness(log, vorderdb.NewStore(log, db, crypt))
+
+	return BusDomain{
+		Audit:     auditBus,
+		Customer:  customerBus,
+		Inventory: inventoryBus,
+		Order:     orderBus,
+		VOrder:    vorderBus,
+	}
+}
+
+// =============================================================================
+
+// Database owns state for running and shutting down tests.
+type Database struct {
+	DB         *sqlx.DB
+	Log        *logger.Logger
+	Encryption *encrypt.Keyring // Keys of the encrypted columns
+	BusDomain  BusDomain
+	logs       *syncBuffer
+}
+
+// New creates a new database with a unique name for the test, runs the
+// migrations and seed data against it, and drops it when the test is done,
+// so store tests can run in parallel without seeing each other's rows. The
+// test is skipped when docker isn't installed.
+func New(t *testing.T, testName string) *Database {
+	t.Helper()
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skipf("docker is required to run database tests: %s", err)
+	}
+
+	c, err := docker.StartContainer(docker.Postgres(containerName))
+	if err != nil {
+		t.Fatalf("starting database: %s", err)
+	}
+
+	t.Logf("Name    : %s\n", c.Name)
+	t.Logf("HostPort: %s\n", c.HostPort)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := docker.WaitForPort(ctx, c); err != nil {
+		t.Fatalf("waiting for database: %s", err)
+	}
+
+	dbM, err := sqldb.Open(sqldb.Config{
+		User:       "postgres",
+		Password:   "postgres",
+		HostPort:   c.HostPort,
+		Name:       "postgres",
+		DisableTLS: true,
+	})
+	if err != nil {
+		t.Fatalf("opening database connection: %v", err)
+	}
+
+	if err := sqldb.StatusCheck(ctx, dbM); err != nil {
+		t.Fatalf("status check database: %v\n%s", err, docker.DumpContainerLogs(c.Name))
+	}
+
+	dbName := fmt.Sprintf("test_%s", randomSuffix())
+
+	t.Logf("Create Database: %s\n", dbName)
+	if _, err := dbM.ExecContext(ctx, "CREATE DATABASE "+dbName); err != nil {
+		t.Fatalf("creating database %s: %v", dbName, err)
+	}
+
+	db, err := sqldb.Open(sqldb.Config{
+		User:       "postgres",
+		Password:   "postgres",
+		HostPort:   c.HostPort,
+		Name:       dbName,
+		DisableTLS: true,
+	})
+	if err != nil {
+		t.Fatalf("opening database connection: %v", err)
+	}
+
+	t.Logf("Migrate Database: %s\n", dbName)
+	if err := migrate.Migrate(ctx, db); err != nil {
+		t.Fatalf("migrating error: %s\n%s", err, docker.DumpContainerLogs(c.Name))
+	}
+
+	t.Logf("Seed Database: %s\n", dbName)
+	if err := migrate.Seed(ctx, db); err != nil {
+		t.Fatalf("seeding error: %s\n%s", err, docker.DumpContainerLogs(c.Name))
+	}
+
+	crypt, err := newKeyring()
+	if err != nil {
+		t.Fatalf("constructing keyring: %v", err)
+	}
+
+	logs := syncBuffer{}
+	log := logger.New(&logs, logger.LevelDebug, "TEST", web.GetTraceID)
+
+	t.Cleanup(func() {
+		t.Helper()
+
+		db.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		t.Logf("Drop Database: %s\n", dbName)
+		if _, err := dbM.ExecContext(ctx, "DROP DATABASE "+dbName+" WITH (FORCE)"); err != nil {
+			t.Logf("dropping database %s: %v", dbName, err)
+		}
+
+		dbM.Close()
+
+		if t.Failed() {
+			t.Logf("******************** LOGS (%s) ********************\n%s", testName, logs.String())
+		}
+	})
+
+	return &Database{
+		DB:         db,
+		Log:        log,
+		Encryption: crypt,
+		BusDomain:  newBusDomains(log, db, crypt),
+		logs:       &logs,
+	}
+}
+
+// Logs returns everything logged through the test logger so far.
+func (db *Database) Logs() string {
+	return db.logs.String()
+}
+
+// =============================================================================
+
+// newKeyring constructs a keyring with random keys so tests exercise the
+// encrypted columns.
+func newKeyring() (*encrypt.Keyring, error) {
+	key := make([]byte, 32)
+	indexKey := make([]byte, 32)
+	for _, b := range [][]byte{key, indexKey} {
+		if _, err := crand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return encrypt.New("test", map[string][]byte{"test": key}, indexKey)
+}
+
+// randomSuffix returns a short lowercase name that is safe to use in an
+// unquoted identifier.
+func randomSuffix() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = letters[rand.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes from handlers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,115 @@
+// Package delegate lets a domain react to the events of another domain
+// without importing it. Handlers run in-process and synchronously on the
+// caller's context, so they take part in the caller's transaction and a
+// failing handler fails the operation that raised the event.
+package delegate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+)
+
+// Data is an event raised by a domain. RawParams carries the JSON encoded
+// parameters the domain documents for the action.
+type Data struct {
+	Domain    string
+	Action    string
+	RawParams []byte
+}
+
+// String implements the fmt.Stringer interface.
+func (d Data) String() string {
+	return fmt.Sprintf("%s.%s", d.Domain, d.Action)
+}
+
+// NewData constructs the event, encoding the params.
+func NewData(domain string, action string, params any) (Data, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return Data{}, fmt.Errorf("marshal %s.%s params: %w", domain, action, err)
+	}
+
+	d := Data{
+		Domain:    domain,
+		Action:    action,
+		RawParams: raw,
+	}
+
+	return d, nil
+}
+
+// Decode unmarshals the params of the event into v.
+func (d Data) Decode(v any) error {
+	if err := json.Unmarshal(d.RawParams, v); err != nil {
+		return fmt.Errorf("unmarshal %s params: %w", d, err)
+	}
+	return nil
+}
+
+// Func is a handler for an event.
+type Func func(ctx context.Context, data Data) error
+
+// Delegate holds the handlers registered for every domain and action.
+type Delegate struct {
+	log   *logger.Logger
+	mu    sync.RWMutex
+	funcs map[string]map[string][]Func
+}
+
+// New constructs a Delegate for use.
+func New(log *logger.Logger) *Delegate {
+	return &Delegate{
+		log:   log,
+		funcs: make(map[string]map[string][]Func),
+	}
+}
+
+// Register adds a handler for the action of the domain. Handlers run in the
+// order they were registered.
+func (d *Delegate) Register(domain string, action string, fn Func) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	actions, exists := d.funcs[domain]
+	if !exists {
+		actions = make(map[string][]Func)
+		d.funcs[domain] = actions
+	}
+
+	actions[action] = append(actions[action], fn)
+}
+
+// Call runs the handlers registered for the event and stops at the first
+// one that fails. A nil Delegate or an event nobody listens to is a no-op.
+func (d *Delegate) Call(ctx context.Context, data Data) error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.RLock()
+	funcs := d.funcs[data.Domain][data.Action]
+	d.mu.RUnlock()
+
+	if len(funcs) == 0 {
+		return nil
+	}
+
+	ctx, span := otel.AddSpan(ctx, "business.sdk.delegate.call")
+	defer span.End()
+
+	d.log.Info(ctx, "delegate call", "status", "started", "event", data.String(), "handlers", len(funcs))
+	defer d.log.Info(ctx, "delegate call", "status", "completed", "event", data.String())
+
+	for _, fn := range funcs {
+		if err := fn(ctx, data); err != nil {
+			return fmt.Errorf("delegate %s: %w", data, err)
+		}
+	}
+
+	return nil
+}
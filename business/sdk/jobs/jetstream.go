@@ -0,0 +1,187 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// JetStreamConfig contains the settings for a JetStream backend.
+type JetStreamConfig struct {
+	Stream  string // Name of the stream holding the jobs, defaults to JOBS
+	Subject string // Subject prefix of the queues, defaults to jobs
+}
+
+// JetStream is a Backend on a NATS JetStream work queue stream. Every queue
+// is a subject with a durable pull consumer whose ack wait is the visibility
+// timeout. Dead-lettered jobs are published to the queue's dlq subject.
+type JetStream struct {
+	js  jetstream.JetStream
+	cfg JetStreamConfig
+
+	mu        sync.Mutex
+	consumers map[string]jetstream.Consumer
+}
+
+// NewJetStream constructs a JetStream backend and creates or updates the
+// stream. Queue names must not contain dots or wildcards.
+func NewJetStream(ctx context.Context, js jetstream.JetStream, cfg JetStreamConfig) (*JetStream, error) {
+	if cfg.Stream == "" {
+		cfg.Stream = "JOBS"
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = "jobs"
+	}
+
+	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      cfg.Stream,
+		Subjects:  []string{cfg.Subject + ".>"},
+		Retention: jetstream.WorkQueuePolicy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create stream %s: %w", cfg.Stream, err)
+	}
+
+	b := JetStream{
+		js:        js,
+		cfg:       cfg,
+		consumers: make(map[string]jetstream.Consumer),
+	}
+
+	return &b, nil
+}
+
+// Enqueue implements the Backend interface.
+func (b *JetStream) Enqueue(ctx context.Context, queue string, payload []byte) error {
+	if _, err := b.js.Publish(ctx, b.subject(queue), payload); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	return nil
+}
+
+// Receive implements the Backend interface.
+func (b *JetStream) Receive(ctx context.Context, queue string, max int, visibility time.Duration) ([]Job, error) {
+	cons, err := b.consumer(ctx, queue, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := cons.Fetch(max, jetstream.FetchMaxWait(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+
+	var jobs []Job
+	for msg := range batch.Messages() {
+		md, err := msg.Metadata()
+		if err != nil {
+			return jobs, fmt.Errorf("metadata: %w", err)
+		}
+
+		jobs = append(jobs, Job{
+			ID:          fmt.Sprintf("%s-%d", md.Stream, md.Sequence.Stream),
+			Queue:       queue,
+			Payload:     msg.Data(),
+			Attempt:     int(md.NumDelivered),
+			DateCreated: md.Timestamp,
+			handle:      msg,
+		})
+	}
+
+	if err := batch.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+		return jobs, fmt.Errorf("fetch: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Ack implements the Backend interface.
+func (b *JetStream) Ack(ctx context.Context, job Job) error {
+	msg, err := message(job)
+	if err != nil {
+		return err
+	}
+
+	return msg.DoubleAck(ctx)
+}
+
+// Retry implements the Backend interface.
+func (b *JetStream) Retry(ctx context.Context, job Job, delay time.Duration, cause error) error {
+	msg, err := message(job)
+	if err != nil {
+		return err
+	}
+
+	return msg.NakWithDelay(delay)
+}
+
+// DeadLetter implements the Backend interface. The job is published to the
+// dlq subject before it's terminated, so it's never lost in between.
+func (b *JetStream) DeadLetter(ctx context.Context, job Job, cause error) error {
+	msg, err := message(job)
+	if err != nil {
+		return err
+	}
+
+	dlq := nats.NewMsg(b.cfg.Subject + ".dlq." + job.Queue)
+	dlq.Data = job.Payload
+	dlq.Header.Set("Job-Id", job.ID)
+	dlq.Header.Set("Job-Error", cause.Error())
+
+	if _, err := b.js.PublishMsg(ctx, dlq); err != nil {
+		return fmt.Errorf("publish dlq: %w", err)
+	}
+
+	return msg.TermWithReason(cause.Error())
+}
+
+// =============================================================================
+
+func (b *JetStream) subject(queue string) string {
+	return b.cfg.Subject + "." + queue
+}
+
+// consumer returns the durable consumer of the queue, creating it on first
+// use with the visibility timeout as its ack wait.
+func (b *JetStream) consumer(ctx context.Context, queue string, visibility time.Duration) (jetstream.Consumer, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cons, exists := b.consumers[queue]; exists {
+		return cons, nil
+	}
+
+	if queue == "dlq" || strings.ContainsAny(queue, ".*> ") {
+		return nil, fmt.Errorf("invalid queue name %q", queue)
+	}
+
+	cons, err := b.js.CreateOrUpdateConsumer(ctx, b.cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       b.cfg.Stream + "-" + queue,
+		FilterSubject: b.subject(queue),
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       visibility,
+		MaxDeliver:    -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create consumer %s: %w", queue, err)
+	}
+
+	b.consumers[queue] = cons
+
+	return cons, nil
+}
+
+func message(job Job) (jetstream.Msg, error) {
+	msg, ok := job.handle.(jetstream.Msg)
+	if !ok {
+		return nil, fmt.Errorf("job[%s] wasn't received from jetstream", job.ID)
+	}
+	return msg, nil
+}
@@ -0,0 +1,273 @@
+// Package jobs provides durable background jobs with at-least-once delivery.
+// Jobs are enqueued on named queues and consumed by handlers; a job that
+// fails is retried with backoff once its attempt is over and moved to the
+// dead-letter queue when its attempts are exhausted. Handlers must be
+// idempotent since a job can be delivered more than once.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Job is a unit of work received from a queue.
+type Job struct {
+	ID          string
+	Queue       string
+	Payload     []byte
+	Attempt     int // Deliveries of the job including this one
+	DateCreated time.Time
+
+	handle any // Backend specific state needed to settle the job
+}
+
+// Decode unmarshals the JSON payload of the job into v.
+func (j Job) Decode(v any) error {
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return fmt.Errorf("decode job[%s]: %w", j.ID, err)
+	}
+	return nil
+}
+
+// Backend is the behavior a queue store provides. A received job stays
+// hidden from other consumers for the visibility timeout, after which it's
+// delivered again unless it was settled with Ack, Retry or DeadLetter.
+type Backend interface {
+	Enqueue(ctx context.Context, queue string, payload []byte) error
+	Receive(ctx context.Context, queue string, max int, visibility time.Duration) ([]Job, error)
+	Ack(ctx context.Context, job Job) error
+	Retry(ctx context.Context, job Job, delay time.Duration, cause error) error
+	DeadLetter(ctx context.Context, job Job, cause error) error
+}
+
+// HandlerFunc processes a single job. Returning an error retries the job,
+// unless the error is marked with Permanent.
+type HandlerFunc func(ctx context.Context, job Job) error
+
+// Config contains the settings for a queue consumer.
+type Config struct {
+	MaxAttempts  int           // Deliveries before the job is dead-lettered, defaults to 5
+	Visibility   time.Duration // Time a handler has to settle a job, defaults to 30s
+	Concurrency  int           // Jobs handled at once, defaults to 1
+	PollInterval time.Duration // Wait when the queue is empty, defaults to 1s
+	InitialDelay time.Duration // Delay before the first retry, defaults to 1s
+	MaxDelay     time.Duration // Upper bound of the retry delay, defaults to 5m
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Visibility <= 0 {
+		cfg.Visibility = 30 * time.Second
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.InitialDelay <= 0 {
+		cfg.InitialDelay = time.Second
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 5 * time.Minute
+	}
+	return cfg
+}
+
+// =============================================================================
+
+// permanentError marks a handler error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (pe *permanentError) Error() string { return pe.err.Error() }
+func (pe *permanentError) Unwrap() error { return pe.err }
+
+// Permanent wraps the error so the job is dead-lettered without retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// =============================================================================
+
+// Queue enqueues and consumes jobs on a backend.
+type Queue struct {
+	log     *logger.Logger
+	backend Backend
+}
+
+// New constructs a queue on the backend.
+func New(log *logger.Logger, backend Backend) *Queue {
+	return &Queue{
+		log:     log,
+		backend: backend,
+	}
+}
+
+// Enqueue adds a job with the JSON encoding of v as its payload.
+func (q *Queue) Enqueue(ctx context.Context, queue string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode job: %w", err)
+	}
+
+	if err := q.backend.Enqueue(ctx, queue, payload); err != nil {
+		return fmt.Errorf("enqueue[%s]: %w", queue, err)
+	}
+
+	return nil
+}
+
+// Consume receives jobs from the queue and runs the handler on them until
+// the context is cancelled. It blocks until in-flight jobs have returned.
+func (q *Queue) Consume(ctx context.Context, queue string, cfg Config, handler HandlerFunc) {
+	cfg = cfg.withDefaults()
+
+	q.log.Info(ctx, "jobs", "status", "consumer started", "queue", queue, "concurrency", cfg.Concurrency)
+	defer q.log.Info(ctx, "jobs", "status", "consumer stopped", "queue", queue)
+
+	sem := make(chan struct{}, cfg.Concurrency)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Only ask for as many jobs as there are free handlers, so received
+		// jobs don't wait out their visibility in this process.
+		sem <- struct{}{}
+		free := 1
+	fill:
+		for free < cfg.Concurrency {
+			select {
+			case sem <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		jobs, err := q.backend.Receive(ctx, queue, free, cfg.Visibility)
+		if err != nil && ctx.Err() == nil {
+			q.log.Error(ctx, "jobs", "status", "receive failed", "queue", queue, "error", err)
+		}
+
+		for range free - len(jobs) {
+			<-sem
+		}
+
+		for _, job := range jobs {
+			wg.Go(func() {
+				defer func() { <-sem }()
+				q.handle(ctx, cfg, job, handler)
+			})
+		}
+
+		if len(jobs) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cfg.PollInterval):
+			}
+		}
+	}
+}
+
+// handle runs the handler on a job and settles it with the backend. Settling
+// uses a context that outlives the consumer so a job finished during
+// shutdown isn't delivered again.
+func (q *Queue) handle(ctx context.Context, cfg Config, job Job, handler HandlerFunc) {
+	ctx, span := otel.AddSpan(ctx, "business.sdk.jobs.handle",
+		attribute.String("queue", job.Queue),
+		attribute.String("job_id", job.ID),
+		attribute.Int("attempt", job.Attempt),
+	)
+	defer span.End()
+
+	settleCtx := context.WithoutCancel(ctx)
+
+	if job.Attempt > cfg.MaxAttempts {
+		q.deadLetter(settleCtx, job, errors.New("attempts exhausted"))
+		return
+	}
+
+	err := q.run(ctx, cfg, job, handler)
+	if err == nil {
+		if err := q.backend.Ack(settleCtx, job); err != nil {
+			q.log.Error(ctx, "jobs", "status", "ack failed", "queue", job.Queue, "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	span.RecordError(err)
+
+	var pe *permanentError
+	if errors.As(err, &pe) || job.Attempt >= cfg.MaxAttempts {
+		q.deadLetter(settleCtx, job, err)
+		return
+	}
+
+	delay := backoff(cfg, job.Attempt)
+
+	q.log.Warn(ctx, "jobs", "status", "job failed", "queue", job.Queue, "job_id", job.ID,
+		"attempt", job.Attempt, "retry_in", delay.String(), "error", err)
+
+	if err := q.backend.Retry(settleCtx, job, delay, err); err != nil {
+		q.log.Error(ctx, "jobs", "status", "retry failed", "queue", job.Queue, "job_id", job.ID, "error", err)
+	}
+}
+
+// run calls the handler within the visibility timeout and turns a panic
+// into an error so the job is retried.
+func (q *Queue) run(ctx context.Context, cfg Config, job Job, handler HandlerFunc) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Visibility)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+func (q *Queue) deadLetter(ctx context.Context, job Job, cause error) {
+	q.log.Error(ctx, "jobs", "status", "job dead-lettered", "queue", job.Queue, "job_id", job.ID,
+		"attempt", job.Attempt, "error", cause)
+
+	if err := q.backend.DeadLetter(ctx, job, cause); err != nil {
+		q.log.Error(ctx, "jobs", "status", "dead-letter failed", "queue", job.Queue, "job_id", job.ID, "error", err)
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling per attempt
+// with 20% jitter so jobs that failed together don't retry together.
+func backoff(cfg Config, attempt int) time.Duration {
+	d := cfg.InitialDelay
+	for i := 1; i < attempt && d < cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, cfg.MaxDelay)
+
+	spread := float64(d) * 0.2
+	return time.Duration(float64(d) - spread + rand.Float64()*2*spread)
+}
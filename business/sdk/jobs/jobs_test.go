@@ -0,0 +1,180 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/jobs"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+func Test_Settle(t *testing.T) {
+	t.Parallel()
+
+	cfg := jobs.Config{
+		MaxAttempts:  10,
+		PollInterval: time.Millisecond,
+		InitialDelay: time.Second,
+		MaxDelay:     10 * time.Second,
+	}
+
+	failed := errors.New("webhook down")
+
+	table := []struct {
+		name    string
+		attempt int
+		handler jobs.HandlerFunc
+		exp     string
+		min     time.Duration
+		max     time.Duration
+		called  bool
+	}{
+		{
+			name:    "success",
+			attempt: 1,
+			handler: func(ctx context.Context, job jobs.Job) error { return nil },
+			exp:     "ack",
+			called:  true,
+		},
+		{
+			name:    "first-retry",
+			attempt: 1,
+			handler: func(ctx context.Context, job jobs.Job) error { return failed },
+			exp:     "retry",
+			min:     800 * time.Millisecond,
+			max:     1200 * time.Millisecond,
+			called:  true,
+		},
+		{
+			name:    "doubled-retry",
+			attempt: 3,
+			handler: func(ctx context.Context, job jobs.Job) error { return failed },
+			exp:     "retry",
+			min:     3200 * time.Millisecond,
+			max:     4800 * time.Millisecond,
+			called:  true,
+		},
+		{
+			name:    "capped-retry",
+			attempt: 6,
+			handler: func(ctx context.Context, job jobs.Job) error { return failed },
+			exp:     "retry",
+			min:     8 * time.Second,
+			max:     12 * time.Second,
+			called:  true,
+		},
+		{
+			name:    "panic",
+			attempt: 1,
+			handler: func(ctx context.Context, job jobs.Job) error { panic("boom") },
+			exp:     "retry",
+			min:     800 * time.Millisecond,
+			max:     1200 * time.Millisecond,
+			called:  true,
+		},
+		{
+			name:    "permanent",
+			attempt: 1,
+			handler: func(ctx context.Context, job jobs.Job) error { return jobs.Permanent(failed) },
+			exp:     "dead",
+			called:  true,
+		},
+		{
+			name:    "last-attempt",
+			attempt: 10,
+			handler: func(ctx context.Context, job jobs.Job) error { return failed },
+			exp:     "dead",
+			called:  true,
+		},
+		{
+			name:    "attempts-exhausted",
+			attempt: 11,
+			handler: func(ctx context.Context, job jobs.Job) error { return nil },
+			exp:     "dead",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			backend := memBackend{
+				jobs:    []jobs.Job{{ID: "1", Queue: "alerts", Attempt: tt.attempt}},
+				settled: cancel,
+			}
+
+			var called bool
+			handler := func(ctx context.Context, job jobs.Job) error {
+				called = true
+				return tt.handler(ctx, job)
+			}
+
+			jobs.New(logger.NewNop(), &backend).Consume(ctx, "alerts", cfg, handler)
+
+			if called != tt.called {
+				t.Errorf("should call the handler %t, got %t", tt.called, called)
+			}
+
+			if backend.outcome != tt.exp {
+				t.Fatalf("should settle the job with %s, got %q", tt.exp, backend.outcome)
+			}
+
+			if tt.exp == "retry" && (backend.delay < tt.min || backend.delay > tt.max) {
+				t.Errorf("should retry in [%s, %s], got %s", tt.min, tt.max, backend.delay)
+			}
+		})
+	}
+}
+
+// memBackend hands out its jobs once and records how the job was settled.
+type memBackend struct {
+	mu      sync.Mutex
+	jobs    []jobs.Job
+	settled func()
+	outcome string
+	delay   time.Duration
+}
+
+func (b *memBackend) Enqueue(ctx context.Context, queue string, payload []byte) error {
+	return nil
+}
+
+func (b *memBackend) Receive(ctx context.Context, queue string, max int, visibility time.Duration) ([]jobs.Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := min(max, len(b.jobs))
+	received := b.jobs[:n]
+	b.jobs = b.jobs[n:]
+
+	return received, nil
+}
+
+func (b *memBackend) Ack(ctx context.Context, job jobs.Job) error {
+	b.settle("ack", 0)
+	return nil
+}
+
+func (b *memBackend) Retry(ctx context.Context, job jobs.Job, delay time.Duration, cause error) error {
+	b.settle("retry", delay)
+	return nil
+}
+
+func (b *memBackend) DeadLetter(ctx context.Context, job jobs.Job, cause error) error {
+	b.settle("dead", 0)
+	return nil
+}
+
+func (b *memBackend) settle(outcome string, delay time.Duration) {
+	b.mu.Lock()
+	b.outcome, b.delay = outcome, delay
+	b.mu.Unlock()
+
+	b.settled()
+}
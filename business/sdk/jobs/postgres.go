@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Postgres is a Backend that keeps jobs in the jobs table. Receiving claims
+// rows with SKIP LOCKED so consumers on every replica share the queue, and
+// dead-lettered jobs stay in the table for inspection.
+type Postgres struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+// NewPostgres constructs a Postgres backend.
+func NewPostgres(log *logger.Logger, db *sqlx.DB) *Postgres {
+	return &Postgres{
+		log: log,
+		db:  db,
+	}
+}
+
+// Enqueue implements the Backend interface.
+func (p *Postgres) Enqueue(ctx context.Context, queue string, payload []byte) error {
+	const q = `
+	INSERT INTO jobs
+		(job_id, queue, payload, attempts, dead, visible_at, date_created)
+	VALUES
+		($1, $2, $3, 0, FALSE, $4, $4)`
+
+	now := time.Now().UTC()
+
+	if _, err := p.db.ExecContext(ctx, q, id.New(), queue, payload, now); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// Receive implements the Backend interface.
+func (p *Postgres) Receive(ctx context.Context, queue string, max int, visibility time.Duration) ([]Job, error) {
+	tx, err := sqldb.BeginTx(ctx, p.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const qs = `
+	SELECT
+		job_id
+	FROM
+		jobs
+	WHERE
+		queue = :queue AND NOT dead AND visible_at <= :now
+	ORDER BY
+		visible_at
+	LIMIT :max`
+
+	now := time.Now().UTC()
+
+	data := map[string]any{
+		"queue": queue,
+		"now":   now,
+		"max":   max,
+	}
+
+	var claimed []struct {
+		ID uuid.UUID `db:"job_id"`
+	}
+	if err := sqldb.NamedQueryForUpdate(ctx, p.log, tx, sqldb.LockSkipLocked, qs, data, &claimed); err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(claimed))
+	for i, c := range claimed {
+		ids[i] = c.ID
+	}
+
+	const qu = `
+	UPDATE
+		jobs
+	SET
+		"attempts" = attempts + 1,
+		"visible_at" = $2
+	WHERE
+		job_id = ANY($1)
+	RETURNING
+		job_id, queue, payload, attempts, date_created`
+
+	var dbJobs []dbJob
+	if err := tx.SelectContext(ctx, &dbJobs, qu, ids, now.Add(visibility)); err != nil {
+		return nil, fmt.Errorf("selectcontext: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	jobs := make([]Job, len(dbJobs))
+	for i, dbj := range dbJobs {
+		jobs[i] = Job{
+			ID:          dbj.ID.String(),
+			Queue:       dbj.Queue,
+			Payload:     dbj.Payload,
+			Attempt:     dbj.Attempts,
+			DateCreated: dbj.DateCreated.In(time.Local),
+			handle:      dbj.Attempts,
+		}
+	}
+
+	return jobs, nil
+}
+
+// Ack implements the Backend interface. A job that was received again by
+// another consumer after its visibility expired is left to that consumer.
+func (p *Postgres) Ack(ctx context.Context, job Job) error {
+	const q = `
+	DELETE FROM
+		jobs
+	WHERE
+		job_id = $1 AND attempts = $2`
+
+	if _, err := p.db.ExecContext(ctx, q, job.ID, job.handle); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// Retry implements the Backend interface.
+func (p *Postgres) Retry(ctx context.Context, job Job, delay time.Duration, cause error) error {
+	const q = `
+	UPDATE
+		jobs
+	SET
+		"visible_at" = $3,
+		"last_error" = $4
+	WHERE
+		job_id = $1 AND attempts = $2`
+
+	visible := time.Now().UTC().Add(delay)
+
+	if _, err := p.db.ExecContext(ctx, q, job.ID, job.handle, visible, cause.Error()); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeadLetter implements the Backend interface.
+func (p *Postgres) DeadLetter(ctx context.Context, job Job, cause error) error {
+	const q = `
+	UPDATE
+		jobs
+	SET
+		"dead" = TRUE,
+		"last_error" = $3
+	WHERE
+		job_id = $1 AND attempts = $2`
+
+	if _, err := p.db.ExecContext(ctx, q, job.ID, job.handle, cause.Error()); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+type dbJob struct {
+	ID          id.ID     `db:"job_id"`
+	Queue       string    `db:"queue"`
+	Payload     []byte    `db:"payload"`
+	Attempts    int       `db:"attempts"`
+	DateCreated time.Time `db:"date_created"`
+}
@@ -0,0 +1,86 @@
+package mid
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// AuditRecord describes an operation that changed state, or tried to.
+type AuditRecord struct {
+	Operation string
+	Outcome   string // "ok" or the name of the error code
+	Error     string
+	Duration  time.Duration
+	Time      time.Time
+}
+
+// Auditor stores audit records.
+type Auditor interface {
+	Audit(ctx context.Context, rec AuditRecord) error
+}
+
+// Audit captures a record for every operation that changes state. A record
+// that can't be stored is logged, it doesn't fail the operation.
+func Audit(log *logger.Logger, auditor Auditor) Middleware {
+	m := func(next HandlerFunc) HandlerFunc {
+		h := func(ctx context.Context) error {
+			op := GetOperation(ctx)
+			if op.ReadOnly {
+				return next(ctx)
+			}
+
+			now := time.Now()
+			err := next(ctx)
+
+			rec := AuditRecord{
+				Operation: op.Name,
+				Outcome:   outcome(err),
+				Duration:  time.Since(now),
+				Time:      now,
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+
+			if aErr := auditor.Audit(ctx, rec); aErr != nil {
+				log.Error(ctx, "audit", "operation", op.Name, "error", aErr)
+			}
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// LogAuditor writes audit records to the log at the audit level, so they
+// reach the sinks keeping the audit trail.
+type LogAuditor struct {
+	log *logger.Logger
+}
+
+// NewLogAuditor constructs an auditor writing to the log.
+func NewLogAuditor(log *logger.Logger) *LogAuditor {
+	return &LogAuditor{
+		log: log,
+	}
+}
+
+// Audit implements the Auditor interface.
+func (a *LogAuditor) Audit(ctx context.Context, rec AuditRecord) error {
+	a.log.Audit(ctx, "audit", "operation", rec.Operation, "outcome", rec.Outcome, "error", rec.Error, "took", rec.Duration.String())
+	return nil
+}
+
+// outcome names the result of an operation.
+func outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return errs.CodeOf(err).String()
+}
@@ -0,0 +1,108 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// Authorizer decides whether the caller carried by the context may invoke
+// the operation.
+type Authorizer interface {
+	Authorize(ctx context.Context, op Operation) error
+}
+
+// Authorize refuses the operation with PermissionDenied unless the
+// authorizer allows it. Errors that already carry a code are kept, so an
+// authorizer can report Unauthenticated.
+func Authorize(authorizer Authorizer) Middleware {
+	m := func(next HandlerFunc) HandlerFunc {
+		h := func(ctx context.Context) error {
+			if err := authorizer.Authorize(ctx, GetOperation(ctx)); err != nil {
+				if errs.IsError(err) {
+					return err
+				}
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return next(ctx)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// =============================================================================
+
+// Claims identifies the caller of an operation. The transport sets it once
+// the caller's token is verified.
+type Claims struct {
+	Subject string
+	Roles   []string
+}
+
+const claimsKey ctxKey = 2
+
+// WithClaims stores the claims of the caller in the context.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// GetClaims returns the claims of the caller stored in the context, false
+// when the caller isn't authenticated.
+func GetClaims(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(Claims)
+	return claims, ok
+}
+
+// =============================================================================
+
+// Roles lists who may invoke the operations.
+type Roles struct {
+	Public []string // Operations anyone may invoke, authenticated or not
+	Read   []string // Roles allowed to invoke read only operations
+	Write  []string // Roles allowed to invoke operations that change state
+}
+
+// RoleAuthorizer allows operations by the roles of the caller.
+type RoleAuthorizer struct {
+	roles Roles
+}
+
+// NewRoleAuthorizer constructs an authorizer for the roles.
+func NewRoleAuthorizer(roles Roles) *RoleAuthorizer {
+	return &RoleAuthorizer{
+		roles: roles,
+	}
+}
+
+// Authorize implements the Authorizer interface. Callers that aren't
+// authenticated get Unauthenticated unless the operation is public, callers
+// without one of the roles get PermissionDenied.
+func (a *RoleAuthorizer) Authorize(ctx context.Context, op Operation) error {
+	if slices.Contains(a.roles.Public, op.Name) {
+		return nil
+	}
+
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		return errs.Newf(errs.Unauthenticated, "authentication required")
+	}
+
+	allowed := a.roles.Write
+	if op.ReadOnly {
+		allowed = a.roles.Read
+	}
+
+	for _, role := range claims.Roles {
+		if slices.Contains(allowed, role) {
+			return nil
+		}
+	}
+
+	return errors.New("caller doesn't have a role allowed to invoke the operation")
+}
@@ -0,0 +1,34 @@
+package mid
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+)
+
+// Metrics counts business operations and records their latency by
+// operation and outcome, whichever transport invoked them.
+func Metrics(provider metrics.Provider) Middleware {
+	m := func(next HandlerFunc) HandlerFunc {
+		h := func(ctx context.Context) error {
+			now := time.Now()
+			err := next(ctx)
+
+			labels := metrics.Labels{
+				"operation": GetOperation(ctx).Name,
+				"outcome":   outcome(err),
+			}
+
+			provider.Counter("business_operations_total", "Business operations invoked.", labels).Inc()
+			provider.Histogram("business_operation_duration_seconds", "Time spent in business operations.", metrics.DefaultBuckets, labels).
+				Observe(time.Since(now).Seconds())
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
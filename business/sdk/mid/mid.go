@@ -0,0 +1,48 @@
+// Package mid provides business level middleware that runs the same way for
+// every transport. The HTTP and gRPC layers adapt their handlers into a
+// HandlerFunc and run it through the chain, so rules like authorization,
+// transactions and auditing are written once.
+package mid
+
+import "context"
+
+// HandlerFunc represents a business operation invoked by a transport.
+type HandlerFunc func(ctx context.Context) error
+
+// Middleware wraps a HandlerFunc to run code before and after it.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Chain wraps the handler with the middleware. The first middleware is the
+// outermost one and runs first.
+func Chain(handler HandlerFunc, mw ...Middleware) HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			handler = mw[i](handler)
+		}
+	}
+	return handler
+}
+
+// =============================================================================
+
+// Operation describes the business operation being invoked. The transport
+// sets it before running the chain.
+type Operation struct {
+	Name     string // Route pattern or gRPC method
+	ReadOnly bool   // The operation doesn't change state
+}
+
+type ctxKey int
+
+const operationKey ctxKey = 1
+
+// WithOperation stores the operation in the context.
+func WithOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, operationKey, op)
+}
+
+// GetOperation returns the operation stored in the context.
+func GetOperation(ctx context.Context) Operation {
+	op, _ := ctx.Value(operationKey).(Operation)
+	return op
+}
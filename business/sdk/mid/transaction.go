@@ -0,0 +1,32 @@
+package mid
+
+import (
+	"context"
+
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// BeginCommitRollback runs operations that change state within a single
+// transaction that is committed when the operation succeeds. Business calls
+// made with the context join it, so an operation spanning domains is atomic.
+func BeginCommitRollback(log *logger.Logger, tran sqldb.Transactor) Middleware {
+	m := func(next HandlerFunc) HandlerFunc {
+		h := func(ctx context.Context) error {
+			if GetOperation(ctx).ReadOnly {
+				return next(ctx)
+			}
+
+			err := tran.WithTx(ctx, next)
+			if err != nil {
+				log.Info(ctx, "transaction", "status", "rolled back", "operation", GetOperation(ctx).Name)
+			}
+
+			return err
+		}
+
+		return h
+	}
+
+	return m
+}
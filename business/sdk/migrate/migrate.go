@@ -0,0 +1,432 @@
+// Package migrate contains the database schema, migrations and seeding data.
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	_ "embed" // Calls init function.
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/business/sdk/sqldb"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	//go:embed sql/migrate.sql
+	migrateDoc string
+
+	//go:embed sql/seed.sql
+	seedDoc string
+)
+
+// lockID is the advisory lock key held while migrations run, so replicas
+// starting at the same time don't apply the same version twice.
+const lockID = 534_272_001
+
+// ErrProduction is returned when reverting a migration is attempted in the
+// production environment.
+var ErrProduction = errors.New("migrate: down migrations are refused in production")
+
+// Production is the environment name in which down migrations are refused.
+const Production = "production"
+
+// Migration is a single versioned change to the schema. Down reverts the
+// change and is empty when the migration can't be reverted.
+type Migration struct {
+	Version     string
+	Description string
+	Script      string
+	Down        string
+}
+
+// VersionStatus reports whether a migration has been applied.
+type VersionStatus struct {
+	Version     string
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// Migrations returns the embedded migrations in the order they are applied.
+func Migrations() ([]Migration, error) {
+	return parse(migrateDoc)
+}
+
+// Migrate attempts to bring the database up to date with the migrations
+// defined in this package.
+func Migrate(ctx context.Context, db *sqlx.DB) error {
+	if err := sqldb.StatusCheck(ctx, db); err != nil {
+		return fmt.Errorf("status check database: %w", err)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	conn, unlock, err := lock(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := createTable(ctx, conn); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, exists := applied[m.Version]; exists {
+			continue
+		}
+
+		if err := apply(ctx, conn, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status reports every embedded migration and whether it has been applied.
+func Status(ctx context.Context, db *sqlx.DB) ([]VersionStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]VersionStatus, len(migrations))
+	for i, m := range migrations {
+		appliedAt, exists := applied[m.Version]
+		statuses[i] = VersionStatus{
+			Version:     m.Version,
+			Description: m.Description,
+			Applied:     exists,
+			AppliedAt:   appliedAt,
+		}
+	}
+
+	return statuses, nil
+}
+
+// DryRun writes the script of every pending migration to w without executing
+// anything against the database.
+func DryRun(ctx context.Context, db *sqlx.DB, w io.Writer) error {
+	statuses, err := Status(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	for i, m := range migrations {
+		if statuses[i].Applied {
+			continue
+		}
+
+		fmt.Fprintf(w, "-- Version: %s\n-- Description: %s\n%s\n\n", m.Version, m.Description, m.Script)
+	}
+
+	return nil
+}
+
+// Down reverts the most recently applied migration. It refuses to run in the
+// production environment, where schema changes only move forward.
+func Down(ctx context.Context, db *sqlx.DB, environment string) (Migration, error) {
+	if environment == Production {
+		return Migration{}, ErrProduction
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return Migration{}, err
+	}
+
+	conn, unlock, err := lock(ctx, db)
+	if err != nil {
+		return Migration{}, err
+	}
+	defer unlock()
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for _, m := range slices.Backward(migrations) {
+		if _, exists := applied[m.Version]; !exists {
+			continue
+		}
+
+		if err := revert(ctx, conn, m); err != nil {
+			return Migration{}, err
+		}
+
+		return m, nil
+	}
+
+	return Migration{}, errors.New("migrate: no applied migrations")
+}
+
+// Seed runs the seed document defined in this package against db. The
+// queries are run in a transaction and rolled back if any fail.
+func Seed(ctx context.Context, db *sqlx.DB) (err error) {
+	if err := sqldb.StatusCheck(ctx, db); err != nil {
+		return fmt.Errorf("status check database: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if errTx := tx.Rollback(); errTx != nil {
+			if errors.Is(errTx, sql.ErrTxDone) {
+				return
+			}
+
+			err = fmt.Errorf("rollback: %w", errTx)
+			return
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, seedDoc); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// lock acquires a connection holding the migration lock. The returned func
+// releases the lock and the connection.
+func lock(ctx context.Context, db *sqlx.DB) (*sqlx.Conn, func(), error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+
+	unlock := func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+		conn.Close()
+	}
+
+	return conn, unlock, nil
+}
+
+// createTable creates the table recording the applied migrations.
+func createTable(ctx context.Context, conn *sqlx.Conn) error {
+	const table = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version     TEXT      NOT NULL,
+		description TEXT      NOT NULL,
+		applied_at  TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+
+		PRIMARY KEY (version)
+	)`
+
+	if _, err := conn.ExecContext(ctx, table); err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// appliedVersions returns the applied versions with the time they were
+// applied. Nothing is applied yet when the table doesn't exist.
+func appliedVersions(ctx context.Context, conn *sqlx.Conn) (map[string]time.Time, error) {
+	applied := make(map[string]time.Time)
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check migrations table: %w", err)
+	}
+
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("query applied versions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v string
+		var at time.Time
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, fmt.Errorf("scan applied version: %w", err)
+		}
+		applied[v] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query applied versions: %w", err)
+	}
+
+	return applied, nil
+}
+
+// apply runs a single migration and records its version in a transaction.
+func apply(ctx context.Context, conn *sqlx.Conn, m Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %s: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.Script); err != nil {
+		return fmt.Errorf("apply migration %s %q: %w", m.Version, m.Description, err)
+	}
+
+	const q = `INSERT INTO schema_migrations (version, description) VALUES ($1, $2)`
+	if _, err := tx.ExecContext(ctx, q, m.Version, m.Description); err != nil {
+		return fmt.Errorf("record migration %s: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %s: %w", m.Version, err)
+	}
+
+	return nil
+}
+
+// revert runs the down script of a single migration and removes its version
+// in a transaction.
+func revert(ctx context.Context, conn *sqlx.Conn, m Migration) error {
+	if m.Down == "" {
+		return fmt.Errorf("migrate: migration %s %q can't be reverted", m.Version, m.Description)
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin revert %s: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+		return fmt.Errorf("revert migration %s %q: %w", m.Version, m.Description, err)
+	}
+
+	const q = `DELETE FROM schema_migrations WHERE version = $1`
+	if _, err := tx.ExecContext(ctx, q, m.Version); err != nil {
+		return fmt.Errorf("remove migration %s: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit revert %s: %w", m.Version, err)
+	}
+
+	return nil
+}
+
+// parse splits a migration document into its versions. Each version starts
+// with a "-- Version:" line followed by a "-- Description:" line. An optional
+// "-- Down:" line starts the script that reverts the version.
+func parse(doc string) ([]Migration, error) {
+	var migrations []Migration
+	var cur *Migration
+	var script, down strings.Builder
+	var inDown bool
+
+	flush := func() {
+		if cur != nil {
+			cur.Script = strings.TrimSpace(script.String())
+			cur.Down = strings.TrimSpace(down.String())
+			migrations = append(migrations, *cur)
+		}
+		script.Reset()
+		down.Reset()
+		inDown = false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(doc))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "-- Version:"):
+			flush()
+			cur = &Migration{
+				Version: strings.TrimSpace(strings.TrimPrefix(line, "-- Version:")),
+			}
+
+		case strings.HasPrefix(line, "-- Description:"):
+			if cur == nil {
+				return nil, errors.New("migrate: description before version")
+			}
+			cur.Description = strings.TrimSpace(strings.TrimPrefix(line, "-- Description:"))
+
+		case strings.HasPrefix(line, "-- Down:"):
+			if cur == nil {
+				return nil, errors.New("migrate: down before version")
+			}
+			inDown = true
+
+		default:
+			if cur == nil {
+				if strings.TrimSpace(line) != "" {
+					return nil, errors.New("migrate: statement before first version")
+				}
+				continue
+			}
+			if inDown {
+				down.WriteString(line)
+				down.WriteString("\n")
+				continue
+			}
+			script.WriteString(line)
+			script.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	seen := make(map[string]bool)
+	for _, m := range migrations {
+		if seen[m.Version] {
+			return nil, fmt.Errorf("migrate: duplicate version %s", m.Version)
+		}
+		seen[m.Version] = true
+	}
+
+	return migrations, nil
+}
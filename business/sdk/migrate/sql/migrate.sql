@@ -0,0 +1,304 @@
+-- Version: 1.01
+-- Description: Create table orders
+CREATE TABLE orders (
+	order_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	status       TEXT      NOT NULL,
+	currency     TEXT      NOT NULL,
+	customer     JSONB     NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (order_id)
+);
+
+CREATE INDEX orders_user_id_idx ON orders (user_id);
+
+-- Down:
+DROP TABLE orders;
+
+-- Version: 1.02
+-- Description: Create table order_items
+CREATE TABLE order_items (
+	order_item_id UUID   NOT NULL,
+	order_id      UUID   NOT NULL,
+	sku           TEXT   NOT NULL,
+	name          TEXT   NOT NULL,
+	quantity      INT    NOT NULL CHECK (quantity > 0),
+	unit_price    BIGINT NOT NULL CHECK (unit_price >= 0),
+
+	PRIMARY KEY (order_item_id),
+	FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX order_items_order_id_idx ON order_items (order_id);
+
+-- Down:
+DROP TABLE order_items;
+
+-- Version: 1.03
+-- Description: Add client supplied idempotency keys to orders
+ALTER TABLE orders ADD COLUMN idempotency_key TEXT NULL;
+ALTER TABLE orders ADD CONSTRAINT orders_idempotency_key UNIQUE (user_id, idempotency_key);
+
+-- Down:
+ALTER TABLE orders DROP CONSTRAINT orders_idempotency_key;
+ALTER TABLE orders DROP COLUMN idempotency_key;
+
+-- Version: 1.04
+-- Description: Create table customers
+CREATE TABLE customers (
+	customer_id  UUID      NOT NULL,
+	user_id      UUID      NULL,
+	name         TEXT      NOT NULL,
+	email        TEXT      UNIQUE NOT NULL,
+	phone        TEXT      NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (customer_id)
+);
+
+CREATE INDEX customers_user_id_idx ON customers (user_id);
+
+-- Down:
+DROP TABLE customers;
+
+-- Version: 1.05
+-- Description: Create table customer_addresses
+CREATE TABLE customer_addresses (
+	address_id  UUID NOT NULL,
+	customer_id UUID NOT NULL,
+	kind        TEXT NOT NULL,
+	line1       TEXT NOT NULL,
+	line2       TEXT NULL,
+	city        TEXT NOT NULL,
+	state       TEXT NOT NULL,
+	zip_code    TEXT NOT NULL,
+	country     TEXT NOT NULL,
+
+	PRIMARY KEY (address_id),
+	FOREIGN KEY (customer_id) REFERENCES customers(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX customer_addresses_customer_id_idx ON customer_addresses (customer_id);
+
+-- Down:
+DROP TABLE customer_addresses;
+
+-- Version: 1.06
+-- Description: Reference customers from orders
+ALTER TABLE orders ADD COLUMN customer_id UUID NULL REFERENCES customers(customer_id);
+ALTER TABLE orders DROP COLUMN customer;
+
+CREATE INDEX orders_customer_id_idx ON orders (customer_id);
+
+-- Down:
+ALTER TABLE orders ADD COLUMN customer JSONB NULL;
+ALTER TABLE orders DROP COLUMN customer_id;
+
+-- Version: 1.07
+-- Description: Create table inventory
+CREATE TABLE inventory (
+	sku          TEXT      NOT NULL,
+	name         TEXT      NOT NULL,
+	on_hand      INT       NOT NULL CHECK (on_hand >= 0),
+	reserved     INT       NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (sku),
+	CHECK (reserved <= on_hand)
+);
+
+-- Down:
+DROP TABLE inventory;
+
+-- Version: 1.08
+-- Description: Create table reservations
+CREATE TABLE reservations (
+	reservation_id UUID      NOT NULL,
+	order_id       UUID      NOT NULL,
+	sku            TEXT      NOT NULL,
+	quantity       INT       NOT NULL CHECK (quantity > 0),
+	status         TEXT      NOT NULL,
+	expires_at     TIMESTAMP NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (reservation_id),
+	FOREIGN KEY (sku) REFERENCES inventory(sku)
+);
+
+CREATE INDEX reservations_order_id_idx ON reservations (order_id);
+CREATE INDEX reservations_held_expires_at_idx ON reservations (expires_at) WHERE status = 'HELD';
+
+-- Down:
+DROP TABLE reservations;
+
+-- Version: 1.09
+-- Description: Add priced amounts to orders
+ALTER TABLE orders
+	ADD COLUMN subtotal         BIGINT NOT NULL DEFAULT 0 CHECK (subtotal >= 0),
+	ADD COLUMN discount         BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0),
+	ADD COLUMN tax              BIGINT NOT NULL DEFAULT 0 CHECK (tax >= 0),
+	ADD COLUMN total            BIGINT NOT NULL DEFAULT 0 CHECK (total >= 0),
+	ADD COLUMN tax_jurisdiction TEXT   NOT NULL DEFAULT '';
+
+UPDATE orders AS o SET
+	subtotal = li.subtotal,
+	total    = li.subtotal
+FROM (
+	SELECT order_id, SUM(quantity * unit_price) AS subtotal
+	FROM order_items
+	GROUP BY order_id
+) AS li
+WHERE o.order_id = li.order_id;
+
+-- Down:
+ALTER TABLE orders
+	DROP COLUMN subtotal,
+	DROP COLUMN discount,
+	DROP COLUMN tax,
+	DROP COLUMN total,
+	DROP COLUMN tax_jurisdiction;
+
+-- Version: 1.10
+-- Description: Create table jobs
+CREATE TABLE jobs (
+	job_id       UUID      NOT NULL,
+	queue        TEXT      NOT NULL,
+	payload      BYTEA     NOT NULL,
+	attempts     INT       NOT NULL DEFAULT 0,
+	last_error   TEXT      NULL,
+	dead         BOOLEAN   NOT NULL DEFAULT FALSE,
+	visible_at   TIMESTAMP NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (job_id)
+);
+
+CREATE INDEX jobs_queue_visible_at_idx ON jobs (queue, visible_at) WHERE NOT dead;
+
+-- Down:
+DROP TABLE jobs;
+
+-- Version: 1.11
+-- Description: Create table audits
+CREATE TABLE audits (
+	audit_id   UUID      NOT NULL,
+	obj_id     UUID      NOT NULL,
+	obj_domain TEXT      NOT NULL,
+	actor_id   UUID      NULL,
+	action     TEXT      NOT NULL,
+	data       JSONB     NULL,
+	timestamp  TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (audit_id)
+);
+
+CREATE INDEX audits_obj_id_idx ON audits (obj_id);
+CREATE INDEX audits_actor_id_idx ON audits (actor_id);
+CREATE INDEX audits_timestamp_idx ON audits (timestamp);
+
+-- Down:
+DROP TABLE audits;
+
+-- Version: 1.12
+-- Description: Create table audit_archives
+CREATE TABLE audit_archives (
+	archive_id      UUID      NOT NULL,
+	start_timestamp TIMESTAMP NOT NULL,
+	end_timestamp   TIMESTAMP NOT NULL,
+	row_count       INT       NOT NULL,
+	data            BYTEA     NOT NULL,
+	date_created    TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (archive_id)
+);
+
+CREATE INDEX audit_archives_date_created_idx ON audit_archives (date_created);
+
+-- Down:
+DROP TABLE audit_archives;
+
+-- Version: 1.13
+-- Description: Create view view_orders
+CREATE OR REPLACE VIEW view_orders AS
+SELECT
+	o.order_id,
+	o.user_id,
+	o.customer_id,
+	c.name AS customer_name,
+	c.email AS customer_email,
+	o.status,
+	o.currency,
+	o.total,
+	COALESCE(li.item_count, 0) AS item_count,
+	COALESCE(li.units, 0) AS units,
+	o.date_created,
+	o.date_updated
+FROM
+	orders AS o
+LEFT JOIN
+	customers AS c ON c.customer_id = o.customer_id
+LEFT JOIN (
+	SELECT order_id, count(1) AS item_count, SUM(quantity) AS units
+	FROM order_items
+	GROUP BY order_id
+) AS li ON li.order_id = o.order_id;
+
+-- Down:
+DROP VIEW view_orders;
+
+-- Version: 1.14
+-- Description: Soft delete customers and archive them
+ALTER TABLE customers ADD COLUMN date_deleted TIMESTAMP NULL;
+ALTER TABLE customers DROP CONSTRAINT customers_email_key;
+
+CREATE UNIQUE INDEX customers_email_key ON customers (email) WHERE date_deleted IS NULL;
+CREATE INDEX customers_date_deleted_idx ON customers (date_deleted) WHERE date_deleted IS NOT NULL;
+
+CREATE TABLE customers_archive (
+	customer_id   UUID      NOT NULL,
+	data          JSONB     NOT NULL,
+	date_deleted  TIMESTAMP NOT NULL,
+	date_archived TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (customer_id)
+);
+
+CREATE INDEX customers_archive_date_archived_idx ON customers_archive (date_archived);
+
+-- Down:
+DROP TABLE customers_archive;
+DELETE FROM customers WHERE date_deleted IS NOT NULL;
+DROP INDEX customers_email_key;
+ALTER TABLE customers ADD CONSTRAINT customers_email_key UNIQUE (email);
+ALTER TABLE customers DROP COLUMN date_deleted;
+
+-- Version: 1.15
+-- Description: Add blind index for encrypted customer emails
+ALTER TABLE customers ADD COLUMN email_hash TEXT NULL;
+
+CREATE UNIQUE INDEX customers_email_hash_key ON customers (email_hash) WHERE date_deleted IS NULL;
+
+-- Down:
+DROP INDEX customers_email_hash_key;
+ALTER TABLE customers DROP COLUMN email_hash;
+
+-- Version: 1.16
+-- Description: Create table users
+CREATE TABLE users (
+	user_id       UUID      NOT NULL,
+	name          TEXT      NOT NULL,
+	email         TEXT      UNIQUE NOT NULL,
+	roles         TEXT[]    NOT NULL,
+	password_hash TEXT      NOT NULL,
+	enabled       BOOLEAN   NOT NULL,
+	date_created  TIMESTAMP NOT NULL,
+	date_updated  TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (user_id)
+);
+
+-- Down:
+DROP TABLE users;
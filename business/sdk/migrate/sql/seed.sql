@@ -0,0 +1,34 @@
+INSERT INTO users (user_id, name, email, roles, password_hash, enabled, date_created, date_updated) VALUES
+	('5cf37266-3473-4006-984f-9325122678b7', 'Admin Gopher', 'admin@example.com', '{ADMIN}', '$2a$10$fLInFz0e6YlQhvRP56D8Yus44kX40WA8WDf6JuU.iqK6O9DWKvCQK', true, '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+	('45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'User Gopher', 'user@example.com', '{USER}', '$2a$10$fLInFz0e6YlQhvRP56D8Yus44kX40WA8WDf6JuU.iqK6O9DWKvCQK', true, '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO customers (customer_id, user_id, name, email, phone, date_created, date_updated) VALUES
+	('3c1f3a55-8a0c-4dd4-9b57-9f1cc1bb2f01', '5cf37266-3473-4006-984f-9325122678b7', 'Hack Er', 'hacker@example.com', '+1 305 555 0100', '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+	('e8a4a1f2-6f0d-4a53-8f4b-0d0c2b18b702', '45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'Bill Kennedy', 'bill@example.com', NULL, '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO customer_addresses (address_id, customer_id, kind, line1, line2, city, state, zip_code, country) VALUES
+	('0b6e4a1c-2a7d-4b8f-a4a3-5c8e2a1d3f01', '3c1f3a55-8a0c-4dd4-9b57-9f1cc1bb2f01', 'SHIPPING', '123 Main St', NULL, 'Miami', 'FL', '33101', 'US'),
+	('6d2f8c3b-9e4a-4f1d-b7c2-1a3e5d7f9b02', 'e8a4a1f2-6f0d-4a53-8f4b-0d0c2b18b702', 'BILLING', '1 Ardan Way', 'Suite 100', 'Miami', 'FL', '33131', 'US')
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO orders (order_id, user_id, customer_id, status, currency, subtotal, discount, tax, total, tax_jurisdiction, date_created, date_updated) VALUES
+	('a2b0639f-2cc6-44b8-b97b-15d69dbb511e', '5cf37266-3473-4006-984f-9325122678b7', '3c1f3a55-8a0c-4dd4-9b57-9f1cc1bb2f01', 'PENDING', 'USD', 10000, 0, 700, 10700, 'US-FL', '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+	('72f8b983-3eb4-48db-9ed0-e45cc6bd716b', '45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'e8a4a1f2-6f0d-4a53-8f4b-0d0c2b18b702', 'PAID', 'USD', 37500, 0, 2625, 40125, 'US-FL', '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO order_items (order_item_id, order_id, sku, name, quantity, unit_price) VALUES
+	('98b6d4b8-f04b-4c79-8c2e-a0aef46854b7', 'a2b0639f-2cc6-44b8-b97b-15d69dbb511e', 'COMIC-001', 'Comic Books', 2, 5000),
+	('85f6fb09-eb05-4874-ae39-82d1a30fe0d7', '72f8b983-3eb4-48db-9ed0-e45cc6bd716b', 'MCDONALDS-01', 'McDonalds Toys', 5, 7500)
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO inventory (sku, name, on_hand, reserved, date_updated) VALUES
+	('COMIC-001', 'Comic Books', 50, 2, '2019-03-24 00:00:00'),
+	('MCDONALDS-01', 'McDonalds Toys', 100, 0, '2019-03-24 00:00:00')
+	ON CONFLICT DO NOTHING;
+
+INSERT INTO reservations (reservation_id, order_id, sku, quantity, status, expires_at, date_created) VALUES
+	('c4d5e6f7-1a2b-4c3d-8e9f-0a1b2c3d4e01', 'a2b0639f-2cc6-44b8-b97b-15d69dbb511e', 'COMIC-001', 2, 'HELD', '2099-01-01 00:00:00', '2019-03-24 00:00:00'),
+	('d5e6f7a8-2b3c-4d4e-9f0a-1b2c3d4e5f02', '72f8b983-3eb4-48db-9ed0-e45cc6bd716b', 'MCDONALDS-01', 5, 'COMMITTED', '2019-03-24 00:15:00', '2019-03-24 00:00:00')
+	ON CONFLICT DO NOTHING;
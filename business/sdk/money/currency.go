@@ -0,0 +1,84 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exponents lists the ISO 4217 currencies that don't have two minor unit
+// digits. Every other code uses two.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Currency is an ISO 4217 currency with the number of digits of its minor
+// unit, like USD with 2 for cents.
+type Currency struct {
+	code     string
+	exponent int
+}
+
+// ParseCurrency parses a three letter ISO 4217 code like "USD".
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return Currency{}, fmt.Errorf("invalid currency %q", code)
+	}
+
+	exponent, exists := exponents[code]
+	if !exists {
+		exponent = 2
+	}
+
+	return Currency{code: code, exponent: exponent}, nil
+}
+
+// MustParseCurrency parses the code and panics on error. It's intended for
+// package level declarations.
+func MustParseCurrency(code string) Currency {
+	c, err := ParseCurrency(code)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the ISO 4217 code.
+func (c Currency) String() string {
+	return c.code
+}
+
+// Exponent returns the number of digits of the minor unit.
+func (c Currency) Exponent() int {
+	return c.exponent
+}
+
+// IsZero reports whether the currency is unset.
+func (c Currency) IsZero() bool {
+	return c.code == ""
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (c Currency) Equal(c2 Currency) bool {
+	return c.code == c2.code
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c.code), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Currency) UnmarshalText(data []byte) error {
+	cur, err := ParseCurrency(string(data))
+	if err != nil {
+		return err
+	}
+	*c = cur
+	return nil
+}
@@ -0,0 +1,317 @@
+// Package money represents amounts of a currency as integer minor units, so
+// financial values are never held in a float. Arithmetic that can produce a
+// fraction of a minor unit takes an explicit rounding mode.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// Set of error variables for money arithmetic.
+var (
+	ErrCurrencyMismatch = errs.Newf(errs.InvalidArgument, "currencies don't match")
+	ErrOverflow         = errs.Newf(errs.InvalidArgument, "amount out of range")
+)
+
+// Money is an amount in the minor units of its currency. The zero value is
+// zero of no currency and adopts the currency of whatever it's added to.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New constructs an amount from minor units, like cents for USD.
+func New(minor int64, currency Currency) Money {
+	return Money{amount: minor, currency: currency}
+}
+
+// Zero returns zero of the currency.
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// Parse parses a decimal amount in major units like "12.34". More fraction
+// digits than the currency has are refused rather than rounded.
+func Parse(value string, currency Currency) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	digits, negative := strings.CutPrefix(value, "-")
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, fmt.Errorf("amount %q: invalid", value)
+	}
+
+	trimmed := strings.TrimRight(frac, "0")
+	if len(trimmed) > currency.exponent {
+		return Money{}, fmt.Errorf("amount %q: more than %d decimal places for %s", value, currency.exponent, currency)
+	}
+	frac = trimmed + strings.Repeat("0", currency.exponent-len(trimmed))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q: invalid", value)
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// Minor returns the amount in minor units.
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the amount.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (m Money) Equal(m2 Money) bool {
+	return m.amount == m2.amount && m.currency == m2.currency
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1.
+func (m Money) Cmp(m2 Money) (int, error) {
+	if _, err := m.common(m2); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.amount < m2.amount:
+		return -1, nil
+	case m.amount > m2.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns m + m2.
+func (m Money) Add(m2 Money) (Money, error) {
+	cur, err := m.common(m2)
+	if err != nil {
+		return Money{}, err
+	}
+
+	sum := m.amount + m2.amount
+	if (sum > m.amount) != (m2.amount > 0) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{amount: sum, currency: cur}, nil
+}
+
+// Sub returns m - m2.
+func (m Money) Sub(m2 Money) (Money, error) {
+	if m2.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(m2.Neg())
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Mul returns m * n, like a unit price times a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	if n != 0 && (m.amount*n)/n != m.amount {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: m.amount * n, currency: m.currency}, nil
+}
+
+// MulRat returns m * num / den rounded to a minor unit with the mode, like
+// a share of a tax rate.
+func (m Money) MulRat(num int64, den int64, rounding Rounding) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("money: division by zero")
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(num))
+	quo := rounding.Quo(product, big.NewInt(den))
+	if !quo.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{amount: quo.Int64(), currency: m.currency}, nil
+}
+
+// Allocate splits the amount in proportion to the weights without losing a
+// minor unit. The remainder left by rounding down is handed out one unit at
+// a time from the first share on.
+func (m Money) Allocate(weights ...int64) ([]Money, error) {
+	var total int64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, errors.New("money: negative weight")
+		}
+		total += w
+	}
+
+	if total == 0 {
+		return nil, errors.New("money: weights sum to zero")
+	}
+
+	shares := make([]Money, len(weights))
+	remainder := m.amount
+
+	for i, w := range weights {
+		share, err := m.MulRat(w, total, RoundDown)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+		remainder -= share.amount
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+
+	for i := 0; remainder != 0; i++ {
+		shares[i%len(shares)].amount += step
+		remainder -= step
+	}
+
+	return shares, nil
+}
+
+// Decimal returns the amount in major units with every minor digit, like
+// "12.30".
+func (m Money) Decimal() string {
+	s := strconv.FormatInt(m.amount, 10)
+	if m.currency.exponent == 0 {
+		return s
+	}
+
+	sign := ""
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign = "-"
+		s = rest
+	}
+
+	if len(s) <= m.currency.exponent {
+		s = strings.Repeat("0", m.currency.exponent-len(s)+1) + s
+	}
+
+	cut := len(s) - m.currency.exponent
+	return sign + s[:cut] + "." + s[cut:]
+}
+
+// String returns the amount with its currency, like "12.30 USD".
+func (m Money) String() string {
+	if m.currency.IsZero() {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.currency.code
+}
+
+// common returns the currency shared by both amounts. A zero amount of no
+// currency matches any currency.
+func (m Money) common(m2 Money) (Currency, error) {
+	switch {
+	case m.currency == m2.currency:
+		return m.currency, nil
+	case m.currency.IsZero() && m.amount == 0:
+		return m2.currency, nil
+	case m2.currency.IsZero() && m2.amount == 0:
+		return m.currency, nil
+	}
+
+	return Currency{}, fmt.Errorf("%s and %s: %w", m.currency, m2.currency, ErrCurrencyMismatch)
+}
+
+// =============================================================================
+
+// document is the JSON form of an amount. The amount is a string so no
+// client has to parse it as a float.
+type document struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(document{
+		Amount:   m.Decimal(),
+		Currency: m.currency.code,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	cur, err := ParseCurrency(doc.Currency)
+	if err != nil {
+		return err
+	}
+
+	money, err := Parse(doc.Amount, cur)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// Value implements the driver.Valuer interface. Only the minor units are
+// stored; the currency belongs in a column of its own.
+func (m Money) Value() (driver.Value, error) {
+	return m.amount, nil
+}
+
+// Scan implements the sql.Scanner interface. It sets the minor units and
+// leaves the currency as it is, so set the currency before or after.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		m.amount = v
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("scan money: %w", err)
+		}
+		m.amount = n
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("scan money: %w", err)
+		}
+		m.amount = n
+	default:
+		return fmt.Errorf("scan money: unsupported type %T", src)
+	}
+
+	return nil
+}
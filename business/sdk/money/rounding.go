@@ -0,0 +1,94 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// The set of rounding modes that can be used.
+var (
+	RoundHalfUp   = newRounding("half-up")
+	RoundHalfEven = newRounding("half-even")
+	RoundDown     = newRounding("down")
+	RoundUp       = newRounding("up")
+)
+
+// =============================================================================
+
+// Set of known rounding modes.
+var roundings = make(map[string]Rounding)
+
+// Rounding decides how a fractional minor unit is resolved. Down and up
+// round toward and away from zero; the half modes round to the nearest
+// unit and only differ on ties.
+type Rounding struct {
+	value string
+}
+
+func newRounding(mode string) Rounding {
+	r := Rounding{mode}
+	roundings[mode] = r
+	return r
+}
+
+// String returns the name of the rounding mode.
+func (r Rounding) String() string {
+	return r.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Rounding) Equal(r2 Rounding) bool {
+	return r.value == r2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Rounding) MarshalText() ([]byte, error) {
+	return []byte(r.value), nil
+}
+
+// ParseRounding parses the string value and returns a rounding mode if one
+// exists.
+func ParseRounding(value string) (Rounding, error) {
+	r, exists := roundings[value]
+	if !exists {
+		return Rounding{}, fmt.Errorf("invalid rounding %q", value)
+	}
+
+	return r, nil
+}
+
+// =============================================================================
+
+// Quo returns num / den rounded with the mode. The division is done on big
+// integers so the product feeding it can't overflow. The zero Rounding
+// rounds down.
+func (r Rounding) Quo(num *big.Int, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// QuoRem truncates toward zero, so moving away from zero means a step
+	// in the direction of the exact result.
+	away := big.NewInt(int64(num.Sign() * den.Sign()))
+
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	half := twice.Cmp(new(big.Int).Abs(den))
+
+	switch r {
+	case RoundUp:
+		quo.Add(quo, away)
+
+	case RoundHalfUp:
+		if half >= 0 {
+			quo.Add(quo, away)
+		}
+
+	case RoundHalfEven:
+		if half > 0 || (half == 0 && quo.Bit(0) == 1) {
+			quo.Add(quo, away)
+		}
+	}
+
+	return quo
+}
@@ -0,0 +1,99 @@
+// Package order provides support for describing the ordering of data.
+package order
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Set of directions for data ordering.
+const (
+	ASC  = "ASC"
+	DESC = "DESC"
+)
+
+var directions = map[string]string{
+	ASC:  "ASC",
+	DESC: "DESC",
+}
+
+// =============================================================================
+
+// By represents a field used to order by and direction.
+type By struct {
+	Field     string
+	Direction string
+}
+
+// NewBy constructs a new By value with no checks.
+func NewBy(field string, direction string) By {
+	return By{
+		Field:     field,
+		Direction: direction,
+	}
+}
+
+// Parse constructs a By value by parsing a string in the form of
+// "field,direction" like "date_created,DESC". The field must be a key of the
+// field mappings, which translate the names clients use to the names the
+// domain uses. The direction defaults to ASC and the default order is
+// returned for an empty string.
+func Parse(fieldMappings map[string]string, orderBy string, defaultOrder By) (By, error) {
+	if orderBy == "" {
+		return defaultOrder, nil
+	}
+
+	orderParts := strings.Split(orderBy, ",")
+
+	orgFieldName := strings.TrimSpace(orderParts[0])
+	fieldName, exists := fieldMappings[orgFieldName]
+	if !exists {
+		return By{}, fmt.Errorf("unknown order field %q", orgFieldName)
+	}
+
+	switch len(orderParts) {
+	case 1:
+		return NewBy(fieldName, ASC), nil
+
+	case 2:
+		direction := strings.ToUpper(strings.TrimSpace(orderParts[1]))
+		if _, exists := directions[direction]; !exists {
+			return By{}, fmt.Errorf("unknown direction %q", orderParts[1])
+		}
+		return NewBy(fieldName, direction), nil
+	}
+
+	return By{}, fmt.Errorf("unknown order %q", orderBy)
+}
+
+// Clause renders the ORDER BY clause for the order. The columns map the
+// fields of the domain to columns and are the only text taken into the SQL,
+// so client input never reaches the query. The tie breakers are appended
+// so paging over equal values is stable.
+func Clause(orderBy By, columns map[string]string, tieBreakers ...string) (string, error) {
+	column, exists := columns[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	direction, exists := directions[orderBy.Direction]
+	if !exists {
+		return "", fmt.Errorf("direction %q does not exist", orderBy.Direction)
+	}
+
+	var b strings.Builder
+	b.WriteString(" ORDER BY ")
+	b.WriteString(column)
+	b.WriteByte(' ')
+	b.WriteString(direction)
+
+	for _, tb := range tieBreakers {
+		if tb == column {
+			continue
+		}
+		b.WriteString(", ")
+		b.WriteString(tb)
+	}
+
+	return b.String(), nil
+}
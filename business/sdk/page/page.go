@@ -0,0 +1,99 @@
+// Package page provides support for query paging.
+package page
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Set of paging bounds shared by every query endpoint.
+const (
+	DefaultRowsPerPage = 10
+	MaxRowsPerPage     = 100
+)
+
+// Page represents the requested page and rows per page.
+type Page struct {
+	number int
+	rows   int
+}
+
+// Parse parses the strings and validates the values are in reason. Empty
+// strings select the first page and the default page size.
+func Parse(page string, rowsPerPage string) (Page, error) {
+	number := 1
+	if page != "" {
+		var err error
+		number, err = strconv.Atoi(page)
+		if err != nil || number <= 0 {
+			return Page{}, fmt.Errorf("page: must be a positive number")
+		}
+	}
+
+	rows := DefaultRowsPerPage
+	if rowsPerPage != "" {
+		var err error
+		rows, err = strconv.Atoi(rowsPerPage)
+		if err != nil || rows <= 0 || rows > MaxRowsPerPage {
+			return Page{}, fmt.Errorf("rows: must be between 1 and %d", MaxRowsPerPage)
+		}
+	}
+
+	p := Page{
+		number: number,
+		rows:   rows,
+	}
+
+	return p, nil
+}
+
+// MustParse creates a paging value for testing.
+func MustParse(page string, rowsPerPage string) Page {
+	pg, err := Parse(page, rowsPerPage)
+	if err != nil {
+		panic(err)
+	}
+
+	return pg
+}
+
+// String implements the stringer interface.
+func (p Page) String() string {
+	return fmt.Sprintf("page: %d rows: %d", p.number, p.rows)
+}
+
+// Number returns the page number.
+func (p Page) Number() int {
+	return p.number
+}
+
+// RowsPerPage returns the rows per page.
+func (p Page) RowsPerPage() int {
+	return p.rows
+}
+
+// Offset returns the number of rows to skip to reach the page.
+func (p Page) Offset() int {
+	return (p.number - 1) * p.rows
+}
+
+// =============================================================================
+
+// Document is the envelope returned by query endpoints.
+type Document[T any] struct {
+	Items       []T `json:"items"`
+	Total       int `json:"total"`
+	Page        int `json:"page"`
+	RowsPerPage int `json:"rowsPerPage"`
+}
+
+// NewDocument constructs a document for the items of the page and the total
+// number of matching rows.
+func NewDocument[T any](items []T, total int, page Page) Document[T] {
+	return Document[T]{
+		Items:       items,
+		Total:       total,
+		Page:        page.number,
+		RowsPerPage: page.rows,
+	}
+}
@@ -0,0 +1,179 @@
+// Package pricing computes order totals, discounts and tax by jurisdiction
+// using exact integer arithmetic on minor currency units.
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// Set of error variables for pricing.
+var (
+	ErrNegativeAmount   = errs.Newf(errs.InvalidArgument, "amount must not be negative")
+	ErrDiscountTooLarge = errs.Newf(errs.InvalidArgument, "discount must not exceed 100%%")
+)
+
+// Jurisdiction describes how tax is charged in a region such as "US-FL".
+type Jurisdiction struct {
+	Code     string
+	Rate     Rate
+	Rounding Rounding
+	PerLine  bool // Round tax on each line instead of on the order subtotal
+}
+
+// Line is a single priced line of an order.
+type Line struct {
+	Quantity  int
+	UnitPrice int64 // Minor currency units, e.g. cents
+}
+
+// LineQuote holds the computed amounts of a line in minor units.
+type LineQuote struct {
+	Subtotal int64
+	Discount int64
+	Tax      int64
+	Total    int64
+}
+
+// Quote holds the computed amounts of an order in minor units.
+type Quote struct {
+	Jurisdiction string
+	Lines        []LineQuote
+	Subtotal     int64
+	Discount     int64
+	Tax          int64
+	Total        int64
+}
+
+// Calculator prices orders against a set of tax jurisdictions.
+type Calculator struct {
+	jurisdictions map[string]Jurisdiction
+	rounding      Rounding
+}
+
+// New constructs a calculator for the given jurisdictions. Discounts are
+// rounded half-up; tax uses the rounding of its jurisdiction.
+func New(jurisdictions []Jurisdiction) *Calculator {
+	m := make(map[string]Jurisdiction, len(jurisdictions))
+	for _, j := range jurisdictions {
+		m[j.Code] = j
+	}
+
+	return &Calculator{
+		jurisdictions: m,
+		rounding:      RoundHalfUp,
+	}
+}
+
+// Jurisdiction returns the most specific configured jurisdiction for the
+// codes, which are tried in order, e.g. "US-FL" then "US". A zero rate
+// jurisdiction is returned when none match.
+func (c *Calculator) Jurisdiction(codes ...string) Jurisdiction {
+	for _, code := range codes {
+		if j, exists := c.jurisdictions[strings.ToUpper(code)]; exists {
+			return j
+		}
+	}
+
+	return Jurisdiction{Rounding: RoundHalfUp}
+}
+
+// Price computes line totals, the discount and the tax of an order. The
+// discount is taken from each line before tax. Per line jurisdictions round
+// the tax of every line; otherwise tax is rounded once on the discounted
+// subtotal and the lines carry their unrounded share truncated.
+func (c *Calculator) Price(lines []Line, discount Rate, j Jurisdiction) (Quote, error) {
+	if discount.ppm > rateScale {
+		return Quote{}, ErrDiscountTooLarge
+	}
+
+	q := Quote{
+		Jurisdiction: j.Code,
+		Lines:        make([]LineQuote, len(lines)),
+	}
+
+	for i, l := range lines {
+		if l.Quantity < 0 || l.UnitPrice < 0 {
+			return Quote{}, fmt.Errorf("line[%d]: %w", i, ErrNegativeAmount)
+		}
+
+		lq := LineQuote{
+			Subtotal: int64(l.Quantity) * l.UnitPrice,
+		}
+		lq.Discount = apply(c.rounding, lq.Subtotal, discount)
+
+		taxable := lq.Subtotal - lq.Discount
+		if j.PerLine {
+			lq.Tax = apply(j.Rounding, taxable, j.Rate)
+		} else {
+			lq.Tax = apply(RoundDown, taxable, j.Rate)
+		}
+		lq.Total = taxable + lq.Tax
+
+		q.Lines[i] = lq
+		q.Subtotal += lq.Subtotal
+		q.Discount += lq.Discount
+		q.Tax += lq.Tax
+	}
+
+	if !j.PerLine {
+		q.Tax = apply(j.Rounding, q.Subtotal-q.Discount, j.Rate)
+	}
+
+	q.Total = q.Subtotal - q.Discount + q.Tax
+
+	return q, nil
+}
+
+// =============================================================================
+
+// ParseJurisdictions parses a list of jurisdictions separated by semicolons,
+// like "US-FL=7%;US-NY=8.875%:half-even;CA=5%:half-up:line". The optional
+// suffixes select the tax rounding and per line rounding.
+func ParseJurisdictions(value string) ([]Jurisdiction, error) {
+	var js []Jurisdiction
+
+	for entry := range strings.SplitSeq(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("jurisdiction %q: missing rate", entry)
+		}
+
+		parts := strings.Split(spec, ":")
+
+		rate, err := ParseRate(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("jurisdiction %q: %w", code, err)
+		}
+
+		j := Jurisdiction{
+			Code:     strings.ToUpper(strings.TrimSpace(code)),
+			Rate:     rate,
+			Rounding: RoundHalfUp,
+		}
+
+		for _, opt := range parts[1:] {
+			if opt == "line" {
+				j.PerLine = true
+				continue
+			}
+
+			r, err := ParseRounding(opt)
+			if err != nil {
+				return nil, fmt.Errorf("jurisdiction %q: %w", code, err)
+			}
+			j.Rounding = r
+		}
+
+		js = append(js, j)
+	}
+
+	return js, nil
+}
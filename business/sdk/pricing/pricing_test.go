@@ -0,0 +1,254 @@
+package pricing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlmirSai/service/business/sdk/pricing"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Price(t *testing.T) {
+	t.Parallel()
+
+	onePct := pricing.MustParseRate("1%")
+	sevenPct := pricing.MustParseRate("7%")
+
+	orderUp := pricing.Jurisdiction{Code: "UP", Rate: onePct, Rounding: pricing.RoundHalfUp}
+	orderEven := pricing.Jurisdiction{Code: "EVEN", Rate: onePct, Rounding: pricing.RoundHalfEven}
+	lineUp := pricing.Jurisdiction{Code: "LINE-UP", Rate: onePct, Rounding: pricing.RoundHalfUp, PerLine: true}
+	lineEven := pricing.Jurisdiction{Code: "LINE-EVEN", Rate: onePct, Rounding: pricing.RoundHalfEven, PerLine: true}
+
+	table := []struct {
+		name     string
+		lines    []pricing.Line
+		discount pricing.Rate
+		j        pricing.Jurisdiction
+		exp      pricing.Quote
+	}{
+		{
+			name:  "half-up-tie",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 250}},
+			j:     orderUp,
+			exp: pricing.Quote{
+				Jurisdiction: "UP",
+				Lines:        []pricing.LineQuote{{Subtotal: 250, Tax: 2, Total: 252}},
+				Subtotal:     250,
+				Tax:          3,
+				Total:        253,
+			},
+		},
+		{
+			name:  "half-even-tie-down",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 250}},
+			j:     orderEven,
+			exp: pricing.Quote{
+				Jurisdiction: "EVEN",
+				Lines:        []pricing.LineQuote{{Subtotal: 250, Tax: 2, Total: 252}},
+				Subtotal:     250,
+				Tax:          2,
+				Total:        252,
+			},
+		},
+		{
+			name:  "half-even-tie-up",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 350}},
+			j:     orderEven,
+			exp: pricing.Quote{
+				Jurisdiction: "EVEN",
+				Lines:        []pricing.LineQuote{{Subtotal: 350, Tax: 3, Total: 353}},
+				Subtotal:     350,
+				Tax:          4,
+				Total:        354,
+			},
+		},
+		{
+			name:  "order-level-rounds-once",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 50}, {Quantity: 1, UnitPrice: 50}},
+			j:     orderUp,
+			exp: pricing.Quote{
+				Jurisdiction: "UP",
+				Lines: []pricing.LineQuote{
+					{Subtotal: 50, Tax: 0, Total: 50},
+					{Subtotal: 50, Tax: 0, Total: 50},
+				},
+				Subtotal: 100,
+				Tax:      1,
+				Total:    101,
+			},
+		},
+		{
+			name:  "per-line-half-up",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 50}, {Quantity: 1, UnitPrice: 50}},
+			j:     lineUp,
+			exp: pricing.Quote{
+				Jurisdiction: "LINE-UP",
+				Lines: []pricing.LineQuote{
+					{Subtotal: 50, Tax: 1, Total: 51},
+					{Subtotal: 50, Tax: 1, Total: 51},
+				},
+				Subtotal: 100,
+				Tax:      2,
+				Total:    102,
+			},
+		},
+		{
+			name:  "per-line-half-even",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 50}, {Quantity: 1, UnitPrice: 150}},
+			j:     lineEven,
+			exp: pricing.Quote{
+				Jurisdiction: "LINE-EVEN",
+				Lines: []pricing.LineQuote{
+					{Subtotal: 50, Tax: 0, Total: 50},
+					{Subtotal: 150, Tax: 2, Total: 152},
+				},
+				Subtotal: 200,
+				Tax:      2,
+				Total:    202,
+			},
+		},
+		{
+			name:  "exact",
+			lines: []pricing.Line{{Quantity: 4, UnitPrice: 2500}},
+			j:     pricing.Jurisdiction{Code: "US-FL", Rate: sevenPct, Rounding: pricing.RoundHalfEven},
+			exp: pricing.Quote{
+				Jurisdiction: "US-FL",
+				Lines:        []pricing.LineQuote{{Subtotal: 10000, Tax: 700, Total: 10700}},
+				Subtotal:     10000,
+				Tax:          700,
+				Total:        10700,
+			},
+		},
+		{
+			name:     "discount-tie-rounds-half-up",
+			lines:    []pricing.Line{{Quantity: 1, UnitPrice: 1005}},
+			discount: pricing.MustParseRate("10%"),
+			j:        pricing.Jurisdiction{Code: "US-FL", Rate: sevenPct, Rounding: pricing.RoundHalfUp},
+			exp: pricing.Quote{
+				Jurisdiction: "US-FL",
+				Lines:        []pricing.LineQuote{{Subtotal: 1005, Discount: 101, Tax: 63, Total: 967}},
+				Subtotal:     1005,
+				Discount:     101,
+				Tax:          63,
+				Total:        967,
+			},
+		},
+		{
+			name:  "near-overflow",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 1 << 60}},
+			j:     pricing.Jurisdiction{Code: "US-FL", Rate: sevenPct, Rounding: pricing.RoundHalfUp},
+			exp: pricing.Quote{
+				Jurisdiction: "US-FL",
+				Lines:        []pricing.LineQuote{{Subtotal: 1 << 60, Tax: 80704505322479288, Total: 1233626009929326264}},
+				Subtotal:     1 << 60,
+				Tax:          80704505322479288,
+				Total:        1233626009929326264,
+			},
+		},
+		{
+			name:     "full-discount",
+			lines:    []pricing.Line{{Quantity: 3, UnitPrice: 333}},
+			discount: pricing.MustParseRate("100%"),
+			j:        orderUp,
+			exp: pricing.Quote{
+				Jurisdiction: "UP",
+				Lines:        []pricing.LineQuote{{Subtotal: 999, Discount: 999}},
+				Subtotal:     999,
+				Discount:     999,
+			},
+		},
+	}
+
+	calc := pricing.New(nil)
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calc.Price(tt.lines, tt.discount, tt.j)
+			if err != nil {
+				t.Fatalf("should be able to price the order: %s", err)
+			}
+
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Errorf("should get the expected quote:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_PriceErrors(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name     string
+		lines    []pricing.Line
+		discount pricing.Rate
+		exp      error
+	}{
+		{
+			name:  "negative-price",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: -100}},
+			exp:   pricing.ErrNegativeAmount,
+		},
+		{
+			name:  "negative-quantity",
+			lines: []pricing.Line{{Quantity: 1, UnitPrice: 100}, {Quantity: -2, UnitPrice: 100}},
+			exp:   pricing.ErrNegativeAmount,
+		},
+		{
+			name:     "discount-over-100",
+			lines:    []pricing.Line{{Quantity: 1, UnitPrice: 100}},
+			discount: pricing.MustParseRate("100.0001%"),
+			exp:      pricing.ErrDiscountTooLarge,
+		},
+	}
+
+	calc := pricing.New(nil)
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.Price(tt.lines, tt.discount, calc.Jurisdiction("US"))
+			if !errors.Is(err, tt.exp) {
+				t.Errorf("should get %q, got %v", tt.exp, err)
+			}
+		})
+	}
+}
+
+func Test_ParseRate(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		value string
+		exp   string
+		fails bool
+	}{
+		{value: "7%", exp: "7%"},
+		{value: "8.875%", exp: "8.875%"},
+		{value: "0.08875", exp: "8.875%"},
+		{value: "0.0001%", exp: "0.0001%"},
+		{value: "-1%", fails: true},
+		{value: "-0.07", fails: true},
+		{value: "0.00001%", fails: true},
+		{value: "abc", fails: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.value, func(t *testing.T) {
+			r, err := pricing.ParseRate(tt.value)
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("should fail to parse %q, got %s", tt.value, r)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("should be able to parse %q: %s", tt.value, err)
+			}
+
+			if got := r.String(); got != tt.exp {
+				t.Errorf("should get %s, got %s", tt.exp, got)
+			}
+		})
+	}
+}
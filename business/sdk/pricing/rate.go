@@ -0,0 +1,99 @@
+package pricing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rateScale is the number of rate units in a whole, so rates are exact to
+// one ten-thousandth of a percent, enough for rates like 8.875%.
+const rateScale = 1_000_000
+
+// Rate is an exact decimal fraction such as a tax or discount rate. It's
+// stored as parts per million so no float arithmetic is involved.
+type Rate struct {
+	ppm int64
+}
+
+// ParseRate parses a percentage like "8.875%" or a fraction like "0.08875".
+func ParseRate(value string) (Rate, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "-") {
+		return Rate{}, fmt.Errorf("rate %q: must not be negative", value)
+	}
+
+	scale := int64(rateScale)
+	digits := 6
+	if s, ok := strings.CutSuffix(value, "%"); ok {
+		value = s
+		scale = rateScale / 100
+		digits = 4
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if len(frac) > digits {
+		return Rate{}, fmt.Errorf("rate %q: more than %d decimal places", value, digits)
+	}
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return Rate{}, fmt.Errorf("rate %q: invalid", value)
+	}
+
+	var f int64
+	if frac != "" {
+		f, err = strconv.ParseInt(frac+strings.Repeat("0", digits-len(frac)), 10, 64)
+		if err != nil {
+			return Rate{}, fmt.Errorf("rate %q: invalid", value)
+		}
+	}
+
+	return Rate{ppm: w*scale + f}, nil
+}
+
+// MustParseRate parses the rate and panics on error. It's intended for
+// package level declarations.
+func MustParseRate(value string) Rate {
+	r, err := ParseRate(value)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IsZero reports whether the rate is zero.
+func (r Rate) IsZero() bool {
+	return r.ppm == 0
+}
+
+// String returns the rate as a percentage without trailing zeros.
+func (r Rate) String() string {
+	whole := r.ppm / (rateScale / 100)
+	frac := r.ppm % (rateScale / 100)
+	if frac == 0 {
+		return fmt.Sprintf("%d%%", whole)
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%d.%04d", whole, frac), "0") + "%"
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Rate) Equal(r2 Rate) bool {
+	return r.ppm == r2.ppm
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Rate) UnmarshalText(data []byte) error {
+	rate, err := ParseRate(string(data))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
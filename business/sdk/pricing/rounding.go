@@ -0,0 +1,35 @@
+package pricing
+
+import (
+	"math/big"
+
+	"github.com/AlmirSai/service/business/sdk/money"
+)
+
+// Rounding decides how a fractional minor unit is resolved. The modes are
+// the ones of the money package.
+type Rounding = money.Rounding
+
+// The set of rounding modes that can be used.
+var (
+	RoundHalfUp   = money.RoundHalfUp
+	RoundHalfEven = money.RoundHalfEven
+	RoundDown     = money.RoundDown
+	RoundUp       = money.RoundUp
+)
+
+// ParseRounding parses the string value and returns a rounding mode if one
+// exists.
+func ParseRounding(value string) (Rounding, error) {
+	return money.ParseRounding(value)
+}
+
+// =============================================================================
+
+// apply computes amount * rate with the rounding mode applied to the result
+// in minor units. The intermediate product is computed with big integers so
+// large amounts can't overflow.
+func apply(r Rounding, amount int64, rate Rate) int64 {
+	num := new(big.Int).Mul(big.NewInt(amount), big.NewInt(rate.ppm))
+	return r.Quo(num, big.NewInt(rateScale)).Int64()
+}
@@ -0,0 +1,93 @@
+// Package rules provides support for checking business invariants that span
+// several fields or need a store lookup, like a total that has to match its
+// line items or a value that has to be unique. Request shape is validated in
+// the app layer, rules run in the business layer before anything is stored.
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/errs"
+)
+
+// Violation describes how a value breaks a rule.
+type Violation struct {
+	Code    errs.Code
+	Field   string
+	Message string
+}
+
+// Violate constructs a violation of a rule by the field. The code decides the
+// status the caller sees, like AlreadyExists for a uniqueness rule.
+func Violate(code errs.Code, field string, format string, args ...any) *Violation {
+	return &Violation{
+		Code:    code,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error implements the error interface.
+func (v *Violation) Error() string {
+	return v.Field + ": " + v.Message
+}
+
+// Rule checks an invariant of a value. It returns a *Violation when the value
+// breaks the invariant and any other error when the check itself failed.
+type Rule[T any] func(ctx context.Context, v T) error
+
+// Set is a list of rules checked together for one kind of value.
+type Set[T any] struct {
+	name  string
+	rules []Rule[T]
+}
+
+// New constructs a set of rules. The name leads the message of the error
+// returned when a value breaks any of them.
+func New[T any](name string, rules ...Rule[T]) *Set[T] {
+	return &Set[T]{
+		name:  name,
+		rules: rules,
+	}
+}
+
+// Check runs every rule against the value. The violations are collected into
+// a single coded error with the code of the first violation and a field entry
+// for each one. A rule that fails for another reason stops the check and its
+// error is returned as is.
+func (s *Set[T]) Check(ctx context.Context, v T) error {
+	var violations []*Violation
+	for _, rule := range s.rules {
+		err := rule(ctx, v)
+		if err == nil {
+			continue
+		}
+
+		var violation *Violation
+		if !errors.As(err, &violation) {
+			return fmt.Errorf("%s rules: %w", s.name, err)
+		}
+
+		violations = append(violations, violation)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(violations))
+	fields := make(map[string]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Error()
+		if _, exists := fields[v.Field]; !exists {
+			fields[v.Field] = v.Message
+		}
+	}
+
+	msg := fmt.Sprintf("%s: %s", s.name, strings.Join(msgs, "; "))
+
+	return errs.NewFields(violations[0].Code, msg, fields)
+}
@@ -0,0 +1,156 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes, see github.com/jackc/pgerrcode for the full list.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	checkViolation      = "23514"
+	lockNotAvailable    = "55P03"
+)
+
+// IsUniqueViolation reports whether the error was raised by a unique
+// constraint, for example a duplicated email.
+func IsUniqueViolation(err error) bool {
+	return pgCode(err) == uniqueViolation
+}
+
+// IsForeignKeyViolation reports whether the error was raised by a foreign key
+// constraint, for example deleting a row that is still referenced.
+func IsForeignKeyViolation(err error) bool {
+	return pgCode(err) == foreignKeyViolation
+}
+
+// IsCheckViolation reports whether the error was raised by a check
+// constraint, for example a quantity dropping below zero.
+func IsCheckViolation(err error) bool {
+	return pgCode(err) == checkViolation
+}
+
+// IsLockNotAvailable reports whether the error was raised because a row lock
+// couldn't be taken within lock_timeout or at once with NOWAIT.
+func IsLockNotAvailable(err error) bool {
+	return pgCode(err) == lockNotAvailable
+}
+
+// pgCode returns the SQLSTATE code carried by a postgres error.
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// =============================================================================
+
+// ConstraintError describes a statement rejected by a constraint. It wraps
+// the postgres error so the Is functions above keep working.
+type ConstraintError struct {
+	Table      string
+	Column     string
+	Constraint string
+	Referenced bool // A foreign key row that is still referenced was removed
+	code       string
+	err        *pgconn.PgError
+}
+
+// Error implements the error interface. The message names the column and
+// is safe to return to a client, the values of the row are left out.
+func (ce *ConstraintError) Error() string {
+	column := ce.Column
+	if column == "" {
+		column = ce.Constraint
+	}
+
+	switch ce.code {
+	case uniqueViolation:
+		return fmt.Sprintf("%s already exists", column)
+
+	case foreignKeyViolation:
+		if ce.Referenced {
+			return fmt.Sprintf("%s is still referenced", ce.Table)
+		}
+		return fmt.Sprintf("%s references a missing row", column)
+	}
+
+	return fmt.Sprintf("%s is not valid", column)
+}
+
+// Unwrap provides access to the postgres error.
+func (ce *ConstraintError) Unwrap() error {
+	return ce.err
+}
+
+// Translate converts a unique, foreign key or check constraint failure into
+// a coded error naming the offending column: AlreadyExists, FailedPrecondition
+// when a removed row is still referenced, and InvalidArgument otherwise.
+// Other errors are returned unchanged.
+func Translate(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var code errs.Code
+	switch pgErr.Code {
+	case uniqueViolation:
+		code = errs.AlreadyExists
+	case foreignKeyViolation:
+		code = errs.InvalidArgument
+	case checkViolation:
+		code = errs.InvalidArgument
+	default:
+		return err
+	}
+
+	ce := ConstraintError{
+		Table:      pgErr.TableName,
+		Column:     constraintColumn(pgErr),
+		Constraint: pgErr.ConstraintName,
+		Referenced: strings.Contains(pgErr.Detail, "is still referenced"),
+		code:       pgErr.Code,
+		err:        pgErr,
+	}
+
+	if ce.Referenced {
+		code = errs.FailedPrecondition
+	}
+
+	return errs.New(code, &ce)
+}
+
+// detailKey matches the columns postgres reports in the detail of unique and
+// foreign key violations, like: Key (email)=(a@b.c) already exists.
+var detailKey = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// constraintColumn returns the column that violated the constraint. When
+// postgres doesn't report it, it's taken from the detail or derived from the
+// default constraint name, like orders_customer_id_fkey.
+func constraintColumn(pgErr *pgconn.PgError) string {
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+
+	if m := detailKey.FindStringSubmatch(pgErr.Detail); m != nil {
+		return m[1]
+	}
+
+	name := strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_")
+	for _, suffix := range []string{"_key", "_fkey", "_check"} {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok {
+			return trimmed
+		}
+	}
+
+	return name
+}
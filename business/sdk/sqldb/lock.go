@@ -0,0 +1,79 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// ErrLockNotAvailable is returned when a locking read gives up on a row held
+// by another transaction.
+var ErrLockNotAvailable = errs.Newf(errs.Aborted, "row is locked by another transaction")
+
+// LockMode selects how a locking read treats rows already locked by another
+// transaction.
+type LockMode int
+
+// Set of lock modes.
+const (
+	LockWait       LockMode = iota // Wait for the rows, bounded by the deadline of the context
+	LockSkipLocked                 // Leave the locked rows out, for workers sharing a queue
+	LockNoWait                     // Fail at once with ErrLockNotAvailable
+)
+
+// clause returns the locking clause appended to the query.
+func (m LockMode) clause() string {
+	switch m {
+	case LockSkipLocked:
+		return " FOR UPDATE SKIP LOCKED"
+	case LockNoWait:
+		return " FOR UPDATE NOWAIT"
+	default:
+		return " FOR UPDATE"
+	}
+}
+
+// NamedQueryForUpdate appends a FOR UPDATE clause in the given mode to the
+// query and scans the rows into dest. The rows stay locked until the
+// transaction ends. With LockWait the wait is bounded by the deadline of the
+// context through lock_timeout, which holds for the rest of the transaction,
+// so a blocked read fails with ErrLockNotAvailable instead of outliving its
+// caller.
+func NamedQueryForUpdate[T any](ctx context.Context, log *logger.Logger, tx *Tx, mode LockMode, query string, data any, dest *[]T) error {
+	if mode == LockWait {
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := setLockTimeout(ctx, tx, time.Until(deadline)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := NamedQuerySlice(ctx, log, tx, query+mode.clause(), data, dest); err != nil {
+		if pgCode(err) == lockNotAvailable {
+			return fmt.Errorf("%w: %w", ErrLockNotAvailable, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// setLockTimeout bounds how long the statements of the transaction wait for
+// a lock. A deadline that already passed fails without touching the rows.
+func setLockTimeout(ctx context.Context, tx *Tx, d time.Duration) error {
+	ms := d.Milliseconds()
+	if ms <= 0 {
+		return fmt.Errorf("lock timeout: %w", context.DeadlineExceeded)
+	}
+
+	const q = `SELECT set_config('lock_timeout', $1, true)`
+
+	if _, err := tx.ExecContext(ctx, q, fmt.Sprintf("%dms", ms)); err != nil {
+		return fmt.Errorf("lock timeout: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// NamedExecContext is a helper function to execute a CUD operation with
+// logging and tracing where field replacement is necessary.
+func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) error {
+	q := queryString(query, data)
+
+	log.Debugc(ctx, 4, "database.NamedExecContext", "query", q)
+
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.exec", attribute.String("query", q))
+	defer span.End()
+
+	defer logSlowQuery(ctx, log, query, time.Now())
+
+	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+		return Translate(err)
+	}
+
+	return nil
+}
+
+// NamedQuerySlice is a helper function for executing queries that return a
+// collection of data to be unmarshalled into a slice where field replacement
+// is necessary.
+func NamedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, dest *[]T) error {
+	q := queryString(query, data)
+
+	log.Debugc(ctx, 4, "database.NamedQuerySlice", "query", q)
+
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.queryslice", attribute.String("query", q))
+	defer span.End()
+
+	defer logSlowQuery(ctx, log, query, time.Now())
+
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, data)
+	if err != nil {
+		return Translate(err)
+	}
+	defer rows.Close()
+
+	var slice []T
+	for rows.Next() {
+		v := new(T)
+		if err := rows.StructScan(v); err != nil {
+			return err
+		}
+		slice = append(slice, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Translate(err)
+	}
+
+	*dest = slice
+
+	return nil
+}
+
+// NamedQueryStruct is a helper function for executing queries that return a
+// single value to be unmarshalled into a struct type where field replacement
+// is necessary. ErrDBNotFound is returned when no row matches.
+func NamedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, dest any) error {
+	q := queryString(query, data)
+
+	log.Debugc(ctx, 4, "database.NamedQueryStruct", "query", q)
+
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.querystruct", attribute.String("query", q))
+	defer span.End()
+
+	defer logSlowQuery(ctx, log, query, time.Now())
+
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, data)
+	if err != nil {
+		return Translate(err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return Translate(err)
+		}
+		return ErrDBNotFound
+	}
+
+	if err := rows.StructScan(dest); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDBNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// queryString provides a pretty print version of the query and parameters
+// for logging. Values are inlined, so it's only meant for debug output.
+func queryString(query string, args any) string {
+	query, params, err := sqlx.Named(query, args)
+	if err != nil {
+		return err.Error()
+	}
+
+	for _, param := range params {
+		var value string
+		switch v := param.(type) {
+		case string:
+			value = fmt.Sprintf("'%s'", v)
+		case []byte:
+			value = fmt.Sprintf("'%s'", string(v))
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+		query = strings.Replace(query, "?", value, 1)
+	}
+
+	query = strings.ReplaceAll(query, "\t", "")
+	query = strings.ReplaceAll(query, "\n", " ")
+
+	return strings.TrimSpace(query)
+}
@@ -0,0 +1,58 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// slowQueryThreshold holds the duration above which queries are logged as
+// slow. Zero disables the logging.
+var slowQueryThreshold atomic.Int64
+
+// SetSlowQueryThreshold sets the duration above which the query helpers log
+// a statement as slow. Zero disables slow query logging.
+func SetSlowQueryThreshold(d time.Duration) {
+	slowQueryThreshold.Store(int64(d))
+}
+
+// logSlowQuery writes a warning for the query when it ran longer than the
+// threshold. The statement is logged with its fingerprint rather than its
+// values so slow queries group together and no data ends up in the logs.
+// The trace ID is added by the logger.
+func logSlowQuery(ctx context.Context, log *logger.Logger, query string, start time.Time) {
+	threshold := time.Duration(slowQueryThreshold.Load())
+	if threshold <= 0 {
+		return
+	}
+
+	took := time.Since(start)
+	if took < threshold {
+		return
+	}
+
+	stmt := normalize(query)
+
+	log.Warnc(ctx, 5, "database slow query",
+		"fingerprint", fingerprint(stmt),
+		"statement", stmt,
+		"took", took.String(),
+		"threshold", threshold.String())
+}
+
+// normalize collapses the whitespace of the statement into single spaces.
+func normalize(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// fingerprint returns a short stable identifier for the statement.
+func fingerprint(stmt string) string {
+	h := fnv.New64a()
+	h.Write([]byte(stmt))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,105 @@
+// Package sqldb provides support for access the database.
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/AlmirSai/service/foundation/retry"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrDBNotFound is returned when a query expected to match a row doesn't.
+// It carries the NotFound code so an untranslated miss still maps to a 404.
+var ErrDBNotFound = errs.Newf(errs.NotFound, "not found")
+
+// Config is the required properties to use the database.
+type Config struct {
+	User         string
+	Password     string
+	HostPort     string
+	Name         string
+	Schema       string
+	MaxIdleConns int
+	MaxOpenConns int
+	DisableTLS   bool
+	Log          *logger.Logger // Optional, logs the statements that fail
+}
+
+// Open knows how to open a database connection based on the configuration.
+// Every statement is traced as a span of the caller's trace.
+func Open(cfg Config) (*sqlx.DB, error) {
+	sslMode := "require"
+	if cfg.DisableTLS {
+		sslMode = "disable"
+	}
+
+	q := make(url.Values)
+	q.Set("sslmode", sslMode)
+	q.Set("timezone", "utc")
+	if cfg.Schema != "" {
+		q.Set("search_path", cfg.Schema)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     cfg.HostPort,
+		Path:     cfg.Name,
+		RawQuery: q.Encode(),
+	}
+
+	connConfig, err := pgx.ParseConfig(u.String())
+	if err != nil {
+		return nil, err
+	}
+	connConfig.Tracer = &queryTracer{log: cfg.Log}
+
+	db := sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx")
+
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+
+	return db, nil
+}
+
+// StatusCheck returns nil if it can successfully talk to the database. It
+// returns a non-nil error otherwise.
+func StatusCheck(ctx context.Context, db *sqlx.DB) error {
+	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.statuscheck")
+	defer span.End()
+
+	// If the user doesn't give us a deadline set 1 second.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second)
+		defer cancel()
+	}
+
+	// Keep pinging until the deadline, the database may still be starting.
+	ping := retry.Config{
+		Name:        "database ping",
+		MaxAttempts: -1,
+		MaxDelay:    time.Second,
+	}
+	if err := retry.Do(ctx, ping, db.PingContext); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	// Run a simple query to determine connectivity.
+	// Running this query forces a round trip through the database.
+	const q = `SELECT TRUE`
+	var tmp bool
+	if err := db.QueryRowContext(ctx, q).Scan(&tmp); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return nil
+}
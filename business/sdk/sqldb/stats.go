@@ -0,0 +1,55 @@
+package sqldb
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+	"github.com/jmoiron/sqlx"
+)
+
+// CollectStats publishes the connection pool statistics of the database on
+// every interval until the context is cancelled.
+func CollectStats(ctx context.Context, db *sqlx.DB, provider metrics.Provider, interval time.Duration) {
+	open := provider.Gauge("db_open_connections", "Established connections, in use and idle.", nil)
+	inUse := provider.Gauge("db_in_use_connections", "Connections currently in use.", nil)
+	idle := provider.Gauge("db_idle_connections", "Idle connections.", nil)
+	maxOpen := provider.Gauge("db_max_open_connections", "Maximum number of open connections.", nil)
+	waitCount := provider.Counter("db_wait_count_total", "Connections waited for.", nil)
+	waitDuration := provider.Counter("db_wait_duration_seconds_total", "Time blocked waiting for a connection.", nil)
+
+	// The pool reports cumulative totals, the counters are advanced by the
+	// difference since the last sample.
+	var last struct {
+		waitCount    int64
+		waitDuration time.Duration
+	}
+
+	sample := func() {
+		s := db.Stats()
+
+		open.Set(float64(s.OpenConnections))
+		inUse.Set(float64(s.InUse))
+		idle.Set(float64(s.Idle))
+		maxOpen.Set(float64(s.MaxOpenConnections))
+		waitCount.Add(float64(s.WaitCount - last.waitCount))
+		waitDuration.Add((s.WaitDuration - last.waitDuration).Seconds())
+
+		last.waitCount = s.WaitCount
+		last.waitDuration = s.WaitDuration
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sample()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sample()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const fingerprintKey ctxKey = 1
+
+// queryTracer implements pgx.QueryTracer so every statement sent to the
+// database gets its own span beneath the span of the caller.
+type queryTracer struct {
+	log *logger.Logger
+}
+
+// TraceQueryStart starts the span of the statement.
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	stmt := normalize(data.SQL)
+	fp := fingerprint(stmt)
+
+	ctx, _ = otel.AddSpan(ctx, "database.query",
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", stmt),
+		attribute.String("db.fingerprint", fp),
+	)
+
+	return context.WithValue(ctx, fingerprintKey, fp)
+}
+
+// TraceQueryEnd records the outcome of the statement and ends its span.
+// Failures are logged with the fingerprint so they can be matched with the
+// trace.
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+
+	if data.Err == nil {
+		return
+	}
+
+	span.RecordError(data.Err)
+	span.SetStatus(codes.Error, data.Err.Error())
+
+	if t.log == nil || errors.Is(data.Err, context.Canceled) {
+		return
+	}
+
+	fp, _ := ctx.Value(fingerprintKey).(string)
+
+	t.log.Error(ctx, "database query", "fingerprint", fp, "error", data.Err)
+}
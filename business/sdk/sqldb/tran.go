@@ -0,0 +1,150 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CommitRollbacker represents a value that can commit or rollback a
+// transaction.
+type CommitRollbacker interface {
+	Commit() error
+	Rollback() error
+}
+
+// Transactor runs a function within a transaction. Business calls made with
+// the context given to the function join the transaction.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// =============================================================================
+
+type txKey struct{}
+
+// Tx is a database transaction or, when it was begun while another one was
+// in progress, a savepoint within it. Rolling back a savepoint only undoes
+// the work done since it was set, the enclosing transaction stays usable.
+// A Tx isn't safe for concurrent use.
+type Tx struct {
+	*sqlx.Tx
+	savepoint string
+	seq       *int
+	done      bool
+}
+
+// BeginTx starts a transaction. When the context carries one, a savepoint is
+// set in it instead so the work joins the enclosing transaction.
+func BeginTx(ctx context.Context, db *sqlx.DB) (*Tx, error) {
+	if parent, ok := ctx.Value(txKey{}).(*Tx); ok {
+		*parent.seq++
+		savepoint := fmt.Sprintf("sp_%d", *parent.seq)
+
+		if _, err := parent.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+			return nil, fmt.Errorf("savepoint: %w", err)
+		}
+
+		tx := Tx{
+			Tx:        parent.Tx,
+			savepoint: savepoint,
+			seq:       parent.seq,
+		}
+
+		return &tx, nil
+	}
+
+	sqlxTx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+
+	tx := Tx{
+		Tx:  sqlxTx,
+		seq: new(int),
+	}
+
+	return &tx, nil
+}
+
+// Commit commits the transaction or releases the savepoint.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	if tx.savepoint == "" {
+		return tx.Tx.Commit()
+	}
+
+	_, err := tx.Exec("RELEASE SAVEPOINT " + tx.savepoint)
+	return err
+}
+
+// Rollback aborts the transaction or rolls back to the savepoint. Calling
+// it after Commit is a no-op so it can be deferred.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+
+	if tx.savepoint == "" {
+		if err := tx.Tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := tx.Exec("ROLLBACK TO SAVEPOINT " + tx.savepoint)
+	return err
+}
+
+// Executor returns the transaction carried by the context, or the database
+// when there is none. Stores use it so their statements join a transaction
+// begun by the business layer.
+func Executor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok {
+		return tx.Tx
+	}
+	return db
+}
+
+// =============================================================================
+
+// Tran begins the transactions business calls join through the context.
+type Tran struct {
+	db *sqlx.DB
+}
+
+// NewTran constructs a Tran for the database.
+func NewTran(db *sqlx.DB) *Tran {
+	return &Tran{
+		db: db,
+	}
+}
+
+// WithTx runs fn within a transaction that is committed when fn returns nil
+// and rolled back otherwise. Called within another transaction, fn runs in a
+// savepoint so only its own work is undone on failure.
+func (t *Tran) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := BeginTx(ctx, t.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
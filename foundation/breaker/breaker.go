@@ -0,0 +1,250 @@
+// Package breaker provides a circuit breaker to wrap calls to outbound
+// dependencies, so a failing dependency is given time to recover instead of
+// being hammered by every request.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/errs"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// ErrOpen is returned without calling the dependency while the breaker is
+// open, or half-open with every probe slot in use.
+var ErrOpen = errs.Newf(errs.Unavailable, "circuit breaker is open")
+
+// State represents the state of a breaker.
+type State int
+
+// Set of breaker states.
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Config contains the settings for a breaker.
+type Config struct {
+	Name             string
+	FailureThreshold int           // Consecutive failures that open the breaker, defaults to 5
+	OpenTimeout      time.Duration // Time spent open before probing, defaults to 30s
+	HalfOpenProbes   int           // Successful probes needed to close, defaults to 1
+	IsFailure        func(err error) bool
+	OnStateChange    func(name string, from State, to State) // Called with the breaker locked
+	Log              *logger.Logger                          // Optional, logs every state change
+}
+
+// Metrics is a snapshot of the breaker counters since it was constructed.
+type Metrics struct {
+	State        State
+	Requests     uint64
+	Successes    uint64
+	Failures     uint64
+	Rejections   uint64
+	StateChanges uint64
+}
+
+// Breaker tracks the health of a dependency and rejects calls while it's
+// considered down. A Breaker is safe for concurrent use.
+type Breaker struct {
+	cfg Config
+	now func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64 // Bumped on every state change
+	failures   int    // Consecutive failures while closed
+	successes  int    // Successful probes while half-open
+	probes     int    // Probes in flight while half-open
+	openedAt   time.Time
+	metrics    Metrics
+}
+
+// New constructs a breaker in the closed state.
+func New(cfg Config) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isFailure
+	}
+
+	return &Breaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Do calls fn if the breaker allows it and records the outcome. ErrOpen is
+// returned when the call is rejected.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	gen, err := b.allow(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ctx)
+	b.record(ctx, gen, err)
+
+	return err
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.currentState()
+}
+
+// Metrics returns a snapshot of the breaker counters.
+func (b *Breaker) Metrics() Metrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m := b.metrics
+	m.State = b.currentState()
+
+	return m
+}
+
+// =============================================================================
+
+// allow decides whether a call may go through, moving an open breaker to
+// half-open once the open timeout has passed. The generation the call was
+// allowed in is returned for record.
+func (b *Breaker) allow(ctx context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.metrics.Requests++
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.setState(ctx, StateHalfOpen)
+	}
+
+	switch b.state {
+	case StateOpen:
+		b.metrics.Rejections++
+		return 0, ErrOpen
+
+	case StateHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			b.metrics.Rejections++
+			return 0, ErrOpen
+		}
+		b.probes++
+	}
+
+	return b.generation, nil
+}
+
+// record applies the outcome of a call to the state of the breaker. A call
+// allowed before the last state change only counts in the metrics, so a slow
+// call can't release a probe slot it never took.
+func (b *Breaker) record(ctx context.Context, gen uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil && b.cfg.IsFailure(err)
+	if failed {
+		b.metrics.Failures++
+	} else {
+		b.metrics.Successes++
+	}
+
+	if gen != b.generation {
+		return
+	}
+
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.setState(ctx, StateOpen)
+		}
+
+	case StateHalfOpen:
+		b.probes--
+
+		if failed {
+			b.setState(ctx, StateOpen)
+			return
+		}
+
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenProbes {
+			b.setState(ctx, StateClosed)
+		}
+	}
+}
+
+// currentState reports the state as seen by a caller, an open breaker whose
+// timeout has passed reports half-open. The lock must be held.
+func (b *Breaker) currentState() State {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// setState moves the breaker to the new state and resets the counters of
+// the state being left. The lock must be held.
+func (b *Breaker) setState(ctx context.Context, to State) {
+	from := b.state
+	if from == to {
+		return
+	}
+
+	b.state = to
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.probes = 0
+	b.metrics.StateChanges++
+
+	if to == StateOpen {
+		b.openedAt = b.now()
+	}
+
+	if b.cfg.Log != nil {
+		b.cfg.Log.Warn(ctx, "circuit breaker", "name", b.cfg.Name, "from", from.String(), "to", to.String())
+	}
+
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(b.cfg.Name, from, to)
+	}
+}
+
+// isFailure is the default failure check. Cancellation by the caller says
+// nothing about the health of the dependency so it isn't counted.
+func isFailure(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,80 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/breaker"
+)
+
+func Test_LateResult(t *testing.T) {
+	t.Parallel()
+
+	b := breaker.New(breaker.Config{
+		Name:             "test",
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+		HalfOpenProbes:   1,
+	})
+
+	// block starts a call that waits for the returned channel to finish.
+	block := func() (chan<- struct{}, <-chan error) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error, 1)
+
+		go func() {
+			done <- b.Do(context.Background(), func(ctx context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+
+		<-started
+		return release, done
+	}
+
+	// A slow call is let through while the breaker is closed.
+	slowRelease, slowDone := block()
+
+	fail := func(ctx context.Context) error { return errors.New("down") }
+	if err := b.Do(context.Background(), fail); err == nil {
+		t.Fatal("should get the error of the call")
+	}
+
+	if s := b.State(); s != breaker.StateOpen {
+		t.Fatalf("should be open after the failure, got %s", s)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// The probe takes the only half-open slot.
+	probeRelease, probeDone := block()
+
+	// The slow call finishing must not count as the probe.
+	close(slowRelease)
+	if err := <-slowDone; err != nil {
+		t.Fatalf("should be able to finish the slow call: %s", err)
+	}
+
+	if s := b.State(); s != breaker.StateHalfOpen {
+		t.Fatalf("should still be half-open, got %s", s)
+	}
+
+	ok := func(ctx context.Context) error { return nil }
+	if err := b.Do(context.Background(), ok); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("should reject calls while the probe is in flight, got %v", err)
+	}
+
+	close(probeRelease)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("should be able to finish the probe: %s", err)
+	}
+
+	if s := b.State(); s != breaker.StateClosed {
+		t.Fatalf("should be closed after the probe, got %s", s)
+	}
+}
@@ -0,0 +1,135 @@
+// Package cache provides typed caching over a pluggable store, so business
+// code decides what to cache without depending on where it's kept. Loads
+// for the same key are collapsed so a miss under load hits the source once.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned when the key isn't in the cache or has expired.
+var ErrNotFound = errors.New("cache: key not found")
+
+// Store is the behavior a backend provides to hold encoded values. A ttl of
+// zero or less keeps the value until it's evicted or deleted.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config contains the settings for a cache.
+type Config struct {
+	Prefix string         // Added to every key so caches can share a store
+	TTL    time.Duration  // Lifetime of values set without an explicit ttl
+	Log    *logger.Logger // Optional, logs store failures that are tolerated
+}
+
+// Cache stores values of type T encoded as JSON. A Cache is safe for
+// concurrent use.
+type Cache[T any] struct {
+	store Store
+	cfg   Config
+	group singleflight.Group
+}
+
+// New constructs a cache for values of type T on top of the store.
+func New[T any](store Store, cfg Config) *Cache[T] {
+	return &Cache[T]{
+		store: store,
+		cfg:   cfg,
+	}
+}
+
+// Get returns the value for the key or ErrNotFound.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+
+	data, err := c.store.Get(ctx, c.key(key))
+	if err != nil {
+		return v, err
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("cache: decode %s: %w", key, err)
+	}
+
+	return v, nil
+}
+
+// Set stores the value for the key with the default TTL.
+func (c *Cache[T]) Set(ctx context.Context, key string, v T) error {
+	return c.SetTTL(ctx, key, v, c.cfg.TTL)
+}
+
+// SetTTL stores the value for the key with the given TTL.
+func (c *Cache[T]) SetTTL(ctx context.Context, key string, v T, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache: encode %s: %w", key, err)
+	}
+
+	return c.store.Set(ctx, c.key(key), data, ttl)
+}
+
+// Delete removes the key. Deleting a missing key isn't an error.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	return c.store.Delete(ctx, c.key(key))
+}
+
+// GetOrLoad returns the cached value for the key, calling load on a miss and
+// caching its result with the default TTL. Concurrent misses for the same
+// key share a single call to load. The cache is an optimization, so a store
+// that fails is logged and load is used instead.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	v, err := c.Get(ctx, key)
+	switch {
+	case err == nil:
+		return v, nil
+
+	case !errors.Is(err, ErrNotFound):
+		c.logFailure(ctx, "get", key, err)
+	}
+
+	res, err, _ := c.group.Do(key, func() (any, error) {
+		v, err := load(ctx)
+		if err != nil {
+			return v, err
+		}
+
+		if err := c.Set(ctx, key, v); err != nil {
+			c.logFailure(ctx, "set", key, err)
+		}
+
+		return v, nil
+	})
+
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	v, _ = res.(T)
+
+	return v, nil
+}
+
+// =============================================================================
+
+func (c *Cache[T]) key(key string) string {
+	return c.cfg.Prefix + key
+}
+
+func (c *Cache[T]) logFailure(ctx context.Context, op string, key string, err error) {
+	if c.cfg.Log == nil {
+		return
+	}
+	c.cfg.Log.Warn(ctx, "cache", "operation", op, "key", c.key(key), "error", err)
+}
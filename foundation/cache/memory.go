@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// MemoryConfig contains the settings for an in-memory store.
+type MemoryConfig struct {
+	MaxEntries int // Entries kept before the least recently used is evicted, defaults to 10000
+	Shards     int // Independently locked partitions, defaults to 16
+}
+
+// Memory is a Store that keeps values in process, split into shards that
+// each evict their least recently used entry when full.
+type Memory struct {
+	seed   maphash.Seed
+	shards []*shard
+	now    func() time.Time
+}
+
+// NewMemory constructs an in-memory store.
+func NewMemory(cfg MemoryConfig) *Memory {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10_000
+	}
+	if cfg.Shards <= 0 {
+		cfg.Shards = 16
+	}
+	cfg.Shards = min(cfg.Shards, cfg.MaxEntries)
+
+	m := Memory{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*shard, cfg.Shards),
+		now:    time.Now,
+	}
+
+	perShard := (cfg.MaxEntries + cfg.Shards - 1) / cfg.Shards
+	for i := range m.shards {
+		m.shards[i] = &shard{
+			max:     perShard,
+			order:   list.New(),
+			entries: make(map[string]*list.Element),
+		}
+	}
+
+	return &m
+}
+
+// Get implements the Store interface.
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	return m.shard(key).get(key, m.now())
+}
+
+// Set implements the Store interface.
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.now().Add(ttl)
+	}
+
+	m.shard(key).set(key, value, expires)
+
+	return nil
+}
+
+// Delete implements the Store interface.
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.shard(key).delete(key)
+	return nil
+}
+
+// Len returns the number of entries held, including expired entries that
+// haven't been evicted yet.
+func (m *Memory) Len() int {
+	var n int
+	for _, s := range m.shards {
+		s.mu.Lock()
+		n += s.order.Len()
+		s.mu.Unlock()
+	}
+	return n
+}
+
+func (m *Memory) shard(key string) *shard {
+	return m.shards[maphash.String(m.seed, key)%uint64(len(m.shards))]
+}
+
+// =============================================================================
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// shard is an LRU list with the most recently used entry at the front.
+type shard struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func (s *shard) get(key string, now time.Time) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, exists := s.entries[key]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	e := el.Value.(*entry)
+	if !e.expires.IsZero() && !now.Before(e.expires) {
+		s.remove(el)
+		return nil, ErrNotFound
+	}
+
+	s.order.MoveToFront(el)
+
+	return e.value, nil
+}
+
+func (s *shard) set(key string, value []byte, expires time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, exists := s.entries[key]; exists {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		s.order.MoveToFront(el)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	if s.order.Len() > s.max {
+		s.remove(s.order.Back())
+	}
+}
+
+func (s *shard) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, exists := s.entries[key]; exists {
+		s.remove(el)
+	}
+}
+
+// remove drops the element from the shard. The lock must be held.
+func (s *shard) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*entry).key)
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig contains the settings for a Redis store.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+// Redis is a Store backed by a Redis server, shared by every replica of the
+// service.
+type Redis struct {
+	client redis.UniversalClient
+}
+
+// NewRedis constructs a Redis store with its own client.
+func NewRedis(cfg RedisConfig) *Redis {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	return &Redis{
+		client: client,
+	}
+}
+
+// NewRedisWithClient constructs a Redis store on an existing client, such as
+// a cluster or sentinel client.
+func NewRedisWithClient(client redis.UniversalClient) *Redis {
+	return &Redis{
+		client: client,
+	}
+}
+
+// Get implements the Store interface.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	return data, nil
+}
+
+// Set implements the Store interface.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	return nil
+}
+
+// Delete implements the Store interface.
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("redis delete: %w", err)
+	}
+
+	return nil
+}
+
+// StatusCheck returns nil if it can successfully talk to the server.
+func (r *Redis) StatusCheck(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close releases the connections held by the client.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,115 @@
+// Package cgroup reads the CPU and memory limits the kernel enforces on the
+// current container, supporting both cgroup v1 and cgroup v2 hierarchies.
+package cgroup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// root is the mount point of the cgroup filesystem inside the container.
+const root = "/sys/fs/cgroup"
+
+// unlimitedV1 is the threshold above which a cgroup v1 memory limit is treated
+// as "no limit". The kernel reports a page-aligned math.MaxInt64 in that case.
+const unlimitedV1 = int64(1) << 62
+
+// CPUQuota returns the number of CPUs the container is allowed to use.
+// The boolean reports whether a quota is configured at all.
+func CPUQuota() (float64, bool, error) {
+	// cgroup v2: "<quota> <period>" or "max <period>".
+	if b, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 {
+			return 0, false, errors.New("cgroup: malformed cpu.max")
+		}
+
+		if fields[0] == "max" {
+			return 0, false, nil
+		}
+
+		return ratio(fields[0], fields[1])
+	}
+
+	// cgroup v1: separate quota and period files, quota of -1 means unlimited.
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false, err
+	}
+
+	q := strings.TrimSpace(string(quota))
+	if q == "-1" {
+		return 0, false, nil
+	}
+
+	return ratio(q, strings.TrimSpace(string(period)))
+}
+
+// MemoryLimit returns the memory limit in bytes enforced on the container.
+// The boolean reports whether a limit is configured at all.
+func MemoryLimit() (int64, bool, error) {
+	// cgroup v2: a byte count or "max".
+	if b, err := os.ReadFile(filepath.Join(root, "memory.max")); err == nil {
+		s := strings.TrimSpace(string(b))
+		if s == "max" {
+			return 0, false, nil
+		}
+
+		limit, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, false, err
+		}
+
+		return limit, true, nil
+	}
+
+	// cgroup v1: a huge value is reported when no limit is set.
+	b, err := os.ReadFile(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if limit >= unlimitedV1 {
+		return 0, false, nil
+	}
+
+	return limit, true, nil
+}
+
+// ratio converts a quota and period pair into a number of CPUs.
+func ratio(quota string, period string) (float64, bool, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, false, err
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if q <= 0 || p <= 0 {
+		return 0, false, nil
+	}
+
+	return q / p, true, nil
+}
@@ -0,0 +1,233 @@
+// Package docker provides support for starting and stopping docker containers
+// for running tests.
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// Container tracks information about the docker container started for tests.
+type Container struct {
+	Name     string
+	HostPort string
+	reused   bool
+}
+
+// Config describes the container to start.
+type Config struct {
+	Image string
+	Name  string   // Reused when a container with this name is already running
+	Port  string   // Container port to publish on a random host port, e.g. "5432"
+	Args  []string // Extra arguments placed before the image, e.g. "-e", "KEY=VALUE"
+	Cmd   []string // Optional command run by the image
+}
+
+// StartContainer starts the specified container for running tests. When a
+// container by that name is already running it's reused, which lets
+// packages share one database across test binaries.
+func StartContainer(cfg Config) (Container, error) {
+
+	// When this is running in parallel, there is a chance that the container
+	// with the specified name is already running.
+	if c, err := exists(cfg.Name, cfg.Port); err == nil {
+		c.reused = true
+		return c, nil
+	}
+
+	arg := []string{"run", "-P", "-d", "--name", cfg.Name}
+	arg = append(arg, cfg.Args...)
+	arg = append(arg, cfg.Image)
+	arg = append(arg, cfg.Cmd...)
+
+	var out bytes.Buffer
+	cmd := exec.Command("docker", arg...)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return Container{}, fmt.Errorf("could not start container %s: %w", cfg.Image, err)
+	}
+
+	c, err := exists(cfg.Name, cfg.Port)
+	if err != nil {
+		return Container{}, fmt.Errorf("could not extract ip/port: %w", err)
+	}
+
+	return c, nil
+}
+
+// StopContainer stops and removes the specified container.
+func StopContainer(name string) error {
+	if err := exec.Command("docker", "stop", name).Run(); err != nil {
+		return fmt.Errorf("could not stop container: %w", err)
+	}
+
+	if err := exec.Command("docker", "rm", name, "-v").Run(); err != nil {
+		return fmt.Errorf("could not remove container: %w", err)
+	}
+
+	return nil
+}
+
+// DumpContainerLogs returns the logs from the running container.
+func DumpContainerLogs(name string) []byte {
+	out, err := exec.Command("docker", "logs", name).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// WaitForPort blocks until the container accepts TCP connections on its
+// mapped port or the context is done.
+func WaitForPort(ctx context.Context, c Container) error {
+	var d net.Dialer
+	for attempt := 1; ; attempt++ {
+		conn, err := d.DialContext(ctx, "tcp", c.HostPort)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s at %s: %w", c.Name, c.HostPort, ctx.Err())
+		case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+		}
+	}
+}
+
+// StartForTest starts the container, waits up to the timeout for its port to
+// accept connections and stops it when the test and its subtests complete.
+// A container that was already running is left running for its owner.
+func StartForTest(t testing.TB, cfg Config, timeout time.Duration) Container {
+	t.Helper()
+
+	c, err := StartContainer(cfg)
+	if err != nil {
+		t.Fatalf("starting %s: %s", cfg.Image, err)
+	}
+
+	t.Cleanup(func() {
+		if c.reused {
+			return
+		}
+
+		if t.Failed() {
+			t.Logf("%s logs:\n%s", c.Name, DumpContainerLogs(c.Name))
+		}
+
+		if err := StopContainer(c.Name); err != nil {
+			t.Logf("stopping %s: %s", c.Name, err)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := WaitForPort(ctx, c); err != nil {
+		t.Fatalf("%s not ready: %s", c.Name, err)
+	}
+
+	return c
+}
+
+// =============================================================================
+
+// Predefined containers for the dependencies the service talks to.
+
+// Postgres returns the configuration for a Postgres container.
+func Postgres(name string) Config {
+	return Config{
+		Image: "postgres:17.2",
+		Name:  name,
+		Port:  "5432",
+		Args:  []string{"-e", "POSTGRES_PASSWORD=postgres"},
+	}
+}
+
+// Redis returns the configuration for a Redis container.
+func Redis(name string) Config {
+	return Config{
+		Image: "redis:7.4",
+		Name:  name,
+		Port:  "6379",
+	}
+}
+
+// NATS returns the configuration for a NATS container with JetStream.
+func NATS(name string) Config {
+	return Config{
+		Image: "nats:2.10",
+		Name:  name,
+		Port:  "4222",
+		Cmd:   []string{"-js"},
+	}
+}
+
+// =============================================================================
+
+func exists(name string, port string) (Container, error) {
+	out, err := exec.Command("docker", "inspect", name).CombinedOutput()
+	if err != nil {
+		return Container{}, fmt.Errorf("could not inspect container %s: %w", name, err)
+	}
+
+	hostIP, hostPort, err := extractIPPort(out, port)
+	if err != nil {
+		return Container{}, fmt.Errorf("could not extract ip/port: %w", err)
+	}
+
+	c := Container{
+		Name:     name,
+		HostPort: net.JoinHostPort(hostIP, hostPort),
+	}
+
+	return c, nil
+}
+
+func extractIPPort(doc []byte, port string) (hostIP string, hostPort string, err error) {
+	var containers []struct {
+		State struct {
+			Running bool
+		}
+		NetworkSettings struct {
+			Ports map[string][]struct {
+				HostIP   string `json:"HostIp"`
+				HostPort string `json:"HostPort"`
+			}
+		}
+	}
+
+	if err := json.Unmarshal(doc, &containers); err != nil {
+		return "", "", fmt.Errorf("could not decode json: %w", err)
+	}
+
+	if len(containers) == 0 || !containers[0].State.Running {
+		return "", "", errors.New("container is not running")
+	}
+
+	for _, data := range containers[0].NetworkSettings.Ports[port+"/tcp"] {
+		if data.HostIP != "::" {
+			hostIP = data.HostIP
+			hostPort = data.HostPort
+		}
+	}
+
+	if hostPort == "" {
+		return "", "", fmt.Errorf("no host port mapped for %s/tcp", port)
+	}
+
+	// Binding to all interfaces is reachable on localhost.
+	if hostIP == "" || hostIP == "0.0.0.0" {
+		hostIP = "localhost"
+	}
+
+	return hostIP, hostPort, nil
+}
@@ -0,0 +1,198 @@
+// Package encrypt provides authenticated encryption of single values with
+// versioned keys. Every value records the key it was encrypted with, so data
+// written before a rotation stays readable while it's re-encrypted with the
+// new key in the background.
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks a value as encrypted. Values without it are plaintext written
+// before encryption was enabled.
+const prefix = "enc:"
+
+// Set of errors returned by the keyring.
+var (
+	ErrKeyNotFound = errors.New("encryption key not found")
+	ErrMalformed   = errors.New("malformed encrypted value")
+)
+
+// Keyring holds the versioned keys. The active key encrypts new values and
+// every held key decrypts. A nil keyring leaves values in plaintext, which is
+// how encryption is disabled.
+type Keyring struct {
+	active   string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// New constructs a keyring from 32 byte AES-256 keys by id. The index key
+// derives the blind indexes used to look encrypted values up by equality; it
+// can't be rotated without rebuilding every index.
+func New(active string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, exists := keys[active]; !exists {
+		return nil, fmt.Errorf("active key %q: %w", active, ErrKeyNotFound)
+	}
+
+	if len(indexKey) < 32 {
+		return nil, errors.New("index key must be at least 32 bytes")
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key id %q: must be non empty without colons", id)
+		}
+
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q: must be 32 bytes, got %d", id, len(key))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		aeads[id] = aead
+	}
+
+	k := Keyring{
+		active:   active,
+		aeads:    aeads,
+		indexKey: indexKey,
+	}
+
+	return &k, nil
+}
+
+// ParseKeys parses keys in the form "id=base64;id=base64", the way they are
+// carried in configuration.
+func ParseKeys(s string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for pair := range strings.SplitSeq(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("key %q: expected id=base64", pair)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		keys[strings.TrimSpace(id)] = key
+	}
+
+	return keys, nil
+}
+
+// Active returns the id of the key used to encrypt new values.
+func (k *Keyring) Active() string {
+	if k == nil {
+		return ""
+	}
+	return k.active
+}
+
+// Encrypt encrypts the value with the active key. The associated data, like
+// the name of the column, binds the result to where it's stored so it can't
+// be moved to another column and decrypted there. Empty values stay empty.
+func (k *Keyring) Encrypt(value string, associated string) (string, error) {
+	if k == nil || value == "" {
+		return value, nil
+	}
+
+	aead := k.aeads[k.active]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(associated))
+
+	return prefix + k.active + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a value produced by Encrypt with the same
+// associated data. Values that aren't encrypted are returned as they are.
+func (k *Keyring) Decrypt(value string, associated string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+
+	if k == nil {
+		return "", fmt.Errorf("key %q: %w", id, ErrKeyNotFound)
+	}
+
+	aead, exists := k.aeads[id]
+	if !exists {
+		return "", fmt.Errorf("key %q: %w", id, ErrKeyNotFound)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(associated))
+	if err != nil {
+		return "", fmt.Errorf("key %q: open: %w", id, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Current reports whether the value is already in the form Encrypt would
+// produce now: encrypted with the active key, or empty. With a nil keyring
+// every value is current.
+func (k *Keyring) Current(value string) bool {
+	if k == nil || value == "" {
+		return true
+	}
+	return strings.HasPrefix(value, prefix+k.active+":")
+}
+
+// BlindIndex returns a keyed hash of the value that can be stored next to the
+// encrypted value and searched by equality. It's empty for a nil keyring.
+func (k *Keyring) BlindIndex(value string) string {
+	if k == nil {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether the value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
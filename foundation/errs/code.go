@@ -0,0 +1,88 @@
+package errs
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code represents the category of an error. The set follows the gRPC status
+// codes so errors translate cleanly to both HTTP and gRPC responses.
+type Code int
+
+// Set of error codes.
+const (
+	Internal Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+type codeInfo struct {
+	name string
+	http int
+	grpc codes.Code
+}
+
+// The zero value of Code is Internal, so an uninitialized code never leaks
+// details to a client.
+var codeInfos = map[Code]codeInfo{
+	Internal:           {"internal", http.StatusInternalServerError, codes.Internal},
+	Canceled:           {"canceled", 499, codes.Canceled},
+	Unknown:            {"unknown", http.StatusInternalServerError, codes.Unknown},
+	InvalidArgument:    {"invalid_argument", http.StatusBadRequest, codes.InvalidArgument},
+	DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	NotFound:           {"not_found", http.StatusNotFound, codes.NotFound},
+	AlreadyExists:      {"already_exists", http.StatusConflict, codes.AlreadyExists},
+	PermissionDenied:   {"permission_denied", http.StatusForbidden, codes.PermissionDenied},
+	ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests, codes.ResourceExhausted},
+	FailedPrecondition: {"failed_precondition", http.StatusConflict, codes.FailedPrecondition},
+	Aborted:            {"aborted", http.StatusConflict, codes.Aborted},
+	OutOfRange:         {"out_of_range", http.StatusBadRequest, codes.OutOfRange},
+	Unimplemented:      {"unimplemented", http.StatusNotImplemented, codes.Unimplemented},
+	Unavailable:        {"unavailable", http.StatusServiceUnavailable, codes.Unavailable},
+	DataLoss:           {"data_loss", http.StatusInternalServerError, codes.DataLoss},
+	Unauthenticated:    {"unauthenticated", http.StatusUnauthorized, codes.Unauthenticated},
+}
+
+// String returns the name of the code.
+func (c Code) String() string {
+	if info, ok := codeInfos[c]; ok {
+		return info.name
+	}
+	return codeInfos[Unknown].name
+}
+
+// Error implements the error interface so a code can be used as the target
+// of errors.Is, for example errors.Is(err, errs.NotFound).
+func (c Code) Error() string {
+	return c.String()
+}
+
+// HTTPStatus returns the HTTP status code used to respond with the code.
+func (c Code) HTTPStatus() int {
+	if info, ok := codeInfos[c]; ok {
+		return info.http
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC status code used to respond with the code.
+func (c Code) GRPCCode() codes.Code {
+	if info, ok := codeInfos[c]; ok {
+		return info.grpc
+	}
+	return codes.Unknown
+}
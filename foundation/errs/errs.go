@@ -0,0 +1,95 @@
+// Package errs provides coded errors that carry a category the transport
+// layers translate into HTTP and gRPC status codes. The business layer
+// declares its errors with a code so the app layer doesn't have to map each
+// one by hand.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error is an error with a code. Errors of this type are considered trusted
+// and, unless the code is Internal, their message is returned to the client.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  map[string]string
+	Err     error
+}
+
+// New wraps the error with the code. The message of the error becomes the
+// message returned to the client.
+func New(code Code, err error) *Error {
+	return &Error{
+		Code:    code,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+// Newf constructs an error with the code and formatted message. The result
+// can be used as a sentinel error and matched with errors.Is.
+func Newf(code Code, format string, args ...any) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// NewFields constructs an error with the code and message that names the
+// fields at fault. Fields maps a field to the reason it's at fault.
+func NewFields(code Code, message string, fields map[string]string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Fields:  fields,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the name of the code, so loggers can report it as a
+// field of its own.
+func (e *Error) ErrorCode() string {
+	return e.Code.String()
+}
+
+// Unwrap provides access to the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error has the code when the target is a Code.
+// Matching a sentinel *Error keeps the standard identity comparison.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && e.Code == code
+}
+
+// IsError checks if an error of type Error exists.
+func IsError(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
+
+// GetError returns the first Error in the error's chain.
+func GetError(err error) *Error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return e
+}
+
+// CodeOf returns the code of the first Error in the error's chain, or
+// Internal when the error doesn't carry a code.
+func CodeOf(err error) Code {
+	if e := GetError(err); e != nil {
+		return e.Code
+	}
+	return Internal
+}
@@ -0,0 +1,150 @@
+// Package health keeps the registry of dependency checks a service runs to
+// decide whether it can take traffic. The startup gate, the readiness
+// handler and the gRPC health service all read from the same registry, so
+// they agree on which dependencies matter.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Level says how much a failing check matters.
+type Level int
+
+// Set of check levels.
+const (
+	// Critical checks must pass for the service to be ready.
+	Critical Level = iota
+
+	// NonCritical checks are reported but a failure only degrades the
+	// service, it stays ready.
+	NonCritical
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	if l == NonCritical {
+		return "non-critical"
+	}
+	return "critical"
+}
+
+// CheckFunc verifies a single dependency.
+type CheckFunc func(ctx context.Context) error
+
+// Check describes a named dependency check.
+type Check struct {
+	Name    string
+	Level   Level
+	Timeout time.Duration // Per run timeout, defaults to 5s
+	Fn      CheckFunc
+}
+
+// Result is the outcome of running a single check.
+type Result struct {
+	Name     string
+	Level    Level
+	Duration time.Duration
+	Err      error
+}
+
+// Report holds the results of a run in registration order.
+type Report struct {
+	Results []Result
+}
+
+// Err returns the failures of the critical checks, or nil when they all
+// passed.
+func (r Report) Err() error {
+	var failed []error
+	for _, res := range r.Results {
+		if res.Err != nil && res.Level == Critical {
+			failed = append(failed, fmt.Errorf("%s: %w", res.Name, res.Err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// Degraded reports whether a non-critical check failed.
+func (r Report) Degraded() bool {
+	for _, res := range r.Results {
+		if res.Err != nil && res.Level == NonCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+
+// Registry holds the registered checks. A Registry is safe for concurrent
+// use.
+type Registry struct {
+	mu     sync.Mutex
+	checks []Check
+}
+
+// New constructs an empty registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check to the registry.
+func (r *Registry) Register(c Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+
+	r.checks = append(r.checks, c)
+}
+
+// Checks returns the registered checks in registration order.
+func (r *Registry) Checks() []Check {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.checks)
+}
+
+// Run executes every check in parallel, each with its own timeout.
+func (r *Registry) Run(ctx context.Context) Report {
+	checks := r.Checks()
+
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() {
+			checkCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.Fn(checkCtx)
+
+			results[i] = Result{
+				Name:     c.Name,
+				Level:    c.Level,
+				Duration: time.Since(start),
+				Err:      err,
+			}
+		})
+	}
+	wg.Wait()
+
+	return Report{
+		Results: results,
+	}
+}
+
+// Ready runs the checks and returns the failures of the critical ones.
+func (r *Registry) Ready(ctx context.Context) error {
+	return r.Run(ctx).Err()
+}
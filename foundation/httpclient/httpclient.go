@@ -0,0 +1,292 @@
+// Package httpclient provides an instrumented http.Client for calls to other
+// services. Every request is traced, logged and timed, idempotent requests
+// are retried and an optional circuit breaker guards the dependency.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/breaker"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/AlmirSai/service/foundation/ratelimit"
+	"github.com/AlmirSai/service/foundation/retry"
+	"github.com/AlmirSai/service/foundation/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Config contains the settings for a client.
+type Config struct {
+	Name      string            // Identifies the dependency in logs and spans
+	Log       *logger.Logger    // Optional, logs every request
+	Timeout   time.Duration     // Per attempt timeout, defaults to 10s
+	Retry     retry.Config      // Retries of idempotent requests, MaxAttempts defaults to 3
+	Breaker   *breaker.Breaker  // Optional, rejects calls while the dependency is down
+	Limiter   ratelimit.Limiter // Optional, throttles attempts to the dependency
+	Transport http.RoundTripper // Defaults to http.DefaultTransport
+	UserAgent string            // Set on requests without one, defaults to the binary version
+}
+
+// Metrics is a snapshot of the client counters since it was constructed.
+type Metrics struct {
+	Requests uint64        // Requests made by callers
+	Attempts uint64        // Round trips including retries
+	Errors   uint64        // Requests that ended with an error or a 5xx
+	Latency  time.Duration // Total time spent in requests
+}
+
+// AvgLatency returns the average time spent per request.
+func (m Metrics) AvgLatency() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.Latency / time.Duration(m.Requests)
+}
+
+// Client is an http.Client with an instrumented transport.
+type Client struct {
+	*http.Client
+	t *transport
+}
+
+// New constructs a client from the configuration.
+func New(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry.MaxAttempts = 3
+	}
+	if cfg.Retry.Name == "" {
+		cfg.Retry.Name = cfg.Name
+	}
+	if cfg.Retry.Log == nil {
+		cfg.Retry.Log = cfg.Log
+	}
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = version.UserAgent("service")
+	}
+
+	t := transport{
+		cfg: cfg,
+	}
+
+	c := Client{
+		Client: &http.Client{Transport: &t},
+		t:      &t,
+	}
+
+	return &c
+}
+
+// Metrics returns a snapshot of the client counters.
+func (c *Client) Metrics() Metrics {
+	return Metrics{
+		Requests: c.t.requests.Load(),
+		Attempts: c.t.attempts.Load(),
+		Errors:   c.t.errors.Load(),
+		Latency:  time.Duration(c.t.latency.Load()),
+	}
+}
+
+// =============================================================================
+
+// Set of errors used internally to steer the retries and the breaker.
+var (
+	errRetryableStatus = errors.New("retryable status")
+	errServerStatus    = errors.New("server error status")
+)
+
+type transport struct {
+	cfg Config
+
+	requests atomic.Uint64
+	attempts atomic.Uint64
+	errors   atomic.Uint64
+	latency  atomic.Int64
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.AddSpan(req.Context(), "http.client "+req.Method,
+		attribute.String("peer.service", t.cfg.Name),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+	)
+	defer span.End()
+
+	start := time.Now()
+	t.requests.Add(1)
+
+	cfg := t.cfg.Retry
+	if !replayable(req) {
+		cfg.MaxAttempts = 1
+	}
+	cfg.RetryIf = retryable
+
+	var attempts int
+	resp, err := retry.DoValue(ctx, cfg, func(ctx context.Context) (*http.Response, error) {
+		attempts++
+		t.attempts.Add(1)
+
+		resp, err := t.attempt(ctx, req, attempts)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempts < cfg.MaxAttempts && retryableStatus(resp.StatusCode) {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s", errRetryableStatus, resp.Status)
+		}
+
+		return resp, nil
+	})
+
+	elapsed := time.Since(start)
+	t.latency.Add(int64(elapsed))
+
+	if err != nil {
+		t.errors.Add(1)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		if t.cfg.Log != nil {
+			t.cfg.Log.Warn(ctx, "http client", "name", t.cfg.Name, "method", req.Method, "url", req.URL.Redacted(),
+				"attempts", attempts, "duration", elapsed.String(), "error", err)
+		}
+
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		t.errors.Add(1)
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if t.cfg.Log != nil {
+		t.cfg.Log.Info(ctx, "http client", "name", t.cfg.Name, "method", req.Method, "url", req.URL.Redacted(),
+			"status", resp.StatusCode, "attempts", attempts, "duration", elapsed.String())
+	}
+
+	return resp, nil
+}
+
+// attempt performs a single round trip with its own timeout. The timeout
+// stays in effect until the caller closes the response body.
+func (t *transport) attempt(ctx context.Context, req *http.Request, n int) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout)
+
+	r := req.Clone(ctx)
+	if n > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, retry.Permanent(fmt.Errorf("get body: %w", err))
+		}
+		r.Body = body
+	}
+
+	if r.Header.Get("User-Agent") == "" {
+		r.Header.Set("User-Agent", t.cfg.UserAgent)
+	}
+
+	if t.cfg.Limiter != nil {
+		if err := ratelimit.Wait(ctx, t.cfg.Limiter, t.cfg.Name); err != nil {
+			cancel()
+			return nil, retry.Permanent(fmt.Errorf("rate limit: %w", err))
+		}
+	}
+
+	otel.AddTraceToRequest(ctx, r)
+
+	var resp *http.Response
+	call := func(ctx context.Context) error {
+		var err error
+		resp, err = t.cfg.Transport.RoundTrip(r)
+		if err != nil {
+			return err
+		}
+
+		// Report server errors so the breaker counts them as failures.
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return errServerStatus
+		}
+		return nil
+	}
+
+	var err error
+	switch t.cfg.Breaker {
+	case nil:
+		err = call(ctx)
+	default:
+		err = t.cfg.Breaker.Do(ctx, call)
+		if errors.Is(err, breaker.ErrOpen) {
+			err = retry.Permanent(err)
+		}
+	}
+
+	if errors.Is(err, errServerStatus) {
+		err = nil
+	}
+
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// =============================================================================
+
+// cancelBody releases the attempt's context once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// replayable reports whether the request can be safely sent more than once.
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryable reports whether a failed attempt is worth repeating.
+func retryable(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
+
+// retryableStatus reports the statuses that are retried for idempotent
+// requests.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
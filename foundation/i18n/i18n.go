@@ -0,0 +1,275 @@
+// Package i18n resolves user facing messages from per language catalogs.
+// The language is negotiated from an Accept-Language header and falls back
+// from a regional tag to its base language and then to the default
+// language of the bundle. Messages may have plural forms.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Args are the named values substituted into a message, where "{name}" in
+// the message is replaced by the value of name.
+type Args map[string]any
+
+// Message is a catalog entry. Other is the only required form; the rest are
+// used by languages whose plural rules select them.
+type Message struct {
+	Zero  string `json:"zero,omitempty"`
+	One   string `json:"one,omitempty"`
+	Two   string `json:"two,omitempty"`
+	Few   string `json:"few,omitempty"`
+	Many  string `json:"many,omitempty"`
+	Other string `json:"other"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. A message
+// without plural forms can be given as a plain string.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = Message{Other: s}
+		return nil
+	}
+
+	type message Message
+	return json.Unmarshal(data, (*message)(m))
+}
+
+// form returns the text for the plural category, or Other when the message
+// doesn't have that form.
+func (m Message) form(c Category) string {
+	var s string
+	switch c {
+	case Zero:
+		s = m.Zero
+	case One:
+		s = m.One
+	case Two:
+		s = m.Two
+	case Few:
+		s = m.Few
+	case Many:
+		s = m.Many
+	}
+
+	if s == "" {
+		return m.Other
+	}
+	return s
+}
+
+// =============================================================================
+
+// Bundle holds the catalogs of every language. It's safe for concurrent use.
+type Bundle struct {
+	fallback string
+	mu       sync.RWMutex
+	catalogs map[string]map[string]Message
+}
+
+// New constructs a bundle that falls back to the language, like "en".
+func New(fallback string) *Bundle {
+	return &Bundle{
+		fallback: normalize(fallback),
+		catalogs: make(map[string]map[string]Message),
+	}
+}
+
+// Add merges the messages into the catalog of the language.
+func (b *Bundle) Add(lang string, messages map[string]Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lang = normalize(lang)
+
+	catalog, exists := b.catalogs[lang]
+	if !exists {
+		catalog = make(map[string]Message, len(messages))
+		b.catalogs[lang] = catalog
+	}
+
+	for key, msg := range messages {
+		catalog[key] = msg
+	}
+}
+
+// LoadFS adds every JSON file in the directory of the file system as the
+// catalog of the language it's named after, like "pt-BR.json".
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("glob: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", file, err)
+		}
+
+		var messages map[string]Message
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("decode %s: %w", file, err)
+		}
+
+		b.Add(strings.TrimSuffix(path.Base(file), ".json"), messages)
+	}
+
+	return nil
+}
+
+// Localizer returns a localizer for the languages of the Accept-Language
+// header values, in order of preference.
+func (b *Bundle) Localizer(acceptLanguage ...string) *Localizer {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var langs []string
+	seen := make(map[string]bool)
+
+	add := func(lang string) {
+		if _, exists := b.catalogs[lang]; exists && !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+
+	for _, header := range acceptLanguage {
+		for _, tag := range ParseAcceptLanguage(header) {
+			add(tag)
+			if base, _, ok := strings.Cut(tag, "-"); ok {
+				add(base)
+			}
+		}
+	}
+	add(b.fallback)
+
+	return &Localizer{
+		bundle: b,
+		langs:  langs,
+	}
+}
+
+// lookup finds the message in the catalog of the language.
+func (b *Bundle) lookup(lang string, key string) (Message, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	msg, exists := b.catalogs[lang][key]
+	return msg, exists
+}
+
+// =============================================================================
+
+// Localizer resolves messages for a negotiated chain of languages. A nil
+// Localizer resolves nothing, so callers can use what's in the context
+// without checking.
+type Localizer struct {
+	bundle *Bundle
+	langs  []string
+}
+
+// Language returns the most preferred language that has a catalog, or an
+// empty string when none has.
+func (l *Localizer) Language() string {
+	if l == nil || len(l.langs) == 0 {
+		return ""
+	}
+	return l.langs[0]
+}
+
+// Translate returns the message for the key in the first language of the
+// chain that has it.
+func (l *Localizer) Translate(key string, args Args) (string, bool) {
+	return l.translate(key, 0, false, args)
+}
+
+// TranslatePlural is Translate for a message with plural forms. The form is
+// chosen by the count, which is also available to the message as {count}.
+func (l *Localizer) TranslatePlural(key string, count int, args Args) (string, bool) {
+	withCount := make(Args, len(args)+1)
+	for k, v := range args {
+		withCount[k] = v
+	}
+	withCount["count"] = count
+
+	return l.translate(key, count, true, withCount)
+}
+
+// T returns the message for the key, or the key itself when no language of
+// the chain has it.
+func (l *Localizer) T(key string, args Args) string {
+	if s, ok := l.Translate(key, args); ok {
+		return s
+	}
+	return key
+}
+
+// Plural returns the message for the key and count, or the key itself when
+// no language of the chain has it.
+func (l *Localizer) Plural(key string, count int, args Args) string {
+	if s, ok := l.TranslatePlural(key, count, args); ok {
+		return s
+	}
+	return key
+}
+
+func (l *Localizer) translate(key string, count int, plural bool, args Args) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+
+	for _, lang := range l.langs {
+		msg, exists := l.bundle.lookup(lang, key)
+		if !exists {
+			continue
+		}
+
+		c := Other
+		if plural {
+			c = PluralCategory(lang, count)
+		}
+
+		return format(msg.form(c), args), true
+	}
+
+	return "", false
+}
+
+// format replaces the "{name}" placeholders with the arguments.
+func format(text string, args Args) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	pairs := make([]string, 0, len(args)*2)
+	for k, v := range args {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const localizerKey ctxKey = 1
+
+// WithLocalizer stores the localizer in the context.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey, l)
+}
+
+// FromContext returns the localizer stored in the context, or nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey).(*Localizer)
+	return l
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import "strings"
+
+// Category is a CLDR plural category.
+type Category int
+
+// Set of plural categories.
+const (
+	Other Category = iota
+	Zero
+	One
+	Two
+	Few
+	Many
+)
+
+// PluralCategory returns the category of the count in the language. The
+// rules cover integer counts for the languages the service ships catalogs
+// for and their neighbours; unknown languages use the English rule.
+func PluralCategory(lang string, n int) Category {
+	base, _, _ := strings.Cut(normalize(lang), "-")
+
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch base {
+	case "ja", "ko", "zh", "th", "vi", "id", "ms":
+		return Other
+
+	case "fr", "hy":
+		if n == 0 || n == 1 {
+			return One
+		}
+		return Other
+
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return One
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return Few
+		}
+		return Many
+
+	case "pl":
+		switch {
+		case n == 1:
+			return One
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return Few
+		}
+		return Many
+
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return One
+		case n >= 2 && n <= 4:
+			return Few
+		}
+		return Other
+
+	case "ar":
+		switch {
+		case n == 0:
+			return Zero
+		case n == 1:
+			return One
+		case n == 2:
+			return Two
+		case mod100 >= 3 && mod100 <= 10:
+			return Few
+		case mod100 >= 11:
+			return Many
+		}
+		return Other
+	}
+
+	if n == 1 {
+		return One
+	}
+	return Other
+}
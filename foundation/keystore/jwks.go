@@ -0,0 +1,194 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// JWK is the JSON Web Key representation of an RSA or EC public key as
+// described in RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set holding every key valid for verification.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK returns the signing JWK of an RSA or ECDSA public key.
+func NewJWK(kid string, pub crypto.PublicKey) (JWK, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: "RS256",
+			KeyID:     kid,
+			Modulus:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+
+	case *ecdsa.PublicKey:
+		alg, crv, err := curveNames(pub.Curve)
+		if err != nil {
+			return JWK{}, err
+		}
+
+		// The uncompressed point is 0x04 followed by X and Y padded to the
+		// size of the curve, which is the encoding RFC 7518 asks for.
+		point, err := pub.Bytes()
+		if err != nil {
+			return JWK{}, fmt.Errorf("encoding point: %w", err)
+		}
+		size := (len(point) - 1) / 2
+
+		return JWK{
+			KeyType:   "EC",
+			Use:       "sig",
+			Algorithm: alg,
+			KeyID:     kid,
+			Curve:     crv,
+			X:         base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			Y:         base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}, nil
+	}
+
+	return JWK{}, fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// PublicKey returns the RSA or ECDSA public key the JWK describes, so keys
+// published by another service's key set can verify its tokens.
+func (jwk JWK) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+		if err != nil {
+			return nil, fmt.Errorf("decoding modulus: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+		if err != nil {
+			return nil, fmt.Errorf("decoding exponent: %w", err)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Curve)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding x: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decoding y: %w", err)
+		}
+
+		point := append([]byte{4}, append(x, y...)...)
+
+		pub, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+		if err != nil {
+			return nil, fmt.Errorf("parsing point: %w", err)
+		}
+
+		return pub, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.KeyType)
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key, a stable identifier
+// derived from the public key alone that makes a good kid.
+func (jwk JWK) Thumbprint() string {
+	// The members required for the key type, in lexicographic order and
+	// without whitespace.
+	var canonical string
+	switch jwk.KeyType {
+	case "EC":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, jwk.Curve, jwk.X, jwk.Y)
+	default:
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.Exponent, jwk.Modulus)
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKS returns the public keys of the store as a key set, with the active key
+// listed first so validators that only read the first key still work.
+func (ks *KeyStore) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := JWKS{
+		Keys: make([]JWK, 0, len(ks.store)),
+	}
+
+	for kid, k := range ks.store {
+		jwk, err := NewJWK(kid, &k.private.PublicKey)
+		if err != nil {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int {
+		switch {
+		case a.KeyID == ks.active:
+			return -1
+		case b.KeyID == ks.active:
+			return 1
+		case a.KeyID < b.KeyID:
+			return -1
+		case a.KeyID > b.KeyID:
+			return 1
+		}
+		return 0
+	})
+
+	return jwks
+}
+
+// curveNames returns the JWS algorithm and the JWK curve name of a curve.
+func curveNames(curve elliptic.Curve) (string, string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "ES256", "P-256", nil
+	case elliptic.P384():
+		return "ES384", "P-384", nil
+	case elliptic.P521():
+		return "ES512", "P-521", nil
+	}
+	return "", "", fmt.Errorf("unsupported curve %s", curve.Params().Name)
+}
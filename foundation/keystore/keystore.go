@@ -0,0 +1,284 @@
+// Package keystore implements an in-memory store of RSA keys for signing and
+// verifying JWTs whose keys can be rotated while the service is running.
+package keystore
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned when the kid isn't held by the store.
+var ErrKeyNotFound = errors.New("kid lookup failed")
+
+// key represents key information for a kid.
+type key struct {
+	privatePEM string
+	publicPEM  string
+	private    *rsa.PrivateKey
+	modTime    time.Time
+}
+
+// KeyStore represents an in memory store of keys by kid. One key is active
+// and used for signing; every held key remains valid for verification, so tokens signed
+// before a rotation keep working until the old key is retired.
+type KeyStore struct {
+	mu     sync.RWMutex
+	store  map[string]key
+	active string
+}
+
+// New constructs an empty KeyStore ready for use.
+func New() *KeyStore {
+	return &KeyStore{
+		store: make(map[string]key),
+	}
+}
+
+// LoadKeys loads every private key file with a .pem extension from the file
+// system, replacing the keys held. The name of the file without the
+// extension becomes the kid and the most recently modified key becomes
+// active, so dropping a new key file in and reloading rotates to it. Call
+// Rotate afterwards to pin a different key.
+func (ks *KeyStore) LoadKeys(fsys fs.FS) (int, error) {
+	store := make(map[string]key)
+
+	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walkdir failure: %w", err)
+		}
+
+		if dirEntry.IsDir() || path.Ext(fileName) != ".pem" {
+			return nil
+		}
+
+		file, err := fsys.Open(fileName)
+		if err != nil {
+			return fmt.Errorf("opening key file: %w", err)
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("stat key file: %w", err)
+		}
+
+		// limit PEM file size to 1 megabyte. This should be reasonable for
+		// almost any PEM file and prevents shenanigans like linking the file
+		// to /dev/random or something like that.
+		privatePEM, err := io.ReadAll(io.LimitReader(file, 1024*1024))
+		if err != nil {
+			return fmt.Errorf("reading auth private key: %w", err)
+		}
+
+		k, err := parseKey(string(privatePEM))
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", fileName, err)
+		}
+		k.modTime = info.ModTime()
+
+		store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = k
+
+		return nil
+	}
+
+	if err := fs.WalkDir(fsys, ".", fn); err != nil {
+		return 0, fmt.Errorf("walking directory: %w", err)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.store = store
+	ks.active = newest(store)
+
+	return len(store), nil
+}
+
+// Add adds a private key in PEM format under the kid, replacing any key
+// already held for it. The first key added becomes active.
+func (ks *KeyStore) Add(kid string, privatePEM string) error {
+	k, err := parseKey(privatePEM)
+	if err != nil {
+		return err
+	}
+	k.modTime = time.Now()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.store[kid] = k
+	if ks.active == "" {
+		ks.active = kid
+	}
+
+	return nil
+}
+
+// Rotate makes the specified kid the key used for signing. The previous key
+// stays available for verification until it's retired.
+func (ks *KeyStore) Rotate(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, exists := ks.store[kid]; !exists {
+		return fmt.Errorf("rotate kid[%s]: %w", kid, ErrKeyNotFound)
+	}
+
+	ks.active = kid
+
+	return nil
+}
+
+// Retire removes a key so tokens signed with it no longer verify. The active
+// key can't be retired.
+func (ks *KeyStore) Retire(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if kid == ks.active {
+		return fmt.Errorf("retire kid[%s]: key is active", kid)
+	}
+
+	if _, exists := ks.store[kid]; !exists {
+		return fmt.Errorf("retire kid[%s]: %w", kid, ErrKeyNotFound)
+	}
+
+	delete(ks.store, kid)
+
+	return nil
+}
+
+// ActiveKID returns the kid of the key used for signing, or an empty string
+// when no keys are held.
+func (ks *KeyStore) ActiveKID() string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.active
+}
+
+// KIDs returns the kids of every key held, which are all valid for
+// verification.
+func (ks *KeyStore) KIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		kids = append(kids, kid)
+	}
+
+	return kids
+}
+
+// PrivateKey searches the key store for a given kid and returns the private
+// key in PEM format.
+func (ks *KeyStore) PrivateKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, found := ks.store[kid]
+	if !found {
+		return "", ErrKeyNotFound
+	}
+
+	return k.privatePEM, nil
+}
+
+// PublicKey searches the key store for a given kid and returns the public
+// key in PEM format.
+func (ks *KeyStore) PublicKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, found := ks.store[kid]
+	if !found {
+		return "", ErrKeyNotFound
+	}
+
+	return k.publicPEM, nil
+}
+
+// =============================================================================
+
+// parseKey decodes a PKCS#1 or PKCS#8 RSA private key and derives the public
+// key in PEM format.
+func parseKey(privatePEM string) (key, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return key{}, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	var privateKey *rsa.PrivateKey
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		pk, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return key{}, fmt.Errorf("parsing auth private key: %w", err)
+		}
+		privateKey = pk
+
+	case "PRIVATE KEY":
+		pk, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return key{}, fmt.Errorf("parsing auth private key: %w", err)
+		}
+
+		rsaKey, ok := pk.(*rsa.PrivateKey)
+		if !ok {
+			return key{}, errors.New("invalid key: only RSA keys are supported")
+		}
+		privateKey = rsaKey
+
+	default:
+		return key{}, fmt.Errorf("invalid key: unsupported PEM type %q", block.Type)
+	}
+
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return key{}, fmt.Errorf("marshaling public key: %w", err)
+	}
+
+	publicBlock := pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: asn1Bytes,
+	}
+
+	var b strings.Builder
+	if err := pem.Encode(&b, &publicBlock); err != nil {
+		return key{}, fmt.Errorf("encoding to public PEM: %w", err)
+	}
+
+	k := key{
+		privatePEM: privatePEM,
+		publicPEM:  b.String(),
+		private:    privateKey,
+	}
+
+	return k, nil
+}
+
+// newest returns the kid of the most recently modified key.
+func newest(store map[string]key) string {
+	var kid string
+	var mt time.Time
+
+	for k, v := range store {
+		if kid == "" || v.modTime.After(mt) || (v.modTime.Equal(mt) && k > kid) {
+			kid = k
+			mt = v.modTime
+		}
+	}
+
+	return kid
+}
@@ -0,0 +1,125 @@
+// Package locker provides distributed locks so work that must only happen on
+// one replica at a time, like singleton background jobs, can be coordinated
+// through a shared store. A held lock is a lease that's renewed in the
+// background; when renewal fails the lease's context is cancelled so the
+// work stops before another replica takes over.
+package locker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// ErrNotAcquired is returned when the lock is held by another owner.
+var ErrNotAcquired = errors.New("lock is held by another owner")
+
+// Locker is the behavior a lock store provides.
+type Locker interface {
+	// TryAcquire takes the lock without waiting, returning ErrNotAcquired
+	// when it's held elsewhere. The lease also ends when ctx is done.
+	TryAcquire(ctx context.Context, key string) (*Lease, error)
+}
+
+// Do runs fn while holding the lock. The context passed to fn is cancelled
+// if the lease is lost. ErrNotAcquired is returned without calling fn when
+// the lock is held elsewhere.
+func Do(ctx context.Context, l Locker, key string, fn func(ctx context.Context) error) error {
+	lease, err := l.TryAcquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer lease.Release(context.WithoutCancel(ctx))
+
+	return fn(lease.Context())
+}
+
+// Singleton wraps fn so that, across every replica sharing the locker, only
+// one runs it at a time. Replicas that don't get the lock skip the run
+// without an error, which suits jobs started from the scheduler.
+func Singleton(l Locker, key string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := Do(ctx, l, key, fn)
+		if errors.Is(err, ErrNotAcquired) {
+			return nil
+		}
+		return err
+	}
+}
+
+// =============================================================================
+
+// Lease is a held lock. It must be released once the work is done.
+type Lease struct {
+	key     string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	release func(ctx context.Context) error
+	done    chan struct{}
+	once    sync.Once
+	err     error
+}
+
+// newLease starts renewing the lease every interval until it's released or
+// a renewal fails.
+func newLease(ctx context.Context, log *logger.Logger, key string, interval time.Duration, renew func(ctx context.Context) error, release func(ctx context.Context) error) *Lease {
+	ctx, cancel := context.WithCancel(ctx)
+
+	l := Lease{
+		key:     key,
+		ctx:     ctx,
+		cancel:  cancel,
+		release: release,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := renew(ctx); err != nil {
+					if ctx.Err() == nil && log != nil {
+						log.Warn(ctx, "locker", "status", "lease lost", "key", key, "error", err)
+					}
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return &l
+}
+
+// Key returns the key of the lock.
+func (l *Lease) Key() string {
+	return l.key
+}
+
+// Context returns a context that's cancelled when the lease is lost or
+// released.
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Release stops renewing the lease and gives up the lock. It's safe to call
+// more than once.
+func (l *Lease) Release(ctx context.Context) error {
+	l.once.Do(func() {
+		l.cancel()
+		<-l.done
+		l.err = l.release(ctx)
+	})
+
+	return l.err
+}
@@ -0,0 +1,80 @@
+package locker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// PostgresConfig contains the settings for a Postgres locker.
+type PostgresConfig struct {
+	Log           *logger.Logger
+	CheckInterval time.Duration // How often the session holding a lock is checked, defaults to 10s
+}
+
+// Postgres is a Locker on session level advisory locks. Each lease holds a
+// dedicated connection, since the lock lives as long as the session; the
+// lease is renewed by checking the connection is still alive.
+type Postgres struct {
+	db  *sql.DB
+	cfg PostgresConfig
+}
+
+// NewPostgres constructs a Postgres locker.
+func NewPostgres(db *sql.DB, cfg PostgresConfig) *Postgres {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 10 * time.Second
+	}
+
+	return &Postgres{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// TryAcquire implements the Locker interface.
+func (p *Postgres) TryAcquire(ctx context.Context, key string) (*Lease, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	lockID := advisoryKey(key)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, lockID).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("try advisory lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, ErrNotAcquired
+	}
+
+	renew := func(ctx context.Context) error {
+		return conn.PingContext(ctx)
+	}
+
+	release := func(ctx context.Context) error {
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+			return fmt.Errorf("advisory unlock: %w", err)
+		}
+		return nil
+	}
+
+	return newLease(ctx, p.cfg.Log, key, p.cfg.CheckInterval, renew, release), nil
+}
+
+// advisoryKey maps the lock name onto the bigint space of advisory locks.
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("locker:" + key))
+	return int64(h.Sum64())
+}
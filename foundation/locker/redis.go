@@ -0,0 +1,96 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig contains the settings for a Redis locker.
+type RedisConfig struct {
+	Log    *logger.Logger
+	Prefix string        // Added to every key, defaults to lock:
+	TTL    time.Duration // Lease length, renewed every third of it, defaults to 30s
+}
+
+// Redis is a Locker on keys holding a random token with an expiry. Only the
+// owner of the token can renew or release the lock, and a crashed owner's
+// lock expires after the TTL.
+type Redis struct {
+	client redis.UniversalClient
+	cfg    RedisConfig
+}
+
+// NewRedis constructs a Redis locker.
+func NewRedis(client redis.UniversalClient, cfg RedisConfig) *Redis {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "lock:"
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Second
+	}
+
+	return &Redis{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Scripts that only touch the key while it still holds the owner's token.
+var (
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// errLeaseExpired is returned by a renewal after the key expired or was
+// taken by another owner.
+var errLeaseExpired = errors.New("lease expired")
+
+// TryAcquire implements the Locker interface.
+func (r *Redis) TryAcquire(ctx context.Context, key string) (*Lease, error) {
+	k := r.cfg.Prefix + key
+	token := id.New().String()
+
+	ok, err := r.client.SetNX(ctx, k, token, r.cfg.TTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("setnx: %w", err)
+	}
+
+	if !ok {
+		return nil, ErrNotAcquired
+	}
+
+	renew := func(ctx context.Context) error {
+		n, err := renewScript.Run(ctx, r.client, []string{k}, token, r.cfg.TTL.Milliseconds()).Int()
+		if err != nil {
+			return fmt.Errorf("renew: %w", err)
+		}
+		if n == 0 {
+			return errLeaseExpired
+		}
+		return nil
+	}
+
+	release := func(ctx context.Context) error {
+		if err := releaseScript.Run(ctx, r.client, []string{k}, token).Err(); err != nil {
+			return fmt.Errorf("release: %w", err)
+		}
+		return nil
+	}
+
+	return newLease(ctx, r.cfg.Log, key, r.cfg.TTL/3, renew, release), nil
+}
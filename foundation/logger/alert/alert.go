@@ -0,0 +1,243 @@
+// Package alert provides a logger event hook that posts error records to a
+// Slack compatible webhook, so alerting doesn't need a log pipeline.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Config holds the options for constructing a Webhook.
+type Config struct {
+	URL      string        // Webhook receiving the messages
+	Service  string        // Named in every message
+	Interval time.Duration // Minimum time between messages, defaults to 30s
+	MaxBatch int           // Records listed per message, defaults to 20
+	Client   *http.Client  // Defaults to a client with a 5s timeout
+}
+
+// Message is the body posted to the webhook.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// DeliverFunc hands a message over for delivery, for example to a durable
+// queue that calls Post until the webhook takes it.
+type DeliverFunc func(ctx context.Context, msg Message) error
+
+// StatusError is returned by Post when the webhook answers with a status
+// other than 2xx.
+type StatusError struct {
+	StatusCode int
+}
+
+func (se *StatusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", se.StatusCode)
+}
+
+// Temporary reports whether posting the message again may succeed.
+func (se *StatusError) Temporary() bool {
+	return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+}
+
+// Webhook collects records and posts them as one message. At most one
+// message is sent per interval, and records beyond MaxBatch are only counted
+// in the message that follows.
+type Webhook struct {
+	cfg     Config
+	deliver atomic.Pointer[DeliverFunc]
+
+	mu      sync.Mutex
+	pending []logger.Record
+	skipped int
+
+	wake chan struct{}
+	done chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// New constructs a Webhook and starts the goroutine posting the messages.
+// Call Shutdown to post what's still pending and stop it.
+func New(cfg Config) *Webhook {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = 20
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	w := Webhook{
+		cfg:  cfg,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+
+	go w.run()
+
+	return &w
+}
+
+// Event queues the record. It matches logger.EventFn so it can be used as
+// the Error hook of logger.Events, and it never blocks the caller.
+func (w *Webhook) Event(ctx context.Context, r logger.Record) {
+	w.mu.Lock()
+	if len(w.pending) < w.cfg.MaxBatch {
+		w.pending = append(w.pending, r)
+	} else {
+		w.skipped++
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SetDeliver hands later messages to fn instead of posting them, so they
+// survive a webhook that is down. A message fn fails to take is posted
+// directly like before.
+func (w *Webhook) SetDeliver(fn DeliverFunc) {
+	w.deliver.Store(&fn)
+}
+
+// Post sends the message to the webhook.
+func (w *Webhook) Post(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting message: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// Shutdown posts the pending records and stops the webhook. It returns when
+// done or when the context is canceled.
+func (w *Webhook) Shutdown(ctx context.Context) error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run posts a message whenever records are pending, waiting out the rest of
+// the interval after every message.
+func (w *Webhook) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case <-w.wake:
+		case <-w.stop:
+			w.post()
+			return
+		}
+
+		w.post()
+
+		select {
+		case <-time.After(w.cfg.Interval):
+		case <-w.stop:
+			w.post()
+			return
+		}
+	}
+}
+
+// post sends the pending records as one message. Failures are not logged,
+// logging them could feed the hook that called us.
+func (w *Webhook) post() {
+	w.mu.Lock()
+	records, skipped := w.pending, w.skipped
+	w.pending, w.skipped = nil, 0
+	w.mu.Unlock()
+
+	if len(records) == 0 {
+		return
+	}
+
+	msg := Message{
+		Text: w.message(records, skipped),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Client.Timeout+time.Second)
+	defer cancel()
+
+	if fn := w.deliver.Load(); fn != nil {
+		if err := (*fn)(ctx, msg); err == nil {
+			return
+		}
+	}
+
+	w.Post(ctx, msg)
+}
+
+// message renders the records as Slack mrkdwn, one line per record.
+func (w *Webhook) message(records []logger.Record, skipped int) string {
+	var b strings.Builder
+
+	total := len(records) + skipped
+	noun := "errors"
+	if total == 1 {
+		noun = "error"
+	}
+	fmt.Fprintf(&b, "*[%s] %d %s*\n", w.cfg.Service, total, noun)
+
+	for _, r := range records {
+		fmt.Fprintf(&b, "• `%s` %s %s", r.Time.UTC().Format(time.TimeOnly), r.Level, r.Message)
+
+		keys := make([]string, 0, len(r.Attributes))
+		for k := range r.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, r.Attributes[k])
+		}
+		b.WriteByte('\n')
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(&b, "…and %d more\n", skipped)
+	}
+
+	return b.String()
+}
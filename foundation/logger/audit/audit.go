@@ -0,0 +1,256 @@
+// Package audit provides a slog.Handler writing audit records to a dedicated
+// stream in which every record carries the hash of the one before it. Editing,
+// removing, or reordering records breaks the chain, which Verify detects.
+// Records cut from the end only show when compared with a Link kept
+// elsewhere. It is meant to be added to the logger with logger.WithSink.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// hashField closes every line, the hash covers everything before it.
+const hashField = `,"hash":"`
+
+// Handler is a slog.Handler writing the audit level records as hash chained
+// JSON lines. Records of other levels are ignored. Records are written
+// synchronously, the audit trail must not lose what was logged.
+type Handler struct {
+	chain *chain
+	attrs map[string]any
+	group string // Key prefix of the open groups, like "req."
+}
+
+// chain is the writer and the chain state shared by a handler and its
+// children.
+type chain struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	seq    uint64
+	prev   string
+}
+
+// New constructs a Handler writing to w. The chain continues from the seq
+// and hash of the last record already written, both empty for a new stream.
+func New(w io.Writer, seq uint64, prev string) *Handler {
+	return &Handler{
+		chain: &chain{
+			w:    w,
+			seq:  seq,
+			prev: prev,
+		},
+		attrs: map[string]any{},
+	}
+}
+
+// Open constructs a Handler appending to the file, continuing the chain of
+// the records in it. The existing records are verified first, so a service
+// doesn't extend a chain that was tampered with.
+func Open(path string) (*Handler, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	last, err := Verify(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("verifying audit log %s: %w", path, err)
+	}
+
+	h := New(f, last.Seq, last.Hash)
+	h.chain.closer = f
+
+	return h, nil
+}
+
+// Close closes the file opened by Open.
+func (h *Handler) Close() error {
+	h.chain.mu.Lock()
+	defer h.chain.mu.Unlock()
+
+	if h.chain.closer == nil {
+		return nil
+	}
+
+	err := h.chain.closer.Close()
+	h.chain.closer = nil
+
+	return err
+}
+
+// Enabled reports true only for the audit level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return logger.Level(level) == logger.LevelAudit
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make(map[string]any, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		logger.FlattenAttr(h2.attrs, h.group, ".", a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle links the record to the previous one and writes it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	rec := make(map[string]any, len(h.attrs)+r.NumAttrs()+5)
+	for k, v := range h.attrs {
+		rec[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		logger.FlattenAttr(rec, h.group, ".", a)
+		return true
+	})
+
+	rec["time"] = r.Time.UTC().Format(time.RFC3339Nano)
+	rec["msg"] = r.Message
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		rec["file"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	c := h.chain
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rec["seq"] = c.seq + 1
+	rec["prev_hash"] = c.prev
+
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding audit record: %w", err)
+	}
+
+	hash := hashLine(body)
+	line := append(body[:len(body)-1], hashField+hash+"\"}\n"...)
+
+	if _, err := c.w.Write(line); err != nil {
+		return fmt.Errorf("writing audit record: %w", err)
+	}
+
+	c.seq++
+	c.prev = hash
+
+	return nil
+}
+
+// =============================================================================
+
+// Link is the position of a record in the chain.
+type Link struct {
+	Seq  uint64
+	Hash string
+}
+
+// Verify reads the records and checks that every record's hash matches its
+// content and links to the record before it. It returns the last record, to
+// continue the chain from, or an error naming the first broken record.
+func Verify(r io.Reader) (Link, error) {
+	var last Link
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+
+		i := bytes.LastIndex(line, []byte(hashField))
+		if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return last, fmt.Errorf("line %d: no hash", n)
+		}
+
+		body := append(line[:i:i], '}')
+		hash := string(line[i+len(hashField) : len(line)-2])
+
+		if hashLine(body) != hash {
+			return last, fmt.Errorf("line %d: hash doesn't match the record", n)
+		}
+
+		var rec struct {
+			Seq      uint64 `json:"seq"`
+			PrevHash string `json:"prev_hash"`
+		}
+		if err := json.Unmarshal(body, &rec); err != nil {
+			return last, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		if rec.Seq != last.Seq+1 || rec.PrevHash != last.Hash {
+			return last, fmt.Errorf("line %d: chain broken after seq %d", n, last.Seq)
+		}
+
+		last = Link{Seq: rec.Seq, Hash: hash}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return last, fmt.Errorf("reading: %w", err)
+	}
+
+	return last, nil
+}
+
+// ErrBroken is returned by VerifyFile when the chain doesn't hold.
+var ErrBroken = errors.New("audit log chain broken")
+
+// VerifyFile verifies the audit log at the path, see Verify.
+func VerifyFile(path string) (Link, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Link{}, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	last, err := Verify(f)
+	if err != nil {
+		return last, fmt.Errorf("%w: %w", ErrBroken, err)
+	}
+
+	return last, nil
+}
+
+// hashLine returns the hex SHA-256 of a record without its hash. The previous
+// hash is part of the record, so every hash covers the whole chain.
+func hashLine(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Capture keeps the records written by a logger in memory, so tests can
+// assert that specific logs were emitted.
+type Capture struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewCapture constructs a logger that writes every record, down to trace
+// level, into the returned Capture.
+func NewCapture() (*Logger, *Capture) {
+	c := Capture{}
+
+	log := NewWithHandler(&captureHandler{capture: &c})
+
+	return log, &c
+}
+
+// Records returns a copy of the records captured so far.
+func (c *Capture) Records() []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.records)
+}
+
+// Contains reports whether a record with the level and message was captured.
+func (c *Capture) Contains(level Level, msg string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.ContainsFunc(c.records, func(r Record) bool {
+		return r.Level == level && r.Message == msg
+	})
+}
+
+// Reset discards the records captured so far.
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records = nil
+}
+
+// =============================================================================
+
+// captureHandler is a slog.Handler appending records to a Capture.
+// Attributes added with WithAttrs are merged into every record, keys inside
+// groups are prefixed with the group names like "req.id".
+type captureHandler struct {
+	capture *Capture
+	attrs   []slog.Attr
+	group   string
+}
+
+// Enabled reports true for every level.
+func (h *captureHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		a.Key = h.group + a.Key
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *captureHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle stores the record.
+func (h *captureHandler) Handle(ctx context.Context, r slog.Record) error {
+	rec := toRecord(r)
+
+	if h.group != "" {
+		attrs := make(map[string]any, len(rec.Attributes))
+		for k, v := range rec.Attributes {
+			attrs[h.group+k] = v
+		}
+		rec.Attributes = attrs
+	}
+
+	for _, a := range h.attrs {
+		rec.Attributes[a.Key] = a.Value.Any()
+	}
+
+	h.capture.mu.Lock()
+	defer h.capture.mu.Unlock()
+
+	h.capture.records = append(h.capture.records, rec)
+	return nil
+}
@@ -0,0 +1,569 @@
+// Package cloudwatch provides a slog.Handler that ships log records to AWS
+// CloudWatch Logs. Records are batched and sent with PutLogEvents from a
+// background goroutine. It is meant to be added next to the regular writer
+// with logger.WithSink.
+package cloudwatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/retry"
+)
+
+// Limits of a single PutLogEvents call.
+const (
+	maxBatchEvents = 10_000
+	maxBatchBytes  = 1_048_576
+	maxBatchSpan   = 24 * time.Hour
+	eventOverhead  = 26 // Bytes CloudWatch counts per event on top of the message
+	maxEventBytes  = 262_144 - eventOverhead
+)
+
+// Config holds the options for constructing a Handler. Credentials default
+// to the standard AWS environment variables.
+type Config struct {
+	Group         string        // Log group, it must exist
+	Stream        string        // Log stream, created when missing
+	MinLevel      logger.Level  // Records below the level are not shipped
+	FlushInterval time.Duration // Maximum time a record waits, defaults to 5s
+	MaxBatch      int           // Records per request, defaults to and capped at 10000
+	MaxQueue      int           // Records held while sending, defaults to 10000, newer ones are dropped
+
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string       // Overrides the regional endpoint, for tests and VPC endpoints
+	Client          *http.Client // Defaults to a client with a 10s timeout
+}
+
+// Handler is a slog.Handler queueing records for CloudWatch Logs. Handle
+// never blocks on the network, a batch is sent when it's full or when the
+// flush interval has passed.
+type Handler struct {
+	shipper *shipper
+	level   slog.Level
+	attrs   map[string]any
+	group   string // Key prefix of the open groups, like "req."
+}
+
+// event is a record encoded as a CloudWatch log event.
+type event struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// shipper is the queue and the sending goroutine shared by a handler and
+// its children.
+type shipper struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	queue   []event
+	size    int // Bytes of the queued events as CloudWatch counts them
+	dropped int
+
+	// The sequence token is only used by the sending goroutine. CloudWatch
+	// no longer requires it, but it is still honored when returned.
+	token string
+
+	wake chan struct{}
+	done chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// New constructs a Handler and starts the goroutine sending the batches.
+// Call Shutdown to send what's still queued and stop it.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Group == "" || cfg.Stream == "" {
+		return nil, errors.New("log group and stream are required")
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.MaxBatch <= 0 || cfg.MaxBatch > maxBatchEvents {
+		cfg.MaxBatch = maxBatchEvents
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = maxBatchEvents
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("aws region is required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://logs.%s.amazonaws.com", cfg.Region)
+	}
+	if cfg.Client == nil {
+		// Not the instrumented client, its logs would come back to us.
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	s := shipper{
+		cfg:  cfg,
+		now:  time.Now,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+
+	go s.run()
+
+	h := Handler{
+		shipper: &s,
+		level:   slog.Level(cfg.MinLevel),
+		attrs:   map[string]any{},
+	}
+
+	return &h, nil
+}
+
+// Shutdown sends the queued records and stops the handler. It returns when
+// done or when the context is canceled. Records handled afterwards are
+// dropped.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	s := h.shipper
+
+	s.once.Do(func() {
+		close(s.stop)
+	})
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enabled checks whether the given log level is shipped.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make(map[string]any, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		logger.FlattenAttr(h2.attrs, h.group, ".", a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle encodes the record as a JSON message and queues it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	msg := make(map[string]any, len(h.attrs)+r.NumAttrs()+4)
+	for k, v := range h.attrs {
+		msg[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		logger.FlattenAttr(msg, h.group, ".", a)
+		return true
+	})
+
+	msg["time"] = r.Time.UTC().Format(time.RFC3339Nano)
+	msg["level"] = logger.Level(r.Level).String()
+	msg["msg"] = r.Message
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		msg["file"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding cloudwatch message: %w", err)
+	}
+
+	// CloudWatch rejects larger events, a cut message is better than none.
+	message := string(data)
+	if len(message) > maxEventBytes {
+		message = strings.ToValidUTF8(message[:maxEventBytes], "")
+	}
+
+	h.shipper.enqueue(event{
+		Timestamp: r.Time.UnixMilli(),
+		Message:   message,
+	})
+
+	return nil
+}
+
+// =============================================================================
+
+// enqueue adds the event to the queue and wakes the goroutine once a batch
+// is full.
+func (s *shipper) enqueue(e event) {
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	s.mu.Lock()
+	if len(s.queue) >= s.cfg.MaxQueue {
+		s.dropped++
+		s.mu.Unlock()
+		return
+	}
+	s.queue = append(s.queue, e)
+	s.size += len(e.Message) + eventOverhead
+	full := len(s.queue) >= s.cfg.MaxBatch || s.size >= maxBatchBytes
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run sends the queued events every flush interval, or sooner when a batch
+// is full.
+func (s *shipper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-s.stop:
+			s.flush()
+			return
+		}
+
+		s.flush()
+	}
+}
+
+// flush sends everything queued, split into batches within the limits of
+// PutLogEvents. Failures are not logged, logging them would feed the
+// handler that is failing.
+func (s *shipper) flush() {
+	s.mu.Lock()
+	events, dropped := s.queue, s.dropped
+	s.queue, s.size, s.dropped = nil, 0, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		events = append(events, event{
+			Timestamp: s.now().UnixMilli(),
+			Message:   fmt.Sprintf(`{"level":"WARN","msg":"cloudwatch: %d records dropped, queue full"}`, dropped),
+		})
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	// The events of a batch must be in chronological order.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+
+	for len(events) > 0 {
+		n := batchLen(events, s.cfg.MaxBatch)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		s.put(ctx, events[:n])
+		cancel()
+
+		events = events[n:]
+	}
+}
+
+// batchLen returns how many of the sorted events fit in one request.
+func batchLen(events []event, maxEvents int) int {
+	size := 0
+	first := events[0].Timestamp
+
+	for i, e := range events {
+		size += len(e.Message) + eventOverhead
+		if i == maxEvents || size > maxBatchBytes || time.Duration(e.Timestamp-first)*time.Millisecond >= maxBatchSpan {
+			return i
+		}
+	}
+
+	return len(events)
+}
+
+// put sends a batch, retrying throttled requests and server errors with
+// backoff. A rejected sequence token is replaced with the expected one and a
+// missing stream is created before trying again.
+func (s *shipper) put(ctx context.Context, events []event) error {
+	cfg := retry.Config{
+		Name:        "cloudwatch",
+		MaxAttempts: 5,
+		MaxDelay:    10 * time.Second,
+		RetryIf:     retryable,
+	}
+
+	return retry.Do(ctx, cfg, func(ctx context.Context) error {
+		err := s.putLogEvents(ctx, events)
+
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			return err
+		}
+
+		switch apiErr.Type {
+		case "DataAlreadyAcceptedException":
+			s.token = apiErr.ExpectedSequenceToken
+			return nil
+
+		case "InvalidSequenceTokenException":
+			s.token = apiErr.ExpectedSequenceToken
+
+		case "ResourceNotFoundException":
+			if err := s.createLogStream(ctx); err != nil {
+				return retry.Permanent(err)
+			}
+		}
+
+		return err
+	})
+}
+
+// putLogEvents makes a single PutLogEvents call.
+func (s *shipper) putLogEvents(ctx context.Context, events []event) error {
+	in := struct {
+		LogGroupName  string  `json:"logGroupName"`
+		LogStreamName string  `json:"logStreamName"`
+		LogEvents     []event `json:"logEvents"`
+		SequenceToken string  `json:"sequenceToken,omitempty"`
+	}{
+		LogGroupName:  s.cfg.Group,
+		LogStreamName: s.cfg.Stream,
+		LogEvents:     events,
+		SequenceToken: s.token,
+	}
+
+	var out struct {
+		NextSequenceToken string `json:"nextSequenceToken"`
+	}
+
+	if err := s.call(ctx, "PutLogEvents", in, &out); err != nil {
+		return err
+	}
+
+	if out.NextSequenceToken != "" {
+		s.token = out.NextSequenceToken
+	}
+
+	return nil
+}
+
+// createLogStream creates the configured stream, which is fine to fail when
+// another instance created it first.
+func (s *shipper) createLogStream(ctx context.Context) error {
+	in := struct {
+		LogGroupName  string `json:"logGroupName"`
+		LogStreamName string `json:"logStreamName"`
+	}{
+		LogGroupName:  s.cfg.Group,
+		LogStreamName: s.cfg.Stream,
+	}
+
+	err := s.call(ctx, "CreateLogStream", in, nil)
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Type == "ResourceAlreadyExistsException" {
+		return nil
+	}
+
+	return err
+}
+
+// call makes a signed request to the CloudWatch Logs JSON API and decodes
+// the response into out when it's not nil.
+func (s *shipper) call(ctx context.Context, action string, in any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+
+	s.sign(req, body)
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudwatch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := apiError{
+			Status: resp.StatusCode,
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+
+		// The type may be qualified, like "com.amazonaws.logs#ThrottlingException".
+		if _, name, ok := strings.Cut(apiErr.Type, "#"); ok {
+			apiErr.Type = name
+		}
+
+		return &apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("cloudwatch: decode: %w", err)
+	}
+
+	return nil
+}
+
+// sign adds the Signature Version 4 headers to the request.
+func (s *shipper) sign(req *http.Request, body []byte) {
+	const service = "logs"
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if s.cfg.SessionToken != "" {
+		headers = []string{"content-type", "host", "x-amz-date", "x-amz-security-token", "x-amz-target"}
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/" + service + "/aws4_request"
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// =============================================================================
+
+// apiError is an error response from the CloudWatch Logs API.
+type apiError struct {
+	Status                int    `json:"-"`
+	Type                  string `json:"__type"`
+	Message               string `json:"message"`
+	ExpectedSequenceToken string `json:"expectedSequenceToken"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("cloudwatch: %d %s: %s", e.Status, e.Type, e.Message)
+}
+
+// retryable reports whether a failed request is worth sending again:
+// network errors, throttling, server errors, and stale sequence tokens.
+func retryable(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+
+	switch apiErr.Type {
+	case "ThrottlingException", "ServiceUnavailableException", "InvalidSequenceTokenException", "ResourceNotFoundException":
+		return true
+	}
+
+	return apiErr.Status >= 500
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ANSI escape sequences used by the console handler.
+const (
+	colorReset   = "\033[0m"
+	colorDim     = "\033[2m"
+	colorRed     = "\033[31m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorMagenta = "\033[35m"
+	colorCyan    = "\033[36m"
+	colorGray    = "\033[90m"
+)
+
+// consoleHandler is a slog.Handler that writes one colored line per record
+// with the time, level, service, caller, and trace ID in aligned columns,
+// followed by the message and the remaining attributes as key=value pairs.
+type consoleHandler struct {
+	mu      *sync.Mutex // Serializes writes from handlers sharing the writer
+	w       io.Writer
+	level   slog.Leveler
+	service string // Taken out of the attributes to get its own column
+	attrs   []byte // Preformatted attributes added through WithAttrs
+	group   string // Key prefix of the open groups, like "req."
+}
+
+// newConsoleHandler creates a consoleHandler writing records at or above the
+// level to w.
+func newConsoleHandler(w io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{
+		mu:    &sync.Mutex{},
+		w:     w,
+		level: level,
+	}
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *consoleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// WithAttrs returns a new handler with additional attributes attached. The
+// service attribute is rendered in its own column and version is dropped,
+// it only adds noise on a developer's terminal.
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = bytes.Clone(h.attrs)
+
+	for _, a := range attrs {
+		if h.group == "" {
+			switch a.Key {
+			case "service":
+				h2.service = a.Value.String()
+				continue
+			case "version":
+				continue
+			}
+		}
+		h2.attrs = appendAttr(h2.attrs, h.group, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle formats the record as a single line and writes it.
+func (h *consoleHandler) Handle(ctx context.Context, r slog.Record) error {
+	var traceID string
+	var attrs []byte
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "trace_id" && h.group == "" {
+			traceID = a.Value.String()
+			return true
+		}
+		attrs = appendAttr(attrs, h.group, a)
+		return true
+	})
+
+	var file string
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		file = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	// A trace ID is a 32 character hex string, the first 8 are enough to
+	// tell requests apart while reading.
+	if len(traceID) > 8 {
+		traceID = traceID[:8]
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%s%s ", colorGray, r.Time.Format("15:04:05.000"), colorReset)
+	fmt.Fprintf(&buf, "%s%-5s%s ", levelColor(r.Level), Level(r.Level), colorReset)
+	if h.service != "" {
+		fmt.Fprintf(&buf, "%s%s%s ", colorMagenta, h.service, colorReset)
+	}
+	fmt.Fprintf(&buf, "%s%-20s%s ", colorDim, file, colorReset)
+	fmt.Fprintf(&buf, "%s%-8s%s ", colorBlue, traceID, colorReset)
+	buf.WriteString(r.Message)
+	buf.Write(h.attrs)
+	buf.Write(attrs)
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// appendAttr appends the attribute as a dimmed key and its value, expanding
+// groups into dotted keys.
+func appendAttr(buf []byte, prefix string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			buf = appendAttr(buf, prefix, ga)
+		}
+		return buf
+	}
+
+	return fmt.Appendf(buf, " %s%s%s=%s%s", colorDim, prefix, a.Key, colorReset, quoteAttr(a.Value.String()))
+}
+
+// quoteAttr quotes values that would be ambiguous when unquoted.
+func quoteAttr(s string) string {
+	if s == "" || quoteValue(s) || bytes.ContainsRune([]byte(s), '=') {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// levelColor returns the color a level is rendered in.
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorCyan
+	default:
+		return colorGray
+	}
+}
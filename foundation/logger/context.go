@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+)
+
+type ctxKey int
+
+const logKey ctxKey = 1
+
+// nop is returned by FromContext when the context carries no logger.
+var nop = NewNop()
+
+// IntoContext returns a copy of the context carrying the logger.
+func IntoContext(ctx context.Context, log *Logger) context.Context {
+	return context.WithValue(ctx, logKey, log)
+}
+
+// FromContext returns the logger carried by the context. When there is none
+// a logger that discards everything is returned, so callers never need to
+// check for nil.
+func FromContext(ctx context.Context) *Logger {
+	log, ok := ctx.Value(logKey).(*Logger)
+	if !ok || log == nil {
+		return nop
+	}
+	return log
+}
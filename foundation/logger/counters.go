@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+)
+
+// counters counts the records written per level into the counters of a
+// metrics provider. It's shared between a logger and its children.
+type counters struct {
+	provider atomic.Pointer[metrics.Provider]
+
+	mu      sync.Mutex
+	byLevel map[Level]metrics.Counter
+}
+
+// setProvider switches the provider the records are counted in.
+func (c *counters) setProvider(p metrics.Provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.provider.Store(&p)
+	c.byLevel = make(map[Level]metrics.Counter)
+}
+
+// inc counts a record of the level when a provider is set.
+func (c *counters) inc(level Level) {
+	p := c.provider.Load()
+	if p == nil {
+		return
+	}
+
+	c.mu.Lock()
+	counter, ok := c.byLevel[level]
+	if !ok {
+		counter = (*p).Counter("log_records_total", "Log records written.", metrics.Labels{"level": level.String()})
+		c.byLevel[level] = counter
+	}
+	c.mu.Unlock()
+
+	counter.Inc()
+}
+
+// =============================================================================
+
+// countingHandler is a slog.Handler counting the records it passes on. It
+// sits inside the filter, dedup and sampling handlers so the records they
+// drop aren't counted.
+type countingHandler struct {
+	handler  slog.Handler
+	counters *counters
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *countingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *countingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &countingHandler{
+		handler:  h.handler.WithAttrs(attrs),
+		counters: h.counters,
+	}
+}
+
+// WithGroup returns a new handler that groups all attributes under the given name.
+func (h *countingHandler) WithGroup(name string) slog.Handler {
+	return &countingHandler{
+		handler:  h.handler.WithGroup(name),
+		counters: h.counters,
+	}
+}
+
+// Handle passes the record on and counts it once it's written.
+func (h *countingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if err := h.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	h.counters.inc(Level(r.Level))
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/metrics"
+)
+
+func Test_CountersSkipDropped(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		opt     logger.Option
+		want    string
+		notWant string
+	}{
+		{
+			name:    "sampling",
+			opt:     logger.WithSampling(1, 0),
+			want:    `log_records_total{level="WARN"} 1`,
+			notWant: `log_records_total{level="WARN"} 5`,
+		},
+		{
+			name:    "filter",
+			opt:     logger.WithFilter(logger.Not(logger.AttrEquals("drop", true))),
+			notWant: `level="WARN"`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := metrics.NewPrometheus()
+			var buf bytes.Buffer
+			log := logger.New(&buf, logger.LevelInfo, "TEST", nil, logger.WithMetrics(provider), tt.opt)
+
+			ctx := context.Background()
+			log.Info(ctx, "kept")
+			for range 5 {
+				log.Warn(ctx, "flood", "drop", true)
+			}
+
+			got := scrape(t, provider)
+
+			if !strings.Contains(got, `log_records_total{level="INFO"} 1`) {
+				t.Errorf("should count the kept info record:\n%s", got)
+			}
+
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("should count the records written, missing %s:\n%s", tt.want, got)
+			}
+
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("should not count the records dropped, found %s:\n%s", tt.notWant, got)
+			}
+		})
+	}
+}
+
+// scrape returns the metrics of the provider in the text exposition format.
+func scrape(t *testing.T, provider *metrics.Prometheus) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	provider.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	return w.Body.String()
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// dedupEntry tracks the copies of a record seen within the current window.
+type dedupEntry struct {
+	ctx      context.Context
+	handler  slog.Handler
+	last     slog.Record
+	repeated int
+}
+
+// deduper holds the entries shared by a dedup handler and its children.
+type deduper struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+// dedupHandler is a slog.Handler that writes the first record with a given
+// level and message and holds back the identical ones that follow within
+// the window. When the window closes the last copy is written once with a
+// repeated attribute counting the copies held back.
+type dedupHandler struct {
+	handler slog.Handler
+	deduper *deduper
+}
+
+// newDedupHandler wraps the handler with duplicate suppression.
+func newDedupHandler(handler slog.Handler, window time.Duration) *dedupHandler {
+	return &dedupHandler{
+		handler: handler,
+		deduper: &deduper{
+			window:  window,
+			entries: make(map[string]*dedupEntry),
+		},
+	}
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+// The entries are shared with the parent.
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dedupHandler{
+		handler: h.handler.WithAttrs(attrs),
+		deduper: h.deduper,
+	}
+}
+
+// WithGroup returns a new handler that groups all attributes under the given name.
+// The entries are shared with the parent.
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	return &dedupHandler{
+		handler: h.handler.WithGroup(name),
+		deduper: h.deduper,
+	}
+}
+
+// Handle writes the record unless an identical one was written within the
+// window, in which case it's counted and kept for the summary. Audit records
+// are always written.
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	if Level(r.Level) == LevelAudit {
+		return h.handler.Handle(ctx, r)
+	}
+
+	key := Level(r.Level).String() + "\x00" + r.Message
+
+	d := h.deduper
+	d.mu.Lock()
+
+	if e, ok := d.entries[key]; ok {
+		e.ctx = context.WithoutCancel(ctx)
+		e.handler = h.handler
+		e.last = r.Clone()
+		e.repeated++
+		d.mu.Unlock()
+		return nil
+	}
+
+	d.entries[key] = &dedupEntry{}
+	time.AfterFunc(d.window, func() {
+		d.flush(key)
+	})
+
+	d.mu.Unlock()
+
+	return h.handler.Handle(ctx, r)
+}
+
+// flush closes the window of the key, writing the summary record when
+// copies were held back.
+func (d *deduper) flush(key string) {
+	d.mu.Lock()
+	e := d.entries[key]
+	delete(d.entries, key)
+	d.mu.Unlock()
+
+	if e == nil || e.repeated == 0 {
+		return
+	}
+
+	e.last.AddAttrs(slog.Int("repeated", e.repeated))
+	e.handler.Handle(e.ctx, e.last)
+}
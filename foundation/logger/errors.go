@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// fielder is implemented by errors that carry structured details.
+type fielder interface {
+	Fields() map[string]any
+}
+
+// coder is implemented by errors that carry a code, like errs.Error.
+type coder interface {
+	ErrorCode() string
+}
+
+// plainErrors are the error types that only carry a message or wrap another
+// error, they say nothing about the kind of the error.
+var plainErrors = map[string]bool{
+	"*errors.errorString": true,
+	"*errors.joinError":   true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+}
+
+// expandErrors replaces error values in the key/value pairs with a group of
+// structured fields when the error has more to say than its message. The
+// pairs are copied before the first replacement, args belongs to the caller.
+func expandErrors(args []any) []any {
+	copied := false
+	set := func(i int, v any) {
+		if !copied {
+			args = slices.Clone(args)
+			copied = true
+		}
+		args[i] = v
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch a := args[i].(type) {
+		case slog.Attr:
+			if err, ok := a.Value.Any().(error); ok && a.Value.Kind() == slog.KindAny {
+				if v, ok := errorValue(err); ok {
+					set(i, slog.Attr{Key: a.Key, Value: v})
+				}
+			}
+
+		case string:
+			if i+1 >= len(args) {
+				return args
+			}
+			i++
+			if err, ok := args[i].(error); ok {
+				if v, ok := errorValue(err); ok {
+					set(i, v)
+				}
+			}
+		}
+	}
+
+	return args
+}
+
+// errorValue returns the error as a group with its message, the type of the
+// first typed error in the chain as kind, its code, its innermost cause, and
+// its fields. It reports false for errors that are only a message, which are
+// left to be logged as a string.
+func errorValue(err error) (slog.Value, bool) {
+	var kind string
+	var code string
+	var fields map[string]any
+
+	cause := err
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		cause = e
+
+		if t := fmt.Sprintf("%T", e); kind == "" && !plainErrors[t] {
+			kind = t
+		}
+		if c, ok := e.(coder); ok && code == "" {
+			code = c.ErrorCode()
+		}
+		if f, ok := e.(fielder); ok && fields == nil {
+			fields = f.Fields()
+		}
+	}
+
+	if kind == "" && code == "" && fields == nil {
+		return slog.Value{}, false
+	}
+
+	attrs := []slog.Attr{
+		slog.String("message", err.Error()),
+	}
+	if kind != "" {
+		attrs = append(attrs, slog.String("kind", kind))
+	}
+	if code != "" {
+		attrs = append(attrs, slog.String("code", code))
+	}
+	if cause != err {
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+
+	return slog.GroupValue(attrs...), true
+}
@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// codedError is an error with a code, which the logger expands into fields.
+type codedError struct{}
+
+func (codedError) Error() string     { return "not found" }
+func (codedError) ErrorCode() string { return "not_found" }
+
+func Test_ErrorsKeepArgs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", nil)
+
+	err := codedError{}
+	args := []any{"err", err, slog.Any("cause", err)}
+
+	log.Error(context.Background(), "request failed", args...)
+
+	if !strings.Contains(buf.String(), `"code":"not_found"`) {
+		t.Fatalf("should expand the error into fields: %s", buf.String())
+	}
+
+	if got, ok := args[1].(codedError); !ok {
+		t.Errorf("should leave the caller's error value alone, got %#v", args[1])
+	} else if got != err {
+		t.Errorf("should leave the caller's error value alone, got %v", got)
+	}
+
+	if a, ok := args[2].(slog.Attr); !ok || a.Value.Kind() != slog.KindAny {
+		t.Errorf("should leave the caller's attribute alone, got %#v", args[2])
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"log/slog"
+	"time"
+)
+
+// FlattenAttr stores the value of the attribute in fields under its key,
+// expanding groups into keys joined with sep. Numbers and booleans are kept,
+// durations and times are formatted and anything else is stored as a string,
+// the shape sinks encoding records as flat JSON objects need.
+func FlattenAttr(fields map[string]any, prefix string, sep string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + sep
+		}
+		for _, ga := range a.Value.Group() {
+			FlattenAttr(fields, prefix, sep, ga)
+		}
+		return
+	}
+
+	key := prefix + a.Key
+
+	switch a.Value.Kind() {
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool:
+		fields[key] = a.Value.Any()
+	case slog.KindDuration:
+		fields[key] = a.Value.Duration().String()
+	case slog.KindTime:
+		fields[key] = a.Value.Time().Format(time.RFC3339Nano)
+	default:
+		fields[key] = a.Value.String()
+	}
+}
@@ -0,0 +1,72 @@
+package logger_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_FlattenAttr(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	attrs := []slog.Attr{
+		slog.Int("status", 200),
+		slog.Bool("cached", true),
+		slog.Duration("took", 1500*time.Millisecond),
+		slog.Group("req",
+			slog.String("path", "/v1/orders"),
+			slog.Time("at", at),
+			slog.Group("", slog.String("inline", "yes")),
+		),
+		{},
+	}
+
+	table := []struct {
+		name   string
+		prefix string
+		sep    string
+		exp    map[string]any
+	}{
+		{
+			name: "dots",
+			sep:  ".",
+			exp: map[string]any{
+				"status":     int64(200),
+				"cached":     true,
+				"took":       "1.5s",
+				"req.path":   "/v1/orders",
+				"req.at":     "2024-03-01T12:00:00Z",
+				"req.inline": "yes",
+			},
+		},
+		{
+			name:   "underscores",
+			prefix: "app_",
+			sep:    "_",
+			exp: map[string]any{
+				"app_status":     int64(200),
+				"app_cached":     true,
+				"app_took":       "1.5s",
+				"app_req_path":   "/v1/orders",
+				"app_req_at":     "2024-03-01T12:00:00Z",
+				"app_req_inline": "yes",
+			},
+		},
+	}
+
+	for _, tt := range table {
+		fields := make(map[string]any)
+		for _, a := range attrs {
+			logger.FlattenAttr(fields, tt.prefix, tt.sep, a)
+		}
+
+		if diff := cmp.Diff(fields, tt.exp); diff != "" {
+			t.Errorf("%s: should flatten the attributes:\n%s", tt.name, diff)
+		}
+	}
+}
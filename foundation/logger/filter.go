@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+)
+
+// FilterFn reports whether a record is kept. The attributes of the record
+// include the ones added with With, members of a group are listed under
+// their keys joined with dots, like "req.path".
+type FilterFn func(ctx context.Context, r Record) bool
+
+// AttrEquals returns a FilterFn keeping the records whose attribute with the
+// key has the value. Numbers match regardless of their Go type.
+func AttrEquals(key string, value any) FilterFn {
+	want := slog.AnyValue(value)
+
+	return func(ctx context.Context, r Record) bool {
+		v, ok := r.Attributes[key]
+		return ok && slog.AnyValue(v).Equal(want)
+	}
+}
+
+// Not returns a FilterFn keeping the records fn drops.
+func Not(fn FilterFn) FilterFn {
+	return func(ctx context.Context, r Record) bool {
+		return !fn(ctx, r)
+	}
+}
+
+// Filter wraps the handler so it only receives the records fn keeps, for
+// example to forward the records of one tenant to a sink:
+//
+//	logger.WithSink(logger.Filter(sink, logger.AttrEquals("tenant", "acme")))
+func Filter(handler slog.Handler, fn FilterFn) slog.Handler {
+	return &filterHandler{
+		handler: handler,
+		keep:    fn,
+		attrs:   map[string]any{},
+	}
+}
+
+// filterHandler is a slog.Handler passing on the records a FilterFn keeps.
+// It tracks the attributes added to it so the function sees them too.
+type filterHandler struct {
+	handler slog.Handler
+	keep    FilterFn
+	attrs   map[string]any
+	group   string // Key prefix of the open groups, like "req."
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with the attributes attached and
+// remembered for the filter.
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithAttrs(attrs)
+	h2.attrs = maps.Clone(h.attrs)
+	for _, a := range attrs {
+		filterAttr(h2.attrs, h.group, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that groups all attributes under the given name.
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.handler = h.handler.WithGroup(name)
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle passes the record on when the filter keeps it.
+func (h *filterHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make(map[string]any, len(h.attrs)+r.NumAttrs())
+	maps.Copy(attrs, h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		filterAttr(attrs, h.group, a)
+		return true
+	})
+
+	rec := Record{
+		Time:       r.Time,
+		Message:    r.Message,
+		Level:      Level(r.Level),
+		Attributes: attrs,
+	}
+
+	if !h.keep(ctx, rec) {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// filterAttr stores the value of the attribute under its key, expanding
+// groups into keys joined with dots.
+func filterAttr(attrs map[string]any, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			filterAttr(attrs, prefix, ga)
+		}
+		return
+	}
+
+	attrs[prefix+a.Key] = a.Value.Any()
+}
@@ -0,0 +1,300 @@
+// Package gelf provides a slog.Handler that ships log records to Graylog in
+// the GELF 1.1 format over UDP or TCP. It is meant to be added next to the
+// regular writer with logger.WithSink.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// GELF limits a UDP message to 128 chunks.
+const maxChunks = 128
+
+// Config holds the options for constructing a Handler.
+type Config struct {
+	Addr      string       // Graylog input host:port
+	Network   string       // udp or tcp, defaults to udp
+	Host      string       // Reported as the source, defaults to the hostname
+	MinLevel  logger.Level // Records below the level are not shipped
+	ChunkSize int          // Largest UDP datagram, defaults to 1420
+	Compress  bool         // Gzip UDP messages, TCP doesn't support it
+	Timeout   time.Duration
+}
+
+// Handler is a slog.Handler writing GELF messages to a Graylog input.
+// Records are written synchronously, a write timeout keeps a slow input
+// from stalling the service for long.
+type Handler struct {
+	conn  *conn
+	level slog.Level
+	attrs map[string]any
+	group string // Key prefix of the open groups, like "req_"
+}
+
+// conn is the connection shared by a handler and its children.
+type conn struct {
+	cfg Config
+
+	mu sync.Mutex
+	c  net.Conn
+}
+
+// New constructs a Handler and connects to the input. For TCP a broken
+// connection is dialed again on the next record.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Network != "udp" && cfg.Network != "tcp" {
+		return nil, fmt.Errorf("unknown network %q", cfg.Network)
+	}
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	if cfg.ChunkSize <= 12 {
+		cfg.ChunkSize = 1420
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+
+	c := conn{
+		cfg: cfg,
+	}
+
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+
+	h := Handler{
+		conn:  &c,
+		level: slog.Level(cfg.MinLevel),
+		attrs: map[string]any{},
+	}
+
+	return &h, nil
+}
+
+// Close closes the connection.
+func (h *Handler) Close() error {
+	h.conn.mu.Lock()
+	defer h.conn.mu.Unlock()
+
+	if h.conn.c == nil {
+		return nil
+	}
+
+	err := h.conn.c.Close()
+	h.conn.c = nil
+
+	return err
+}
+
+// Enabled checks whether the given log level is shipped.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make(map[string]any, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		logger.FlattenAttr(h2.attrs, h.group, "_", a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "_"
+	return &h2
+}
+
+// Handle encodes the record as a GELF message and writes it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(map[string]any, len(h.attrs)+r.NumAttrs())
+	for k, v := range h.attrs {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		logger.FlattenAttr(fields, h.group, "_", a)
+		return true
+	})
+
+	msg := make(map[string]any, len(fields)+6)
+	for k, v := range fields {
+		msg[additionalKey(k)] = v
+	}
+
+	msg["version"] = "1.1"
+	msg["host"] = h.conn.cfg.Host
+	msg["short_message"] = r.Message
+	msg["timestamp"] = float64(r.Time.UnixMicro()) / 1e6
+	msg["level"] = syslogLevel(r.Level)
+	msg["_level_name"] = logger.Level(r.Level).String()
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		msg["_file"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding gelf message: %w", err)
+	}
+
+	return h.conn.write(data)
+}
+
+// =============================================================================
+
+// dial connects to the input. The caller must hold c.mu unless the conn
+// isn't shared yet.
+func (c *conn) dial() error {
+	nc, err := net.DialTimeout(c.cfg.Network, c.cfg.Addr, c.cfg.Timeout)
+	if err != nil {
+		return fmt.Errorf("dialing graylog: %w", err)
+	}
+
+	c.c = nc
+	return nil
+}
+
+// write sends the message, chunked and compressed as configured for UDP and
+// null terminated for TCP.
+func (c *conn) write(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.c == nil {
+		if err := c.dial(); err != nil {
+			return err
+		}
+	}
+
+	c.c.SetWriteDeadline(time.Now().Add(c.cfg.Timeout))
+
+	if c.cfg.Network == "tcp" {
+		if _, err := c.c.Write(append(data, 0)); err != nil {
+			c.c.Close()
+			c.c = nil
+			return fmt.Errorf("writing gelf message: %w", err)
+		}
+		return nil
+	}
+
+	if c.cfg.Compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		data = buf.Bytes()
+	}
+
+	return c.writeChunks(data)
+}
+
+// writeChunks writes the message in a single datagram or, when it's larger
+// than the chunk size, in GELF chunks sharing a random message id.
+func (c *conn) writeChunks(data []byte) error {
+	if len(data) <= c.cfg.ChunkSize {
+		_, err := c.c.Write(data)
+		return err
+	}
+
+	// Every chunk carries a 12 byte header: magic, message id, seq number
+	// and seq count.
+	const header = 12
+	size := c.cfg.ChunkSize - header
+
+	count := (len(data) + size - 1) / size
+	if count > maxChunks {
+		return errors.New("gelf message too large")
+	}
+
+	var id [8]byte
+	rand.Read(id[:])
+
+	chunk := make([]byte, 0, c.cfg.ChunkSize)
+	for i := range count {
+		end := min((i+1)*size, len(data))
+
+		chunk = chunk[:0]
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*size:end]...)
+
+		if _, err := c.c.Write(chunk); err != nil {
+			return fmt.Errorf("writing gelf chunk: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// syslogLevel maps a slog level to the syslog severity GELF expects.
+func syslogLevel(level slog.Level) int {
+	switch l := logger.Level(level); {
+	case l >= logger.LevelFatal:
+		return 2 // critical
+	case l == logger.LevelAudit:
+		return 5 // notice
+	case l >= logger.LevelError:
+		return 3 // error
+	case l >= logger.LevelWarn:
+		return 4 // warning
+	case l >= logger.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// additionalKey turns a flattened attribute key into the name of an
+// additional field, which GELF wants prefixed with an underscore.
+func additionalKey(key string) string {
+	key = "_" + fieldName(key)
+	if key == "_id" {
+		key = "__id"
+	}
+	return key
+}
+
+// fieldName replaces the characters GELF doesn't allow in field names.
+func fieldName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, key)
+}
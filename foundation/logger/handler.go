@@ -50,11 +50,19 @@ func (h *logHandler) WithGroup(name string) slog.Handler {
 // 2. Passes the record to the underlying slog.Handler for normal processing.
 func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
 	switch r.Level {
+	case slog.Level(LevelTrace):
+		if h.events.Trace != nil {
+			h.events.Trace(ctx, toRecord(r))
+		}
+	case slog.Level(LevelAudit):
+		if h.events.Audit != nil {
+			h.events.Audit(ctx, toRecord(r))
+		}
 	case slog.LevelDebug:
 		if h.events.Debug != nil {
 			h.events.Debug(ctx, toRecord(r))
 		}
-	case slog.LevelError:
+	case slog.LevelError, slog.Level(LevelFatal):
 		if h.events.Error != nil {
 			h.events.Error(ctx, toRecord(r))
 		}
@@ -0,0 +1,246 @@
+// Package journald provides a slog.Handler that writes records to the
+// systemd journal over its native socket protocol, so the journal gets a
+// priority and one field per attribute instead of a JSON blob. It is meant
+// to be added next to the regular writer with logger.WithSink.
+package journald
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// socketPath is where journald listens for native protocol datagrams.
+const socketPath = "/run/systemd/journal/socket"
+
+// Config holds the options for constructing a Handler.
+type Config struct {
+	Identifier string       // SYSLOG_IDENTIFIER, defaults to the binary name
+	Socket     string       // Defaults to /run/systemd/journal/socket
+	MinLevel   logger.Level // Records below the level are not written
+}
+
+// Handler is a slog.Handler writing to the journal. Attribute keys become
+// upper case journal fields, like REQ_ID for the id attribute in the req
+// group.
+type Handler struct {
+	conn       *net.UnixConn
+	addr       *net.UnixAddr
+	identifier string
+	level      slog.Level
+	attrs      []field
+	group      string // Field prefix of the open groups, like "REQ_"
+}
+
+// field is a single journal field.
+type field struct {
+	name  string
+	value string
+}
+
+// New constructs a Handler. An error is returned when the journal socket
+// isn't there, which is the case outside systemd.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Socket == "" {
+		cfg.Socket = socketPath
+	}
+	if cfg.Identifier == "" {
+		cfg.Identifier = filepath.Base(os.Args[0])
+	}
+
+	if _, err := os.Stat(cfg.Socket); err != nil {
+		return nil, fmt.Errorf("journal socket: %w", err)
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("opening socket: %w", err)
+	}
+
+	h := Handler{
+		conn:       conn,
+		addr:       &net.UnixAddr{Name: cfg.Socket, Net: "unixgram"},
+		identifier: cfg.Identifier,
+		level:      slog.Level(cfg.MinLevel),
+	}
+
+	return &h, nil
+}
+
+// Close closes the socket.
+func (h *Handler) Close() error {
+	return h.conn.Close()
+}
+
+// Enabled checks whether the given log level is written.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]field, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(h2.attrs, h.attrs)
+
+	for _, a := range attrs {
+		h2.attrs = appendField(h2.attrs, h.group, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the fields of all
+// attributes added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + fieldName(name) + "_"
+	return &h2
+}
+
+// Handle encodes the record in the native protocol and sends it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	fields := []field{
+		{"MESSAGE", r.Message},
+		{"PRIORITY", strconv.Itoa(priority(r.Level))},
+		{"SYSLOG_IDENTIFIER", h.identifier},
+		{"LEVEL", logger.Level(r.Level).String()},
+	}
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		fields = append(fields,
+			field{"CODE_FILE", frame.File},
+			field{"CODE_LINE", strconv.Itoa(frame.Line)},
+			field{"CODE_FUNC", frame.Function},
+		)
+	}
+
+	fields = append(fields, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendField(fields, h.group, a)
+		return true
+	})
+
+	return h.send(encode(fields))
+}
+
+// send writes the datagram. Datagrams too large for the socket are passed
+// as a file descriptor, as the protocol allows.
+func (h *Handler) send(data []byte) error {
+	_, err := h.conn.WriteToUnix(data, h.addr)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return fmt.Errorf("writing to journal: %w", err)
+	}
+
+	return sendFile(h.conn, h.addr, data)
+}
+
+// =============================================================================
+
+// encode renders the fields in the native protocol. Values holding a newline
+// are written with an explicit length.
+func encode(fields []field) []byte {
+	var buf bytes.Buffer
+	for _, f := range fields {
+		if !strings.Contains(f.value, "\n") {
+			buf.WriteString(f.name)
+			buf.WriteByte('=')
+			buf.WriteString(f.value)
+			buf.WriteByte('\n')
+			continue
+		}
+
+		buf.WriteString(f.name)
+		buf.WriteByte('\n')
+		binary.Write(&buf, binary.LittleEndian, uint64(len(f.value)))
+		buf.WriteString(f.value)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// priority maps a slog level to a syslog priority.
+func priority(level slog.Level) int {
+	switch l := logger.Level(level); {
+	case l >= logger.LevelFatal:
+		return 2 // critical
+	case l == logger.LevelAudit:
+		return 5 // notice
+	case l >= logger.LevelError:
+		return 3 // error
+	case l >= logger.LevelWarn:
+		return 4 // warning
+	case l >= logger.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// appendField converts the attribute, expanding groups into fields joined
+// with underscores.
+func appendField(fields []field, prefix string, a slog.Attr) []field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += fieldName(a.Key) + "_"
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendField(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	return append(fields, field{name: fieldName(prefix + a.Key), value: a.Value.String()})
+}
+
+// fieldName makes the key a valid journal field name: upper case letters,
+// digits, and underscores, not starting with an underscore or a digit, and
+// at most 64 characters.
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, key)
+
+	name = strings.TrimLeft(name, "_0123456789")
+	if name == "" {
+		name = "FIELD"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package journald
+
+import (
+	"errors"
+	"net"
+)
+
+// sendFile is not supported without unix rights, large records are dropped.
+func sendFile(conn *net.UnixConn, addr *net.UnixAddr, data []byte) error {
+	return errors.New("journal record too large")
+}
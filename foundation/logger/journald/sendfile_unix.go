@@ -0,0 +1,37 @@
+//go:build unix
+
+package journald
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// sendFile writes the data to an unlinked temporary file and passes its
+// descriptor to journald, which reads the message from it.
+func sendFile(conn *net.UnixConn, addr *net.UnixAddr, data []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "journal-")
+	if err != nil {
+		return fmt.Errorf("creating journal file: %w", err)
+	}
+	defer f.Close()
+
+	os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing journal file: %w", err)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return fmt.Errorf("rewinding journal file: %w", err)
+	}
+
+	rights := syscall.UnixRights(int(f.Fd()))
+	if _, _, err := conn.WriteMsgUnix(nil, rights, addr); err != nil {
+		return fmt.Errorf("passing journal file: %w", err)
+	}
+
+	return nil
+}
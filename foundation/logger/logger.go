@@ -6,37 +6,67 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"strings"
 	"time"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+	"github.com/AlmirSai/service/foundation/version"
 )
 
 // TraceIDFn defines a function type for extracting a trace ID from the context.
 // Useful for correlating logs in distributed systems.
 type TraceIDFn func(ctx context.Context) string
 
+// ContextAttrsFn defines a function type for extracting attributes, such as
+// a span ID, from the context. They are added to every record.
+type ContextAttrsFn func(ctx context.Context) []slog.Attr
+
 // Logger is a structured logging wrapper around slog.Handler.
 // It supports trace ID injection, service name tagging, and custom event hooks.
 type Logger struct {
-	discard   bool         // Whether logs should be discarded (io.Discard)
-	handler   slog.Handler // Underlying slog handler
-	traceIDFn TraceIDFn    // Function to extract trace ID from context
+	discard   bool                      // Whether logs should be discarded (io.Discard)
+	handler   slog.Handler              // Underlying slog handler
+	traceIDFn TraceIDFn                 // Function to extract trace ID from context
+	ctxAttrFn ContextAttrsFn            // Function to extract more attributes from context
+	level     *slog.LevelVar            // Minimum level, adjustable at runtime
+	counters  *counters                 // Records written per level, when enabled
+	onFatal   func(ctx context.Context) // Called by Fatal before exiting
+	exitCode  int                       // Code Fatal exits with
 }
 
 // New creates a Logger with the given output, log level, service name, and optional trace ID function.
-func New(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn) *Logger {
-	return new(w, minLevel, serviceName, traceIDFn, Events{})
+func New(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, opts ...Option) *Logger {
+	return new(w, minLevel, serviceName, traceIDFn, Events{}, opts...)
 }
 
 // NewWithEvents creates a Logger with custom event hooks for different log levels.
-func NewWithEvents(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events) *Logger {
-	return new(w, minLevel, serviceName, traceIDFn, events)
+func NewWithEvents(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events, opts ...Option) *Logger {
+	return new(w, minLevel, serviceName, traceIDFn, events, opts...)
 }
 
 // NewWithHandler wraps an existing slog.Handler in a Logger.
 func NewWithHandler(h slog.Handler) *Logger {
+	c := counters{}
+
+	return &Logger{
+		handler:  &countingHandler{handler: h, counters: &c},
+		counters: &c,
+		exitCode: 1,
+	}
+}
+
+// NewNop creates a Logger that discards everything. It's a safe default for
+// library code and tests that don't care about the output.
+func NewNop() *Logger {
 	return &Logger{
-		handler: h,
+		discard:  true,
+		handler:  slog.DiscardHandler,
+		counters: &counters{},
+		exitCode: 1,
 	}
 }
 
@@ -46,6 +76,71 @@ func NewStdLogger(logger *Logger, level Level) *log.Logger {
 	return slog.NewLogLogger(logger.handler, slog.Level(level))
 }
 
+// With returns a child logger that adds the given key/value pairs to every
+// record it writes. The child shares the level of its parent, so SetLevel on
+// either changes both.
+func (log *Logger) With(args ...any) *Logger {
+	if len(args) == 0 {
+		return log
+	}
+
+	child := *log
+	child.handler = slog.New(log.handler).With(args...).Handler()
+	return &child
+}
+
+// SetLevel changes the minimum level of records written by the logger.
+// It is safe to call while the logger is in use. Loggers constructed with
+// NewWithHandler are controlled by their handler and ignore this call.
+func (log *Logger) SetLevel(level Level) {
+	if log.level == nil {
+		return
+	}
+	log.level.Set(slog.Level(level))
+}
+
+// SetMetrics starts counting the records written per level in the
+// log_records_total counter of the provider. It's meant for loggers created
+// before the provider, others can use WithMetrics.
+func (log *Logger) SetMetrics(provider metrics.Provider) {
+	if log.counters == nil {
+		return
+	}
+	log.counters.setProvider(provider)
+}
+
+// GetLevel returns the minimum level of records written by the logger. For
+// loggers constructed with NewWithHandler it reports the lowest standard
+// level the handler has enabled.
+func (log *Logger) GetLevel() Level {
+	if log.level != nil {
+		return Level(log.level.Level())
+	}
+
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn} {
+		if log.handler.Enabled(context.Background(), slog.Level(level)) {
+			return level
+		}
+	}
+	return LevelError
+}
+
+// Trace logs a trace-level message, for detail too noisy even for debug.
+func (log *Logger) Trace(ctx context.Context, msg string, args ...any) {
+	if log.discard {
+		return
+	}
+	log.write(ctx, LevelTrace, 3, msg, args...)
+}
+
+// Tracec logs a trace-level message with a custom caller skip depth.
+func (log *Logger) Tracec(ctx context.Context, caller int, msg string, args ...any) {
+	if log.discard {
+		return
+	}
+	log.write(ctx, LevelTrace, caller, msg, args...)
+}
+
 // Debug logs a debug-level message.
 func (log *Logger) Debug(ctx context.Context, msg string, args ...any) {
 	if log.discard {
@@ -110,6 +205,59 @@ func (log *Logger) Errorc(ctx context.Context, caller int, msg string, args ...a
 	log.write(ctx, LevelError, caller, msg, args...)
 }
 
+// ErrorWithStack logs an error-level message with the error and the stack of
+// the caller, so the error can be located without reproducing it.
+func (log *Logger) ErrorWithStack(ctx context.Context, msg string, err error, args ...any) {
+	if log.discard {
+		return
+	}
+	args = append(args, "error", err, "stack", callerStack(3))
+	log.write(ctx, LevelError, 3, msg, args...)
+}
+
+// Audit logs an audit-level message. The level sits above error so audit
+// records are kept at any level below fatal.
+func (log *Logger) Audit(ctx context.Context, msg string, args ...any) {
+	if log.discard {
+		return
+	}
+	log.write(ctx, LevelAudit, 3, msg, args...)
+}
+
+// Auditc logs an audit-level message with a custom caller skip depth.
+func (log *Logger) Auditc(ctx context.Context, caller int, msg string, args ...any) {
+	if log.discard {
+		return
+	}
+	log.write(ctx, LevelAudit, caller, msg, args...)
+}
+
+// Fatal logs a fatal-level message, runs the hook registered with
+// WithFatalHook, and exits the process. It never returns, so deferred
+// functions of the caller do not run.
+func (log *Logger) Fatal(ctx context.Context, msg string, args ...any) {
+	if !log.discard {
+		log.write(ctx, LevelFatal, 3, msg, args...)
+	}
+
+	if log.onFatal != nil {
+		log.onFatal(ctx)
+	}
+
+	os.Exit(log.exitCode)
+}
+
+// LogPanic logs a recovered panic value at error level along with the stack
+// of the goroutine that panicked. Call it from the deferred function that
+// recovered.
+func (log *Logger) LogPanic(ctx context.Context, rec any, msg string, args ...any) {
+	if log.discard {
+		return
+	}
+	args = append(args, "panic", fmt.Sprint(rec), "stack", string(debug.Stack()))
+	log.write(ctx, LevelError, 3, msg, args...)
+}
+
 // write creates and sends a log record to the handler.
 // - Adds trace ID if available
 // - Captures caller information based on the given depth
@@ -133,17 +281,39 @@ func (log *Logger) write(ctx context.Context, level Level, caller int, msg strin
 		args = append(args, "trace_id", log.traceIDFn(ctx))
 	}
 
-	// Add additional structured attributes
-	r.Add(args...)
+	// Append attributes taken from the context, like the span ID
+	if log.ctxAttrFn != nil {
+		for _, a := range log.ctxAttrFn(ctx) {
+			args = append(args, a)
+		}
+	}
+
+	// Add additional structured attributes, expanding errors with details
+	r.Add(expandErrors(args)...)
 
-	// Send the log record to the handler
+	// Send the log record to the handler, which counts it if it's written
 	log.handler.Handle(ctx, r)
 }
 
-// new initializes a Logger with JSON output, optional event hooks, and service tagging.
-func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events) *Logger {
+// new initializes a Logger with JSON, text, or console output, optional event hooks, and service tagging.
+func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, events Events, opts ...Option) *Logger {
+	o := options{
+		exitCode: 1,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// ReplaceAttr function to customize source file formatting
 	f := func(groups []string, a slog.Attr) slog.Attr {
+		// Name our own levels instead of letting slog call them DEBUG-4 and such
+		if a.Key == slog.LevelKey {
+			if level, ok := a.Value.Any().(slog.Level); ok {
+				if name, ok := levelNames[Level(level)]; ok {
+					return slog.String(slog.LevelKey, name)
+				}
+			}
+		}
 		if a.Key == slog.SourceKey {
 			if source, ok := a.Value.Any().(*slog.Source); ok {
 				// Use only the file name and line number
@@ -157,27 +327,165 @@ func new(w io.Writer, minLevel Level, serviceName string, traceIDFn TraceIDFn, e
 		return a
 	}
 
-	// Create a JSON handler with custom options
-	handler := slog.Handler(slog.NewJSONHandler(w, &slog.HandlerOptions{
-		AddSource:   true,
-		Level:       slog.Level(minLevel),
-		ReplaceAttr: f,
-	}))
+	// Keep the level in a LevelVar so it can be changed without rebuilding handlers
+	level := &slog.LevelVar{}
+	level.Set(slog.Level(minLevel))
+
+	// Create the handler for the main output, one for each extra output, and add the sinks
+	handler := newHandler(w, o.format, level, f)
+	if len(o.outputs) > 0 || len(o.sinks) > 0 {
+		handlers := []slog.Handler{handler}
+		for _, out := range o.outputs {
+			handlers = append(handlers, newHandler(out.w, o.format, slog.Level(out.minLevel), f))
+		}
+		handlers = append(handlers, o.sinks...)
+		handler = newMultiHandler(handlers...)
+	}
 
 	// Wrap handler with event hooks if provided
-	if events.Debug != nil || events.Info != nil || events.Warn != nil || events.Error != nil {
+	if events.Trace != nil || events.Debug != nil || events.Info != nil || events.Warn != nil || events.Error != nil || events.Audit != nil {
 		handler = newLogHandler(handler, events)
 	}
 
-	// Add service name as a constant log attribute
+	// Count inside the stages below so the records they drop aren't counted
+	c := counters{}
+	if o.metrics != nil {
+		c.setProvider(o.metrics)
+	}
+	handler = &countingHandler{handler: handler, counters: &c}
+
+	// Sample ahead of the event hooks so a flood doesn't reach them either
+	if o.sampling != nil {
+		handler = newSamplingHandler(handler, o.sampling.first, o.sampling.thereafter)
+	}
+
+	// Collapse duplicates before they are counted by the sampler
+	if o.dedup > 0 {
+		handler = newDedupHandler(handler, o.dedup)
+	}
+
+	// Redact first so secrets never reach hooks or any of the outputs
+	if o.redact != nil {
+		handler = newRedactHandler(handler, o.redact.keys, o.redact.patterns)
+	}
+
+	// Filter last so dropped records cost nothing further down
+	if len(o.filters) > 0 {
+		filters := o.filters
+		handler = Filter(handler, func(ctx context.Context, r Record) bool {
+			if r.Level == LevelAudit {
+				return true
+			}
+			for _, fn := range filters {
+				if !fn(ctx, r) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	// Add service name and version as constant log attributes
 	attrs := []slog.Attr{
 		{Key: "service", Value: slog.StringValue(serviceName)},
+		{Key: "version", Value: slog.StringValue(version.Version())},
+	}
+	if o.runtime {
+		attrs = append(attrs, runtimeAttrs(o.env)...)
 	}
 	handler = handler.WithAttrs(attrs)
 
 	return &Logger{
-		discard:   w == io.Discard,
+		discard:   w == io.Discard && len(o.outputs) == 0 && len(o.sinks) == 0,
 		handler:   handler,
 		traceIDFn: traceIDFn,
+		ctxAttrFn: o.ctxAttrFn,
+		level:     level,
+		counters:  &c,
+		onFatal:   o.onFatal,
+		exitCode:  o.exitCode,
 	}
-}
\ No newline at end of file
+}
+
+// maxStackFrames caps the frames reported by callerStack.
+const maxStackFrames = 32
+
+// callerStack returns the stack above the given depth as "function
+// file:line" entries, trimmed to maxStackFrames and without the runtime's
+// own frames.
+func callerStack(skip int) []string {
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(skip, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, filepath.Base(frame.File), frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// logfmtAttr wraps replaceAttr to follow the logfmt conventions of other
+// tools: the time goes in ts as UTC and the level is lowercase.
+func logfmtAttr(replaceAttr func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		a = replaceAttr(groups, a)
+		if len(groups) > 0 {
+			return a
+		}
+
+		switch a.Key {
+		case slog.TimeKey:
+			return slog.String("ts", a.Value.Time().UTC().Format(time.RFC3339Nano))
+		case slog.LevelKey:
+			return slog.String(slog.LevelKey, strings.ToLower(a.Value.String()))
+		}
+		return a
+	}
+}
+
+// runtimeAttrs returns the attributes describing the process and where it
+// is deployed.
+func runtimeAttrs(environment string) []slog.Attr {
+	hostname, _ := os.Hostname()
+
+	attrs := []slog.Attr{
+		slog.String("hostname", hostname),
+		slog.Int("pid", os.Getpid()),
+		slog.String("goversion", runtime.Version()),
+	}
+	if environment != "" {
+		attrs = append(attrs, slog.String("environment", environment))
+	}
+
+	return attrs
+}
+
+// newHandler creates a JSON, text, or console handler writing records at or
+// above the level to w.
+func newHandler(w io.Writer, format Format, level slog.Leveler, replaceAttr func([]string, slog.Attr) slog.Attr) slog.Handler {
+	opts := slog.HandlerOptions{
+		AddSource:   true,
+		Level:       level,
+		ReplaceAttr: replaceAttr,
+	}
+
+	switch format {
+	case TextFormat:
+		return slog.NewTextHandler(w, &opts)
+	case ConsoleFormat:
+		return newConsoleHandler(w, level)
+	case LogfmtFormat:
+		opts.ReplaceAttr = logfmtAttr(replaceAttr)
+		return slog.NewTextHandler(w, &opts)
+	default:
+		return slog.NewJSONHandler(w, &opts)
+	}
+}
@@ -0,0 +1,41 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/testutil"
+)
+
+// volatileLogfmt matches the values of the keys that differ between runs.
+var volatileLogfmt = regexp.MustCompile(`\b(ts|file|version)=\S+`)
+
+func Test_Logfmt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	traceID := func(ctx context.Context) string {
+		return "00000000000000000000000000000001"
+	}
+
+	log := logger.New(&buf, logger.LevelTrace, "TEST", traceID, logger.WithFormat(logger.LogfmtFormat))
+
+	ctx := context.Background()
+
+	log.Trace(ctx, "trace")
+	log.Debug(ctx, "debug", "count", 3)
+	log.Info(ctx, "startup", "status", "started", "took", 1500*time.Millisecond)
+	log.Warn(ctx, "quoting", "query", `name = "bill"`, "empty", "")
+	log.Error(ctx, "request failed", "err", errors.New("connection refused"))
+	log.With("req", "r1").Info(ctx, "with attributes", "path", "/v1/orders")
+
+	got := volatileLogfmt.ReplaceAll(buf.Bytes(), []byte("$1=<ignored>"))
+
+	testutil.Golden(t, "logfmt", got)
+}
@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -12,12 +15,74 @@ type Level slog.Level
 
 // Common log levels wrapped into our custom Level type.
 const (
+	LevelTrace = Level(slog.LevelDebug - 4)
 	LevelDebug = Level(slog.LevelDebug)
 	LevelInfo  = Level(slog.LevelInfo)
 	LevelWarn  = Level(slog.LevelWarn)
 	LevelError = Level(slog.LevelError)
+	LevelAudit = Level(slog.LevelError + 2)
+	LevelFatal = Level(slog.LevelError + 4)
 )
 
+// levelNames holds the names of the levels slog doesn't know about.
+var levelNames = map[Level]string{
+	LevelTrace: "TRACE",
+	LevelAudit: "AUDIT",
+	LevelFatal: "FATAL",
+}
+
+// String returns the name of the level, such as "DEBUG" or "WARN+2".
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return slog.Level(l).String()
+}
+
+// UnmarshalText parses a level name produced by String, so TRACE, AUDIT, and
+// FATAL are accepted along with everything slog.Level understands.
+func (l *Level) UnmarshalText(data []byte) error {
+	for level, name := range levelNames {
+		if strings.EqualFold(string(data), name) {
+			*l = level
+			return nil
+		}
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText(data); err != nil {
+		return err
+	}
+
+	*l = Level(lvl)
+	return nil
+}
+
+// ParseLevel returns the level with the given name, like "warn" or "DEBUG+2".
+// Names are case insensitive.
+func ParseLevel(name string) (Level, error) {
+	var l Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return LevelInfo, fmt.Errorf("parse level %q: %w", name, err)
+	}
+	return l, nil
+}
+
+// LevelFromEnv returns the level named by the environment variable key, or
+// def when the variable is unset or empty.
+func LevelFromEnv(key string, def Level) (Level, error) {
+	name := os.Getenv(key)
+	if name == "" {
+		return def, nil
+	}
+
+	l, err := ParseLevel(name)
+	if err != nil {
+		return def, fmt.Errorf("%s: %w", key, err)
+	}
+	return l, nil
+}
+
 // Record represents a structured log entry.
 // Unlike slog.Record, this struct can be easily stored, serialized, or sent over the network.
 type Record struct {
@@ -54,8 +119,10 @@ type EventFn func(ctx context.Context, r Record)
 // Events holds callbacks for different log levels.
 // This allows assigning custom behavior for each log level independently.
 type Events struct {
+	Trace EventFn // Called for trace-level logs
 	Debug EventFn // Called for debug-level logs
 	Info  EventFn // Called for info-level logs
 	Warn  EventFn // Called for warning-level logs
-	Error EventFn // Called for error-level logs
+	Error EventFn // Called for error-level and fatal-level logs
+	Audit EventFn // Called for audit-level logs
 }
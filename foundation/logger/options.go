@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/metrics"
+)
+
+// Format selects how log records are encoded.
+type Format int
+
+// Set of formats a logger can write.
+const (
+	JSONFormat    Format = iota // One JSON object per line, the default
+	TextFormat                  // key=value pairs for reading in a terminal
+	ConsoleFormat               // Colored, column aligned lines for local development
+	LogfmtFormat                // logfmt with ts and a lowercase level, for Loki and the like
+)
+
+// ParseFormat returns the format with the given name: json, text, console,
+// or logfmt.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "json":
+		return JSONFormat, nil
+	case "text":
+		return TextFormat, nil
+	case "console":
+		return ConsoleFormat, nil
+	case "logfmt":
+		return LogfmtFormat, nil
+	}
+	return JSONFormat, fmt.Errorf("unknown log format %q", name)
+}
+
+// options holds the settings that can be changed through an Option.
+type options struct {
+	format    Format
+	outputs   []output
+	sinks     []slog.Handler
+	ctxAttrFn ContextAttrsFn
+	metrics   metrics.Provider
+	runtime   bool
+	env       string
+	sampling  *sampling
+	dedup     time.Duration
+	redact    *redaction
+	filters   []FilterFn
+	onFatal   func(ctx context.Context)
+	exitCode  int
+}
+
+// sampling holds the settings added with WithSampling.
+type sampling struct {
+	first      int
+	thereafter int
+}
+
+// redaction holds the settings added with WithRedaction.
+type redaction struct {
+	keys     []string
+	patterns []*regexp.Regexp
+}
+
+// output is an extra destination added with WithOutput.
+type output struct {
+	w        io.Writer
+	minLevel Level
+}
+
+// Option changes how New and NewWithEvents construct a logger.
+type Option func(*options)
+
+// WithFormat sets the encoding of the log records. It is meant for local
+// development, production logs should stay in the default JSON format.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithFatalHook registers a function Fatal calls after writing the record and
+// before exiting, to flush hooks and close writers.
+func WithFatalHook(fn func(ctx context.Context)) Option {
+	return func(o *options) {
+		o.onFatal = fn
+	}
+}
+
+// WithExitCode sets the code Fatal exits the process with, 1 by default.
+func WithExitCode(code int) Option {
+	return func(o *options) {
+		o.exitCode = code
+	}
+}
+
+// WithOutput adds a destination that receives the records at or above
+// minLevel, in the same format as the main writer. The level of an extra
+// output is fixed, SetLevel only changes the level of the main writer.
+func WithOutput(w io.Writer, minLevel Level) Option {
+	return func(o *options) {
+		o.outputs = append(o.outputs, output{w: w, minLevel: minLevel})
+	}
+}
+
+// WithSink adds a handler that receives the records next to the writers,
+// such as an exporter to a log collector. The handler decides on its own
+// which levels it accepts.
+func WithSink(h slog.Handler) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, h)
+	}
+}
+
+// WithContextAttrs sets a function whose attributes are added to every
+// record next to the trace ID, for example the span ID of the active span.
+func WithContextAttrs(fn ContextAttrsFn) Option {
+	return func(o *options) {
+		o.ctxAttrFn = fn
+	}
+}
+
+// WithRuntimeAttrs tags every record with the hostname, pid, and Go version
+// of the process and the deployment environment, such as dev, stage, or
+// prod. The environment is left out when empty.
+func WithRuntimeAttrs(environment string) Option {
+	return func(o *options) {
+		o.runtime = true
+		o.env = environment
+	}
+}
+
+// WithMetrics counts the records written per level in the log_records_total
+// counter of the provider, so dashboards can alert on the error rate.
+func WithMetrics(provider metrics.Provider) Option {
+	return func(o *options) {
+		o.metrics = provider
+	}
+}
+
+// WithSampling limits how many records of the same level and message are
+// written per second: the first are all written, after that only one in
+// every thereafter. Event hooks only see the records that are written.
+// Audit records are never sampled.
+func WithSampling(first int, thereafter int) Option {
+	return func(o *options) {
+		o.sampling = &sampling{first: first, thereafter: thereafter}
+	}
+}
+
+// WithDedup holds back records with the same level and message as one
+// written less than window ago. When the window closes the last copy is
+// written once with a repeated attribute counting the copies held back. A
+// summary still pending when the process exits is lost. Audit records are
+// never held back.
+func WithDedup(window time.Duration) Option {
+	return func(o *options) {
+		o.dedup = window
+	}
+}
+
+// WithRedaction replaces the values of attributes with the given keys, and
+// the parts of string values matching any of the patterns, with
+// "[REDACTED]" before records are written. DefaultRedactKeys holds the
+// usual keys.
+func WithRedaction(keys []string, patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		o.redact = &redaction{keys: keys, patterns: patterns}
+	}
+}
+
+// WithFilter drops the records fn doesn't keep before they reach any output,
+// sink, or event hook, such as the request logs of a noisy endpoint. When
+// added more than once a record must be kept by every function. Audit
+// records are never filtered.
+func WithFilter(fn FilterFn) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, fn)
+	}
+}
@@ -0,0 +1,225 @@
+// Package otlp provides a slog.Handler that exports log records over OTLP to
+// an OpenTelemetry collector. It is meant to be added as a second sink next
+// to the regular writer with logger.WithSink.
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+// Config defines the information needed to export logs.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string       // Defaults to the version of the binary
+	Host           string       // Collector host:port
+	Protocol       string       // grpc or http, defaults to grpc
+	Secure         bool         // Use TLS to reach the collector
+	MinLevel       logger.Level // Records below the level are not exported
+}
+
+// Handler is a slog.Handler exporting records through an OpenTelemetry
+// logger provider. Records are batched and sent in the background.
+type Handler struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+	level    slog.Level
+	attrs    []attribute.KeyValue
+	group    string // Key prefix of the open groups, like "req."
+}
+
+// New constructs a Handler exporting to the collector. Call Shutdown before
+// the process exits to flush the records still buffered.
+func New(ctx context.Context, cfg Config) (*Handler, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating new exporter: %w", err)
+	}
+
+	if cfg.ServiceVersion == "" {
+		cfg.ServiceVersion = version.Version()
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+
+	h := Handler{
+		provider: provider,
+		logger:   provider.Logger("github.com/AlmirSai/service/foundation/logger"),
+		level:    slog.Level(cfg.MinLevel),
+	}
+
+	return &h, nil
+}
+
+// newExporter creates the OTLP exporter for the configured protocol.
+func newExporter(ctx context.Context, cfg Config) (sdklog.Exporter, error) {
+	switch cfg.Protocol {
+	case "", "grpc":
+		opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(cfg.Host)}
+		if !cfg.Secure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		return otlploggrpc.New(ctx, opts...)
+
+	case "http":
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(cfg.Host)}
+		if !cfg.Secure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+
+	return nil, fmt.Errorf("unknown protocol %q", cfg.Protocol)
+}
+
+// Shutdown exports the records still buffered and stops the exporter.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	return h.provider.Shutdown(ctx)
+}
+
+// Enabled checks whether the given log level is exported.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.level {
+		return false
+	}
+	return h.logger.Enabled(ctx, otellog.EnabledParameters{Severity: severity(level)})
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]attribute.KeyValue, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(h2.attrs, h.attrs)
+
+	for _, a := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.group, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle converts the record and hands it to the batch processor. The span
+// in the context, if any, is attached to the record by the SDK.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var rec otellog.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetSeverity(severity(r.Level))
+	rec.SetSeverityText(logger.Level(r.Level).String())
+	rec.SetBody(attribute.StringValue(r.Message))
+
+	attrs := make([]attribute.KeyValue, len(h.attrs), len(h.attrs)+r.NumAttrs()+1)
+	copy(attrs, h.attrs)
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		attrs = append(attrs, attribute.String("file", fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)))
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, h.group, a)
+		return true
+	})
+	rec.AddAttributes(attrs...)
+
+	h.logger.Emit(ctx, rec)
+
+	return nil
+}
+
+// severity maps a slog level onto the OpenTelemetry severity numbers, which
+// put DEBUG, INFO, WARN, and ERROR 9 above the slog values.
+func severity(level slog.Level) otellog.Severity {
+	s := int(level) + 9
+	switch {
+	case s < int(otellog.SeverityTrace1):
+		return otellog.SeverityTrace1
+	case s > int(otellog.SeverityFatal4):
+		return otellog.SeverityFatal4
+	}
+	return otellog.Severity(s)
+}
+
+// appendAttr converts the attribute, expanding groups into dotted keys.
+func appendAttr(attrs []attribute.KeyValue, prefix string, a slog.Attr) []attribute.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	key := prefix + a.Key
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, prefix, ga)
+		}
+		return attrs
+
+	case slog.KindBool:
+		return append(attrs, attribute.Bool(key, a.Value.Bool()))
+
+	case slog.KindInt64:
+		return append(attrs, attribute.Int64(key, a.Value.Int64()))
+
+	case slog.KindUint64:
+		return append(attrs, attribute.Int64(key, int64(a.Value.Uint64())))
+
+	case slog.KindFloat64:
+		return append(attrs, attribute.Float64(key, a.Value.Float64()))
+
+	case slog.KindDuration:
+		return append(attrs, attribute.String(key, a.Value.Duration().String()))
+
+	case slog.KindTime:
+		return append(attrs, attribute.String(key, a.Value.Time().Format(time.RFC3339Nano)))
+	}
+
+	return append(attrs, attribute.String(key, a.Value.String()))
+}
@@ -0,0 +1,61 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// JetStream is a Publisher sending messages to a NATS JetStream subject. The
+// message ID is set as Nats-Msg-Id, so the stream drops a message published
+// again within its duplicate window. A stream must capture the subject.
+type JetStream struct {
+	js      jetstream.JetStream
+	subject string
+}
+
+// NewJetStream constructs a JetStream publisher for the subject.
+func NewJetStream(js jetstream.JetStream, subject string) *JetStream {
+	return &JetStream{
+		js:      js,
+		subject: subject,
+	}
+}
+
+// Publish implements the Publisher interface. The messages are published
+// asynchronously and the acknowledgements awaited in order.
+func (p *JetStream) Publish(ctx context.Context, msgs []Message) (int, error) {
+	futures := make([]jetstream.PubAckFuture, 0, len(msgs))
+
+	var sendErr error
+
+	for _, m := range msgs {
+		msg := nats.NewMsg(p.subject)
+		msg.Data = m.Data
+
+		f, err := p.js.PublishMsgAsync(msg, jetstream.WithMsgID(m.ID))
+		if err != nil {
+			sendErr = err
+			break
+		}
+		futures = append(futures, f)
+	}
+
+	for i, f := range futures {
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			return i, fmt.Errorf("publish: %w", err)
+		case <-ctx.Done():
+			return i, ctx.Err()
+		}
+	}
+
+	if sendErr != nil {
+		return len(futures), fmt.Errorf("publish: %w", sendErr)
+	}
+
+	return len(msgs), nil
+}
@@ -0,0 +1,315 @@
+// Package publish provides a slog.Handler that publishes log records to a
+// message broker, such as a NATS JetStream subject or a Kafka topic, so the
+// log pipeline doesn't depend on scraping stdout. It is meant to be added
+// next to the regular writer with logger.WithSink.
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/id"
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/retry"
+)
+
+// Message is a log record serialized as a JSON object. The ID stays the same
+// when the message is published again, so brokers and consumers can drop
+// the duplicates.
+type Message struct {
+	ID   string
+	Time time.Time
+	Data []byte
+}
+
+// Publisher delivers messages to a broker. Publish returns how many messages
+// from the start of the slice the broker acknowledged, the rest are published
+// again later. A Kafka producer only needs a small adapter to satisfy it,
+// using the ID as the record key.
+type Publisher interface {
+	Publish(ctx context.Context, msgs []Message) (int, error)
+}
+
+// Config holds the options for constructing a Handler.
+type Config struct {
+	Publisher     Publisher
+	MinLevel      logger.Level  // Records below the level are not published
+	FlushInterval time.Duration // Maximum time a record waits, defaults to 1s
+	MaxBatch      int           // Messages per Publish call, defaults to 500
+	MaxQueue      int           // Messages held while the broker is down, defaults to 10000, newer ones are dropped
+}
+
+// Handler is a slog.Handler queueing records for a broker. Handle never
+// blocks on the network. Messages stay queued until the broker acknowledges
+// them, so every record is delivered at least once unless the queue fills
+// up or the process exits first.
+type Handler struct {
+	shipper *shipper
+	level   slog.Level
+	attrs   map[string]any
+	group   string // Key prefix of the open groups, like "req."
+}
+
+// shipper is the queue and the publishing goroutine shared by a handler and
+// its children.
+type shipper struct {
+	cfg Config
+
+	mu      sync.Mutex
+	queue   []Message
+	dropped int
+
+	wake chan struct{}
+	done chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// New constructs a Handler and starts the goroutine publishing the records.
+// Call Shutdown to publish what's still queued and stop it.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = 500
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = 10_000
+	}
+
+	s := shipper{
+		cfg:  cfg,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+
+	go s.run()
+
+	h := Handler{
+		shipper: &s,
+		level:   slog.Level(cfg.MinLevel),
+		attrs:   map[string]any{},
+	}
+
+	return &h, nil
+}
+
+// Shutdown publishes the queued records and stops the handler. It returns
+// when done or when the context is canceled, and reports the records the
+// broker never acknowledged. Records handled afterwards are dropped.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	s := h.shipper
+
+	s.once.Do(func() {
+		close(s.stop)
+	})
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.queue) + s.dropped; n > 0 {
+		return fmt.Errorf("%d log records not published", n)
+	}
+
+	return nil
+}
+
+// Enabled checks whether the given log level is published.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make(map[string]any, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		logger.FlattenAttr(h2.attrs, h.group, ".", a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle serializes the record and queues it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	rec := make(map[string]any, len(h.attrs)+r.NumAttrs()+4)
+	for k, v := range h.attrs {
+		rec[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		logger.FlattenAttr(rec, h.group, ".", a)
+		return true
+	})
+
+	rec["time"] = r.Time.UTC().Format(time.RFC3339Nano)
+	rec["level"] = logger.Level(r.Level).String()
+	rec["msg"] = r.Message
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		rec["file"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding log record: %w", err)
+	}
+
+	h.shipper.enqueue(Message{
+		ID:   id.New().String(),
+		Time: r.Time,
+		Data: data,
+	})
+
+	return nil
+}
+
+// =============================================================================
+
+// enqueue adds the message to the queue and wakes the goroutine once a
+// batch is full.
+func (s *shipper) enqueue(msg Message) {
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	s.mu.Lock()
+	if len(s.queue) >= s.cfg.MaxQueue {
+		s.dropped++
+		s.mu.Unlock()
+		return
+	}
+	s.queue = append(s.queue, msg)
+	full := len(s.queue) >= s.cfg.MaxBatch
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run publishes the queued messages every flush interval, or sooner when a
+// batch is full.
+func (s *shipper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-s.stop:
+			s.flush()
+			return
+		}
+
+		s.flush()
+	}
+}
+
+// flush publishes batches until the queue is empty or the broker keeps
+// failing, in which case the rest waits for the next flush. Failures are
+// not logged, logging them would feed the handler that is failing.
+func (s *shipper) flush() {
+	s.noteDropped()
+
+	cfg := retry.Config{
+		Name:        "publish",
+		MaxAttempts: 3,
+		MaxDelay:    time.Second,
+	}
+
+	for {
+		s.mu.Lock()
+		batch := make([]Message, min(len(s.queue), s.cfg.MaxBatch))
+		copy(batch, s.queue)
+		s.mu.Unlock()
+
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := retry.Do(ctx, cfg, func(ctx context.Context) error {
+			n, err := s.cfg.Publisher.Publish(ctx, batch)
+			n = max(0, min(n, len(batch)))
+
+			// Only this goroutine removes messages, so the head of the queue
+			// is still the batch.
+			s.mu.Lock()
+			s.queue = s.queue[n:]
+			s.mu.Unlock()
+
+			batch = batch[n:]
+			if err == nil && len(batch) > 0 {
+				return errors.New("publisher skipped messages")
+			}
+			return err
+		})
+		cancel()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// noteDropped queues a warning counting the records dropped while the queue
+// was full, once there is room for it again.
+func (s *shipper) noteDropped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dropped == 0 || len(s.queue) >= s.cfg.MaxQueue {
+		return
+	}
+
+	now := time.Now()
+	s.queue = append(s.queue, Message{
+		ID:   id.New().String(),
+		Time: now,
+		Data: fmt.Appendf(nil, `{"time":%q,"level":"WARN","msg":"log records dropped, publish queue full","count":%d}`,
+			now.UTC().Format(time.RFC3339Nano), s.dropped),
+	})
+	s.dropped = 0
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values of sensitive attributes.
+const redacted = "[REDACTED]"
+
+// DefaultRedactKeys are attribute keys that commonly hold secrets.
+var DefaultRedactKeys = []string{
+	"password",
+	"authorization",
+	"token",
+	"secret",
+	"card_number",
+}
+
+// redactor holds the keys and patterns of the values to redact.
+type redactor struct {
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// redactHandler is a slog.Handler that redacts sensitive attributes before
+// passing records on. Values of matching keys are replaced as a whole, and
+// parts of string values matching a pattern are replaced in place. Groups are
+// walked, so a key nested in a group is redacted as well.
+type redactHandler struct {
+	handler  slog.Handler
+	redactor *redactor
+}
+
+// newRedactHandler wraps the handler with redaction. Keys are matched
+// without regard to case.
+func newRedactHandler(handler slog.Handler, keys []string, patterns []*regexp.Regexp) *redactHandler {
+	r := redactor{
+		keys:     make(map[string]bool, len(keys)),
+		patterns: patterns,
+	}
+	for _, k := range keys {
+		r.keys[strings.ToLower(k)] = true
+	}
+
+	return &redactHandler{
+		handler:  handler,
+		redactor: &r,
+	}
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with the attributes redacted and attached.
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = h.redactor.redact(a)
+	}
+
+	return &redactHandler{
+		handler:  h.handler.WithAttrs(clean),
+		redactor: h.redactor,
+	}
+}
+
+// WithGroup returns a new handler that groups all attributes under the given name.
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{
+		handler:  h.handler.WithGroup(name),
+		redactor: h.redactor,
+	}
+}
+
+// Handle passes on a copy of the record with its attributes redacted.
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(h.redactor.redact(a))
+		return true
+	})
+
+	return h.handler.Handle(ctx, clean)
+}
+
+// redact returns the attribute with sensitive values replaced.
+func (r *redactor) redact(a slog.Attr) slog.Attr {
+	if r.keys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		clean := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			clean[i] = r.redact(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(clean...)}
+
+	case slog.KindString:
+		s := a.Value.String()
+		for _, p := range r.patterns {
+			s = p.ReplaceAllString(s, redacted)
+		}
+		return slog.String(a.Key, s)
+	}
+
+	return a
+}
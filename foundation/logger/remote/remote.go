@@ -0,0 +1,312 @@
+// Package remote provides a writer that streams log lines to a collector
+// over TCP or UDP. While the collector is unreachable the lines are spilled
+// to a file and sent once the connection is back. It is meant to be used as
+// the writer of a logger or added with logger.WithOutput.
+package remote
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/retry"
+)
+
+// Config holds the options for constructing a Writer.
+type Config struct {
+	Addr         string        // Collector host:port
+	Network      string        // tcp or udp, defaults to tcp
+	DialTimeout  time.Duration // Defaults to 5s
+	WriteTimeout time.Duration // Defaults to 1s
+	MinBackoff   time.Duration // First delay between reconnects, defaults to 100ms
+	MaxBackoff   time.Duration // Longest delay between reconnects, defaults to 30s
+	SpillFile    string        // Lines are dropped while disconnected when empty
+	MaxSpill     int64         // Largest spill file in bytes, defaults to 100MB
+}
+
+// Writer is an io.Writer sending every write as one line to the collector.
+// A failed write closes the connection and a background goroutine dials
+// again with backoff. Until the spilled lines are sent, new lines are
+// appended to the spill file to keep their order. Lines left in the file
+// when the process exits are sent by the next Writer using it.
+type Writer struct {
+	cfg Config
+
+	mu       sync.Mutex
+	conn     net.Conn
+	spill    *os.File
+	spilled  int64 // Bytes in the spill file
+	replayed int64 // Bytes of the spill file already sent
+	dropped  int
+
+	wake   chan struct{}
+	done   chan struct{}
+	cancel context.CancelFunc
+}
+
+// New constructs a Writer and connects to the collector. An unreachable
+// collector isn't an error, the lines are spilled until it's reachable.
+func New(cfg Config) (*Writer, error) {
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+	}
+	if cfg.Network != "tcp" && cfg.Network != "udp" {
+		return nil, fmt.Errorf("unknown network %q", cfg.Network)
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = time.Second
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = 100 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	if cfg.MaxSpill <= 0 {
+		cfg.MaxSpill = 100 << 20
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := Writer{
+		cfg:    cfg,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	if cfg.SpillFile != "" {
+		f, err := os.OpenFile(cfg.SpillFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("opening spill file: %w", err)
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			cancel()
+			return nil, fmt.Errorf("opening spill file: %w", err)
+		}
+
+		w.spill = f
+		w.spilled = info.Size()
+	}
+
+	// Lines spilled by a previous process are sent by the goroutine before
+	// any new line goes out directly.
+	if w.spilled == 0 {
+		if conn, err := w.dial(); err == nil {
+			w.conn = conn
+		}
+	}
+
+	go w.run(ctx)
+
+	if w.conn == nil {
+		w.reconnect()
+	}
+
+	return &w, nil
+}
+
+// Write sends the line, or spills it when the collector is unreachable. It
+// only fails when the line can't be sent nor spilled.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		w.conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteTimeout))
+		if _, err := w.conn.Write(p); err == nil {
+			return len(p), nil
+		}
+
+		w.conn.Close()
+		w.conn = nil
+		w.reconnect()
+	}
+
+	if err := w.spillLine(p); err != nil {
+		w.dropped++
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close stops reconnecting and closes the connection and the spill file.
+// Lines still in the spill file stay there for the next Writer.
+func (w *Writer) Close() error {
+	w.cancel()
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	if w.conn != nil {
+		errs = append(errs, w.conn.Close())
+		w.conn = nil
+	}
+	if w.spill != nil {
+		errs = append(errs, w.spill.Close())
+		w.spill = nil
+	}
+
+	return errors.Join(errs...)
+}
+
+// =============================================================================
+
+// spillLine appends the line to the spill file. The caller must hold w.mu.
+func (w *Writer) spillLine(p []byte) error {
+	if w.spill == nil {
+		return errors.New("collector unreachable")
+	}
+	if w.spilled+int64(len(p)) > w.cfg.MaxSpill {
+		return errors.New("collector unreachable, spill file full")
+	}
+
+	n, err := w.spill.Write(p)
+	w.spilled += int64(n)
+	if err != nil {
+		return fmt.Errorf("spilling: %w", err)
+	}
+
+	return nil
+}
+
+// reconnect wakes the goroutine dialing the collector.
+func (w *Writer) reconnect() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run connects to the collector whenever the connection is lost, backing
+// off between attempts until it succeeds or the writer is closed.
+func (w *Writer) run(ctx context.Context) {
+	defer close(w.done)
+
+	cfg := retry.Config{
+		Name:         "remote log writer",
+		MaxAttempts:  -1,
+		InitialDelay: w.cfg.MinBackoff,
+		MaxDelay:     w.cfg.MaxBackoff,
+	}
+
+	for {
+		select {
+		case <-w.wake:
+		case <-ctx.Done():
+			return
+		}
+
+		retry.Do(ctx, cfg, w.connect)
+	}
+}
+
+// connect dials the collector, sends the spilled lines, and switches the
+// writer to the new connection.
+func (w *Writer) connect(ctx context.Context) error {
+	w.mu.Lock()
+	connected := w.conn != nil
+	w.mu.Unlock()
+
+	if connected {
+		return nil
+	}
+
+	conn, err := w.dial()
+	if err != nil {
+		return err
+	}
+
+	if err := w.replay(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	return nil
+}
+
+// replay sends the spilled lines over the connection. Lines keep being
+// spilled meanwhile, the connection only takes over once the file is sent.
+func (w *Writer) replay(conn net.Conn) error {
+	for {
+		w.mu.Lock()
+		off, end := w.replayed, w.spilled
+		if off == end {
+			if w.spill != nil && end > 0 {
+				if err := w.spill.Truncate(0); err != nil {
+					w.mu.Unlock()
+					return fmt.Errorf("truncating spill file: %w", err)
+				}
+			}
+			w.spilled, w.replayed = 0, 0
+			w.conn = conn
+			w.writeDropped()
+			w.mu.Unlock()
+			return nil
+		}
+		w.mu.Unlock()
+
+		r := bufio.NewReader(io.NewSectionReader(w.spill, off, end-off))
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteTimeout))
+				if _, err := conn.Write(line); err != nil {
+					return fmt.Errorf("sending spilled lines: %w", err)
+				}
+
+				w.mu.Lock()
+				w.replayed += int64(len(line))
+				w.mu.Unlock()
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("reading spill file: %w", err)
+			}
+		}
+	}
+}
+
+// writeDropped sends a line counting the lines dropped while disconnected.
+// The caller must hold w.mu.
+func (w *Writer) writeDropped() {
+	if w.dropped == 0 {
+		return
+	}
+
+	line := fmt.Appendf(nil, `{"time":%q,"level":"WARN","msg":"log lines dropped, collector unreachable","count":%d}`+"\n",
+		time.Now().UTC().Format(time.RFC3339Nano), w.dropped)
+
+	w.conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteTimeout))
+	if _, err := w.conn.Write(line); err == nil {
+		w.dropped = 0
+	}
+}
+
+// dial connects to the collector.
+func (w *Writer) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout(w.cfg.Network, w.cfg.Addr, w.cfg.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("dialing collector: %w", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,301 @@
+// Package rotate provides an io.Writer for log files that rotates the file by
+// size and age and prunes old backups, so services writing logs to disk don't
+// fill their volumes.
+package rotate
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupLayout is the time format added to the names of rotated files.
+const backupLayout = "2006-01-02T15-04-05.000"
+
+// Config holds the options for constructing a Writer.
+type Config struct {
+	Filename   string        // File the logs are written to
+	MaxSize    int64         // Rotate once the file reaches this many bytes, 0 disables
+	Every      time.Duration // Rotate once the file has been open this long, 0 disables
+	MaxAge     time.Duration // Remove backups older than this, 0 keeps them
+	MaxBackups int           // Number of backups to keep, 0 keeps them all
+	Compress   bool          // Gzip the backups
+}
+
+// Writer writes to a file and rotates it according to its Config. It is safe
+// for concurrent use.
+type Writer struct {
+	cfg Config
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// Compressing and pruning backups happens in the background so a
+	// rotation doesn't stall the writers.
+	mill sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// New constructs a Writer, appending to the file if it already exists.
+func New(cfg Config) (*Writer, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("filename is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Filename), 0o755); err != nil {
+		return nil, fmt.Errorf("creating log dir: %w", err)
+	}
+
+	w := Writer{
+		cfg: cfg,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// Write writes p to the file, rotating it first when p would take the file
+// past MaxSize or the file is older than Every.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.due(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Rotate closes the current file, moves it aside as a backup, and opens a
+// new one. It can be called from a signal handler to rotate on demand.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+
+	return w.rotate()
+}
+
+// Close closes the file and waits for backups being compressed or pruned.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	w.wg.Wait()
+
+	return err
+}
+
+// due reports whether the file has to be rotated before writing n bytes. A
+// single write larger than MaxSize goes to an empty file instead of
+// rotating forever.
+func (w *Writer) due(n int64) bool {
+	if w.cfg.MaxSize > 0 && w.size > 0 && w.size+n > w.cfg.MaxSize {
+		return true
+	}
+
+	if w.cfg.Every > 0 && time.Since(w.openedAt) >= w.cfg.Every {
+		return true
+	}
+
+	return false
+}
+
+// open opens the file for appending.
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+
+	return nil
+}
+
+// rotate does the work of Rotate. The caller must hold w.mu.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("closing log file: %w", err)
+	}
+	w.file = nil
+
+	// Two rotations within the same millisecond would share a name.
+	now := time.Now()
+	backup := w.backupName(now)
+	for exists(backup) || exists(backup+".gz") {
+		now = now.Add(time.Millisecond)
+		backup = w.backupName(now)
+	}
+
+	if err := os.Rename(w.cfg.Filename, backup); err != nil {
+		return fmt.Errorf("renaming log file: %w", err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.wg.Go(func() {
+		w.mill.Lock()
+		defer w.mill.Unlock()
+
+		if w.cfg.Compress {
+			compress(backup)
+		}
+		w.prune()
+	})
+
+	return nil
+}
+
+// backupName returns the name of a backup rotated at t, like
+// sales-2024-05-01T10-00-00.000.log for sales.log.
+func (w *Writer) backupName(t time.Time) string {
+	dir, base := filepath.Split(w.cfg.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.UTC().Format(backupLayout), ext))
+}
+
+// exists reports whether a file is present at path.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// backup is a rotated file found on disk.
+type backup struct {
+	path string
+	at   time.Time
+}
+
+// backups returns the rotated files of the writer, newest first.
+func (w *Writer) backups() []backup {
+	dir, base := filepath.Split(w.cfg.Filename)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var list []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ext)
+		at, err := time.Parse(backupLayout, strings.TrimPrefix(stamp, prefix))
+		if err != nil {
+			continue
+		}
+
+		list = append(list, backup{path: filepath.Join(dir, name), at: at})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].at.After(list[j].at)
+	})
+
+	return list
+}
+
+// prune removes the backups beyond MaxBackups and those older than MaxAge.
+// Failures are ignored, the next rotation tries again.
+func (w *Writer) prune() {
+	cutoff := time.Now().Add(-w.cfg.MaxAge)
+
+	for i, b := range w.backups() {
+		tooMany := w.cfg.MaxBackups > 0 && i >= w.cfg.MaxBackups
+		tooOld := w.cfg.MaxAge > 0 && b.at.Before(cutoff)
+
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// compress gzips the file and removes the original. The original is kept
+// when compressing fails.
+func compress(path string) {
+	if err := gzipFile(path, path+".gz"); err != nil {
+		os.Remove(path + ".gz")
+		return
+	}
+	os.Remove(path)
+}
+
+// gzipFile writes a gzip compressed copy of src to dst.
+func gzipFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// samplerSlots is the number of counters records are hashed into. Messages
+// sharing a slot are sampled together, which is acceptable for a guard
+// against floods.
+const samplerSlots = 4096
+
+// samplerCounter counts the records of one slot in the current second.
+type samplerCounter struct {
+	second  int64
+	count   int
+	dropped int
+}
+
+// sampler holds the counters shared by a sampling handler and its children.
+type sampler struct {
+	first      int
+	thereafter int
+
+	mu       sync.Mutex
+	counters [samplerSlots]samplerCounter
+}
+
+// samplingHandler is a slog.Handler that lets the first records of each
+// level and message through every second and then only one in every
+// thereafter, so a misbehaving loop can't flood the log pipeline. Records
+// that pass once sampling started carry sampled=true and the number of
+// records dropped since the previous one.
+type samplingHandler struct {
+	handler slog.Handler
+	sampler *sampler
+}
+
+// newSamplingHandler wraps the handler with sampling. A thereafter below one
+// drops everything past the first records.
+func newSamplingHandler(handler slog.Handler, first int, thereafter int) *samplingHandler {
+	return &samplingHandler{
+		handler: handler,
+		sampler: &sampler{
+			first:      first,
+			thereafter: thereafter,
+		},
+	}
+}
+
+// Enabled checks whether the given log level is enabled for this handler.
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+// The counters are shared with the parent.
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{
+		handler: h.handler.WithAttrs(attrs),
+		sampler: h.sampler,
+	}
+}
+
+// WithGroup returns a new handler that groups all attributes under the given name.
+// The counters are shared with the parent.
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{
+		handler: h.handler.WithGroup(name),
+		sampler: h.sampler,
+	}
+}
+
+// Handle passes the record on when the sampler keeps it. Audit records are
+// always kept, a gap in the audit trail is not acceptable.
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if Level(r.Level) == LevelAudit {
+		return h.handler.Handle(ctx, r)
+	}
+
+	keep, sampled, dropped := h.sampler.check(r)
+	if !keep {
+		return nil
+	}
+
+	if sampled {
+		r = r.Clone()
+		r.AddAttrs(slog.Bool("sampled", true), slog.Int("dropped", dropped))
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// check decides whether the record is kept. It reports whether sampling was
+// in effect and how many records were dropped since the last kept one.
+func (s *sampler) check(r slog.Record) (keep bool, sampled bool, dropped int) {
+	hash := fnv.New32a()
+	hash.Write([]byte(Level(r.Level).String()))
+	hash.Write([]byte(r.Message))
+
+	second := r.Time.Unix()
+	if r.Time.IsZero() {
+		second = time.Now().Unix()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := &s.counters[hash.Sum32()%samplerSlots]
+	if c.second != second {
+		c.second = second
+		c.count = 0
+	}
+	c.count++
+
+	switch {
+	case c.count <= s.first:
+		// Report the drops of the previous seconds on the first record kept.
+		if c.dropped == 0 {
+			return true, false, 0
+		}
+
+	case s.thereafter > 0 && (c.count-s.first)%s.thereafter == 0:
+
+	default:
+		c.dropped++
+		return false, false, 0
+	}
+
+	dropped = c.dropped
+	c.dropped = 0
+
+	return true, true, dropped
+}
@@ -0,0 +1,326 @@
+// Package syslog provides a slog.Handler that writes RFC 5424 messages to a
+// local or remote syslog endpoint. The attributes of a record travel as
+// structured data. It is meant to be added next to the regular writer with
+// logger.WithSink.
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// timeLayout is RFC 3339 with the microsecond precision RFC 5424 allows.
+const timeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// Facility is the syslog facility messages are tagged with.
+type Facility int
+
+// Set of facilities commonly used by applications.
+const (
+	FacilityUser   Facility = 1
+	FacilityDaemon Facility = 3
+	FacilityLocal0 Facility = 16
+	FacilityLocal7 Facility = 23
+)
+
+// Config holds the options for constructing a Handler.
+type Config struct {
+	Network  string       // unixgram, unix, udp, or tcp; empty dials the local socket
+	Addr     string       // host:port, or the socket path, defaults to /dev/log
+	AppName  string       // APP-NAME of the messages
+	Facility Facility     // Defaults to FacilityUser
+	SDID     string       // SD-ID of the structured data, defaults to attrs@32473
+	MinLevel logger.Level // Records below the level are not written
+	Timeout  time.Duration
+}
+
+// Handler is a slog.Handler writing RFC 5424 messages.
+type Handler struct {
+	conn  *conn
+	level slog.Level
+	attrs []param
+	group string // Key prefix of the open groups, like "req."
+}
+
+// param is a single SD-PARAM of the structured data.
+type param struct {
+	name  string
+	value string
+}
+
+// conn is the connection shared by a handler and its children.
+type conn struct {
+	cfg      Config
+	hostname string
+	procID   string
+
+	mu sync.Mutex
+	c  net.Conn
+}
+
+// New constructs a Handler and connects to the endpoint. When a write fails
+// the connection is dialed again and the write retried once.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Network == "" {
+		cfg.Network = "unixgram"
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = "/dev/log"
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = FacilityUser
+	}
+	if cfg.SDID == "" {
+		cfg.SDID = "attrs@32473"
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = filepath.Base(os.Args[0])
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+
+	hostname, _ := os.Hostname()
+
+	c := conn{
+		cfg:      cfg,
+		hostname: hostname,
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+
+	h := Handler{
+		conn:  &c,
+		level: slog.Level(cfg.MinLevel),
+	}
+
+	return &h, nil
+}
+
+// Close closes the connection.
+func (h *Handler) Close() error {
+	h.conn.mu.Lock()
+	defer h.conn.mu.Unlock()
+
+	if h.conn.c == nil {
+		return nil
+	}
+
+	err := h.conn.c.Close()
+	h.conn.c = nil
+
+	return err
+}
+
+// Enabled checks whether the given log level is written.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with additional attributes attached.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]param, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(h2.attrs, h.attrs)
+
+	for _, a := range attrs {
+		h2.attrs = appendParam(h2.attrs, h.group, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of all attributes
+// added later with the given name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// Handle formats the record as an RFC 5424 message and writes it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	params := make([]param, len(h.attrs), len(h.attrs)+r.NumAttrs()+2)
+	copy(params, h.attrs)
+
+	params = append(params, param{name: "level", value: logger.Level(r.Level).String()})
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		params = append(params, param{name: "file", value: fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)})
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		params = appendParam(params, h.group, a)
+		return true
+	})
+
+	c := h.conn
+	pri := int(c.cfg.Facility)*8 + severity(r.Level)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s - ",
+		pri,
+		r.Time.UTC().Format(timeLayout),
+		header(c.hostname, 255),
+		header(c.cfg.AppName, 48),
+		c.procID,
+	)
+
+	b.WriteString("[")
+	b.WriteString(c.cfg.SDID)
+	for _, p := range params {
+		fmt.Fprintf(&b, ` %s="%s"`, p.name, escape(p.value))
+	}
+	b.WriteString("] ")
+
+	// A BOM marks the message as UTF-8.
+	b.WriteString("\ufeff")
+	b.WriteString(r.Message)
+
+	return c.write(b.String())
+}
+
+// =============================================================================
+
+// dial connects to the endpoint. The caller must hold c.mu unless the conn
+// isn't shared yet.
+func (c *conn) dial() error {
+	nc, err := net.DialTimeout(c.cfg.Network, c.cfg.Addr, c.cfg.Timeout)
+	if err != nil {
+		return fmt.Errorf("dialing syslog: %w", err)
+	}
+
+	c.c = nc
+	return nil
+}
+
+// write sends the message, reconnecting and retrying once when the
+// connection is broken. Stream transports use octet counting framing.
+func (c *conn) write(msg string) error {
+	if c.cfg.Network == "tcp" || c.cfg.Network == "unix" {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for range 2 {
+		if c.c == nil {
+			if err = c.dial(); err != nil {
+				continue
+			}
+		}
+
+		c.c.SetWriteDeadline(time.Now().Add(c.cfg.Timeout))
+		if _, err = c.c.Write([]byte(msg)); err == nil {
+			return nil
+		}
+
+		c.c.Close()
+		c.c = nil
+	}
+
+	return fmt.Errorf("writing syslog message: %w", err)
+}
+
+// severity maps a slog level to a syslog severity.
+func severity(level slog.Level) int {
+	switch l := logger.Level(level); {
+	case l >= logger.LevelFatal:
+		return 2 // critical
+	case l == logger.LevelAudit:
+		return 5 // notice
+	case l >= logger.LevelError:
+		return 3 // error
+	case l >= logger.LevelWarn:
+		return 4 // warning
+	case l >= logger.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// appendParam converts the attribute, expanding groups into dotted names.
+func appendParam(params []param, prefix string, a slog.Attr) []param {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return params
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			params = appendParam(params, prefix, ga)
+		}
+		return params
+	}
+
+	return append(params, param{name: paramName(prefix + a.Key), value: a.Value.String()})
+}
+
+// paramName makes the key a valid PARAM-NAME: printable ASCII without '=',
+// space, ']' or '"', at most 32 characters.
+func paramName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		name = "_"
+	}
+
+	return name
+}
+
+// escape escapes the characters RFC 5424 reserves in PARAM-VALUE.
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// header makes the value a valid header field: printable ASCII without
+// spaces, capped at max characters, and "-" when empty.
+func header(value string, max int) string {
+	v := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+
+	if len(v) > max {
+		v = v[:max]
+	}
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Expvar is a Provider that publishes its metrics under the "metrics" key
+// of the expvar page, keyed by series.
+type Expvar struct {
+	root *expvar.Map
+	mu   sync.Mutex
+}
+
+// NewExpvar constructs an expvar provider. Providers share the published
+// map since expvar names are process wide.
+func NewExpvar() *Expvar {
+	root, ok := expvar.Get("metrics").(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap("metrics")
+	}
+
+	return &Expvar{
+		root: root,
+	}
+}
+
+// Counter implements the Provider interface.
+func (e *Expvar) Counter(name string, help string, labels Labels) Counter {
+	return e.series(seriesKey(name, labels), func() expvar.Var { return &expvarValue{} }).(*expvarValue)
+}
+
+// Gauge implements the Provider interface.
+func (e *Expvar) Gauge(name string, help string, labels Labels) Gauge {
+	return e.series(seriesKey(name, labels), func() expvar.Var { return &expvarValue{} }).(*expvarValue)
+}
+
+// Histogram implements the Provider interface.
+func (e *Expvar) Histogram(name string, help string, buckets []float64, labels Labels) Histogram {
+	return e.series(seriesKey(name, labels), func() expvar.Var { return newExpvarHistogram(buckets) }).(*expvarHistogram)
+}
+
+// Handler implements the Provider interface.
+func (e *Expvar) Handler() http.Handler {
+	return expvar.Handler()
+}
+
+// series returns the published variable for the key, creating it as needed.
+// A key reused with a different kind panics, as it's a programming error.
+func (e *Expvar) series(key string, create func() expvar.Var) expvar.Var {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if v := e.root.Get(key); v != nil {
+		if fmt.Sprintf("%T", v) != fmt.Sprintf("%T", create()) {
+			panic(fmt.Sprintf("metrics: %s registered as %T", key, v))
+		}
+		return v
+	}
+
+	v := create()
+	e.root.Set(key, v)
+
+	return v
+}
+
+// =============================================================================
+
+// expvarValue adapts expvar.Float to counters and gauges.
+type expvarValue struct {
+	expvar.Float
+}
+
+func (v *expvarValue) Inc() {
+	v.Float.Add(1)
+}
+
+// expvarHistogram renders as a JSON object with the count, sum and the
+// cumulative count of every bucket.
+type expvarHistogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newExpvarHistogram(buckets []float64) *expvarHistogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Sorted(slices.Values(buckets))
+
+	return &expvarHistogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *expvarHistogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// String implements the expvar.Var interface.
+func (h *expvarHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]uint64, len(h.buckets))
+	for i, b := range h.buckets {
+		buckets[formatFloat(b)] = h.counts[i]
+	}
+
+	data, _ := json.Marshal(struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}{
+		Count:   h.count,
+		Sum:     h.sum,
+		Buckets: buckets,
+	})
+
+	return string(data)
+}
@@ -0,0 +1,91 @@
+// Package metrics provides counters, gauges and histograms behind small
+// interfaces, so instrumentation is written once and exposed through expvar
+// or the Prometheus text format depending on what the operator runs.
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Counter is a value that only goes up.
+type Counter interface {
+	Inc()
+	Add(delta float64)
+}
+
+// Gauge is a value that goes up and down.
+type Gauge interface {
+	Set(v float64)
+	Add(delta float64)
+}
+
+// Histogram counts observations in buckets.
+type Histogram interface {
+	Observe(v float64)
+}
+
+// Labels are the dimensions of a metric. Every distinct set of labels is a
+// separate series.
+type Labels map[string]string
+
+// Provider constructs metrics and exposes them over HTTP. Asking for the
+// same name and labels twice returns the same metric. Implementations are
+// safe for concurrent use.
+type Provider interface {
+	Counter(name string, help string, labels Labels) Counter
+	Gauge(name string, help string, labels Labels) Gauge
+	Histogram(name string, help string, buckets []float64, labels Labels) Histogram
+	Handler() http.Handler
+}
+
+// Backend selects how metrics are exposed.
+type Backend string
+
+// Set of backends.
+const (
+	BackendExpvar     Backend = "expvar"
+	BackendPrometheus Backend = "prometheus"
+)
+
+// DefaultBuckets suit latencies measured in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// New constructs the provider for the backend.
+func New(backend Backend) (Provider, error) {
+	switch backend {
+	case BackendExpvar, "":
+		return NewExpvar(), nil
+	case BackendPrometheus:
+		return NewPrometheus(), nil
+	}
+	return nil, fmt.Errorf("unknown metrics backend %q", backend)
+}
+
+// =============================================================================
+
+// seriesKey renders the name and labels in the Prometheus series format,
+// with the labels sorted so the key is stable.
+func seriesKey(name string, labels Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+
+	for i, k := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Prometheus is a Provider that serves its metrics in the Prometheus text
+// exposition format.
+type Prometheus struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string
+	series map[string]any
+}
+
+// NewPrometheus constructs a Prometheus provider.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		families: make(map[string]*family),
+	}
+}
+
+// Counter implements the Provider interface.
+func (p *Prometheus) Counter(name string, help string, labels Labels) Counter {
+	return p.series(name, help, "counter", labels, func() any { return &promValue{} }).(*promValue)
+}
+
+// Gauge implements the Provider interface.
+func (p *Prometheus) Gauge(name string, help string, labels Labels) Gauge {
+	return p.series(name, help, "gauge", labels, func() any { return &promValue{} }).(*promValue)
+}
+
+// Histogram implements the Provider interface.
+func (p *Prometheus) Histogram(name string, help string, buckets []float64, labels Labels) Histogram {
+	return p.series(name, help, "histogram", labels, func() any { return newPromHistogram(buckets) }).(*promHistogram)
+}
+
+// Handler implements the Provider interface.
+func (p *Prometheus) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		p.write(w)
+	})
+}
+
+// series returns the series of the family, creating both as needed. A name
+// reused with a different kind panics, as it's a programming error.
+func (p *Prometheus) series(name string, help string, kind string, labels Labels, create func() any) any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, exists := p.families[name]
+	if !exists {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]any)}
+		p.families[name] = f
+	}
+
+	if f.kind != kind {
+		panic(fmt.Sprintf("metrics: %s registered as %s, requested as %s", name, f.kind, kind))
+	}
+
+	key := seriesKey(name, labels)
+
+	s, exists := f.series[key]
+	if !exists {
+		s = create()
+		f.series[key] = s
+	}
+
+	return s
+}
+
+// write renders every family sorted by name.
+func (p *Prometheus) write(w io.Writer) {
+	p.mu.Lock()
+	families := make([]*family, 0, len(p.families))
+	for _, f := range p.families {
+		families = append(families, f)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	for _, f := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.name, strings.ReplaceAll(f.help, "\n", " "))
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+		p.mu.Lock()
+		keys := slices.Sorted(maps.Keys(f.series))
+		series := make([]any, len(keys))
+		for i, k := range keys {
+			series[i] = f.series[k]
+		}
+		p.mu.Unlock()
+
+		for i, key := range keys {
+			switch s := series[i].(type) {
+			case *promValue:
+				fmt.Fprintf(w, "%s %s\n", key, formatFloat(s.load()))
+
+			case *promHistogram:
+				s.write(w, f.name, strings.TrimPrefix(key, f.name))
+			}
+		}
+	}
+}
+
+// =============================================================================
+
+// promValue is a float64 updated atomically, used by counters and gauges.
+type promValue struct {
+	bits atomic.Uint64
+}
+
+func (v *promValue) Inc() {
+	v.Add(1)
+}
+
+func (v *promValue) Add(delta float64) {
+	for {
+		old := v.bits.Load()
+		if v.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (v *promValue) Set(f float64) {
+	v.bits.Store(math.Float64bits(f))
+}
+
+func (v *promValue) load() float64 {
+	return math.Float64frombits(v.bits.Load())
+}
+
+// promHistogram keeps cumulative bucket counts.
+type promHistogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newPromHistogram(buckets []float64) *promHistogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Sorted(slices.Values(buckets))
+
+	return &promHistogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *promHistogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// write renders the bucket, sum and count series. The labels are in the
+// rendered form, like {method="GET"}, or empty.
+func (h *promHistogram) write(w io.Writer, name string, labels string) {
+	h.mu.Lock()
+	counts := slices.Clone(h.counts)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	inner := strings.TrimSuffix(strings.TrimPrefix(labels, "{"), "}")
+	if inner != "" {
+		inner += ","
+	}
+
+	for i, b := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{%sle=%q} %d\n", name, inner, formatFloat(b), counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, inner, count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, count)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
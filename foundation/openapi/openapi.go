@@ -0,0 +1,122 @@
+// Package openapi builds an OpenAPI 3 document from the route table of a
+// web.App, using reflection over the documented request and response models.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/web"
+)
+
+// Version is the OpenAPI specification version produced by this package.
+const Version = "3.0.3"
+
+// bearerAuth is the name of the security scheme used by protected routes.
+const bearerAuth = "bearerAuth"
+
+// pathParam matches mux wildcards like {id} and {path...}.
+var pathParam = regexp.MustCompile(`\{([a-zA-Z0-9_]+)(\.\.\.)?\}`)
+
+// Build generates the document for the provided routes. Routes marked as
+// hidden are left out.
+func Build(info Info, routes []web.Route) Document {
+	gen := newGenerator()
+
+	doc := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+				},
+			},
+		},
+	}
+
+	errSchema := gen.schemaOf(web.ErrorDocument{})
+
+	for _, route := range routes {
+		if route.Doc.Hidden {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+
+		op := Operation{
+			OperationID: operationID(route.Method, path),
+			Summary:     route.Doc.Summary,
+			Tags:        route.Doc.Tags,
+			Responses:   make(map[string]Response),
+		}
+
+		for _, m := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     m[1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+
+		if route.Doc.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content: map[string]MediaType{
+					"application/json": {Schema: gen.schemaOf(route.Doc.Request)},
+				},
+			}
+		}
+
+		status := route.Doc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		resp := Response{
+			Description: http.StatusText(status),
+		}
+		if route.Doc.Response != nil && status != http.StatusNoContent {
+			resp.Content = map[string]MediaType{
+				"application/json": {Schema: gen.schemaOf(route.Doc.Response)},
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+
+		op.Responses["default"] = Response{
+			Description: "Error",
+			Content: map[string]MediaType{
+				"application/json": {Schema: errSchema},
+			},
+		}
+
+		if route.Doc.Auth {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+
+		item, exists := doc.Paths[path]
+		if !exists {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[method] = &op
+	}
+
+	doc.Components.Schemas = gen.schemas
+
+	return doc
+}
+
+// operationID derives a stable identifier such as "get_v1_users_id".
+func operationID(method string, path string) string {
+	r := strings.NewReplacer("/", "_", "{", "", "}", "", "-", "_")
+	return fmt.Sprintf("%s%s", strings.ToLower(method), r.Replace(path))
+}
@@ -0,0 +1,239 @@
+// Package otel provides otel support.
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+	"github.com/AlmirSai/service/foundation/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Config defines the information needed to init tracing.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string              // Defaults to the version of the binary
+	Host           string              // OTLP gRPC collector, empty disables exporting
+	Probability    float64             // Share of root spans sampled, between 0 and 1
+	ExcludedRoutes map[string]struct{} // URL paths that are never sampled, e.g. health checks
+}
+
+// Provider owns the tracer provider and the sampler so the sampling rate can
+// be changed while the service is running.
+type Provider struct {
+	tp      trace.TracerProvider
+	sampler *sampler
+	close   func(ctx context.Context) error
+}
+
+// InitTracing configures open telemetry to be used with the service. The
+// provider is installed globally along with the W3C trace context and
+// baggage propagators. Without a host a no-op provider is used, which keeps
+// spans cheap and leaves trace IDs to the caller.
+func InitTracing(ctx context.Context, log *logger.Logger, cfg Config) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Host == "" {
+		log.Info(ctx, "otel", "status", "tracing disabled, no collector host")
+
+		p := Provider{
+			tp:      noop.NewTracerProvider(),
+			sampler: newSampler(0, nil),
+			close:   func(context.Context) error { return nil },
+		}
+		otel.SetTracerProvider(p.tp)
+
+		return &p, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithInsecure(),
+		otlptracegrpc.WithEndpoint(cfg.Host),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating new exporter: %w", err)
+	}
+
+	if cfg.ServiceVersion == "" {
+		cfg.ServiceVersion = version.Version()
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	}
+	if rev := version.Revision(); rev != "" {
+		attrs = append(attrs, attribute.String("vcs.revision", rev), attribute.Bool("vcs.modified", version.Dirty()))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+
+	smp := newSampler(cfg.Probability, cfg.ExcludedRoutes)
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(smp)),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxExportBatchSize(sdktrace.DefaultMaxExportBatchSize),
+			sdktrace.WithBatchTimeout(sdktrace.DefaultScheduleDelay*time.Millisecond),
+		),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	log.Info(ctx, "otel", "status", "tracing enabled", "host", cfg.Host, "probability", cfg.Probability)
+
+	p := Provider{
+		tp:      tp,
+		sampler: smp,
+		close:   tp.Shutdown,
+	}
+
+	return &p, nil
+}
+
+// Tracer returns a named tracer from the provider.
+func (p *Provider) Tracer(name string) trace.Tracer {
+	return p.tp.Tracer(name)
+}
+
+// SetProbability changes the share of root spans that are sampled.
+func (p *Provider) SetProbability(probability float64) {
+	p.sampler.setProbability(probability)
+}
+
+// Shutdown flushes the spans that are still buffered and stops the
+// exporter. It should be deferred right after InitTracing.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.close(ctx)
+}
+
+// =============================================================================
+
+// AddSpan adds an otel span to the existing trace. The caller must end the
+// returned span, typically with a defer.
+func AddSpan(ctx context.Context, spanName string, keyValues ...attribute.KeyValue) (context.Context, trace.Span) {
+	v, ok := ctx.Value(tracerKey).(trace.Tracer)
+	if !ok || v == nil {
+		v = otel.Tracer("")
+	}
+
+	ctx, span := v.Start(ctx, spanName)
+	span.SetAttributes(keyValues...)
+
+	return ctx, span
+}
+
+// AddTraceToRequest adds the current trace id to the request so it can be
+// delivered to the service being called.
+func AddTraceToRequest(ctx context.Context, r *http.Request) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// InjectTracing stores the tracer in the context so spans started with
+// AddSpan beneath it use the service tracer.
+func InjectTracing(ctx context.Context, tracer trace.Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey, tracer)
+}
+
+// GetTraceID returns the trace id of the span in the context or an empty
+// string when there is no recording or remote span.
+func GetTraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// GetSpanID returns the span id of the span in the context or an empty
+// string when there is no recording or remote span.
+func GetSpanID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return ""
+	}
+	return sc.SpanID().String()
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const tracerKey ctxKey = 1
+
+// sampler samples root spans at a probability that can be changed at runtime
+// and never samples spans for excluded routes.
+type sampler struct {
+	threshold atomic.Uint64
+	excluded  map[string]struct{}
+}
+
+func newSampler(probability float64, excluded map[string]struct{}) *sampler {
+	s := sampler{
+		excluded: excluded,
+	}
+	s.setProbability(probability)
+	return &s
+}
+
+func (s *sampler) setProbability(probability float64) {
+	probability = max(0, min(1, probability))
+	s.threshold.Store(uint64(probability * math.MaxUint64))
+}
+
+// ShouldSample implements the sdktrace.Sampler interface.
+func (s *sampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(parameters.ParentContext)
+
+	for _, attr := range parameters.Attributes {
+		if attr.Key != "url.path" {
+			continue
+		}
+		if _, exists := s.excluded[attr.Value.AsString()]; exists {
+			return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: psc.TraceState()}
+		}
+	}
+
+	// Use the upper bits of the trace id so the decision is consistent for
+	// every span of the trace.
+	tid := parameters.TraceID
+	x := uint64(tid[0])<<56 | uint64(tid[1])<<48 | uint64(tid[2])<<40 | uint64(tid[3])<<32 |
+		uint64(tid[4])<<24 | uint64(tid[5])<<16 | uint64(tid[6])<<8 | uint64(tid[7])
+
+	if x < s.threshold.Load() {
+		return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: psc.TraceState()}
+	}
+
+	return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: psc.TraceState()}
+}
+
+// Description implements the sdktrace.Sampler interface.
+func (s *sampler) Description() string {
+	return "DynamicTraceIDRatioSampler"
+}
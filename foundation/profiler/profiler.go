@@ -0,0 +1,194 @@
+// Package profiler periodically collects CPU and heap profiles and pushes
+// them to a Pyroscope compatible ingestion endpoint.
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/httpclient"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Config holds the settings for the profiler.
+type Config struct {
+	Log         *logger.Logger
+	URL         string            // Base URL of the ingestion server
+	AppName     string            // Application name the profiles are stored under
+	Labels      map[string]string // Static labels like service, version and pod
+	Interval    time.Duration     // Time between uploads, defaults to 60s
+	CPUDuration time.Duration     // Length of each CPU profile, defaults to 10s
+	Client      *http.Client
+}
+
+// Profiler collects and uploads profiles.
+type Profiler struct {
+	log         *logger.Logger
+	url         string
+	name        string
+	interval    time.Duration
+	cpuDuration time.Duration
+	client      *http.Client
+}
+
+// New constructs a Profiler from the configuration.
+func New(cfg Config) (*Profiler, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("profiler: url is required")
+	}
+
+	if cfg.AppName == "" {
+		return nil, fmt.Errorf("profiler: application name is required")
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = 60 * time.Second
+	}
+
+	if cfg.CPUDuration <= 0 || cfg.CPUDuration > cfg.Interval {
+		cfg.CPUDuration = min(10*time.Second, cfg.Interval)
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = httpclient.New(httpclient.Config{
+			Name:    "profiler",
+			Log:     cfg.Log,
+			Timeout: 30 * time.Second,
+		}).Client
+	}
+
+	p := Profiler{
+		log:         cfg.Log,
+		url:         strings.TrimSuffix(cfg.URL, "/") + "/ingest",
+		name:        cfg.AppName + formatLabels(cfg.Labels),
+		interval:    cfg.Interval,
+		cpuDuration: cfg.CPUDuration,
+		client:      cfg.Client,
+	}
+
+	return &p, nil
+}
+
+// Run collects and uploads profiles on the configured interval until the
+// context is cancelled. Failures are logged and never stop the loop.
+func (p *Profiler) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect captures one CPU and one heap profile and uploads both.
+func (p *Profiler) collect(ctx context.Context) {
+	from := time.Now()
+
+	var cpu bytes.Buffer
+	if err := pprof.StartCPUProfile(&cpu); err != nil {
+		// Another CPU profile is running, for example from the debug endpoint.
+		p.log.Warn(ctx, "profiler", "status", "skipping cpu profile", "error", err)
+	} else {
+		select {
+		case <-time.After(p.cpuDuration):
+		case <-ctx.Done():
+		}
+		pprof.StopCPUProfile()
+
+		if err := p.upload(ctx, "cpu", cpu.Bytes(), from, time.Now()); err != nil {
+			p.log.Warn(ctx, "profiler", "status", "cpu upload failed", "error", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	var heap bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		p.log.Warn(ctx, "profiler", "status", "heap profile failed", "error", err)
+		return
+	}
+
+	now := time.Now()
+	if err := p.upload(ctx, "memory", heap.Bytes(), now, now); err != nil {
+		p.log.Warn(ctx, "profiler", "status", "heap upload failed", "error", err)
+	}
+}
+
+// upload sends a pprof encoded profile to the ingestion endpoint.
+func (p *Profiler) upload(ctx context.Context, kind string, profile []byte, from time.Time, until time.Time) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	fw, err := mw.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+
+	if _, err := fw.Write(profile); err != nil {
+		return err
+	}
+
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("name", strings.Replace(p.name, "{", "."+kind+"{", 1))
+	q.Set("from", fmt.Sprint(from.Unix()))
+	q.Set("until", fmt.Sprint(until.Unix()))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"?"+q.Encode(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ingest returned %s: %s", resp.Status, msg)
+	}
+
+	return nil
+}
+
+// formatLabels renders labels in the {key=value,...} form used by the
+// ingestion API, sorted so the name is stable.
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Memory is a Limiter keeping its state in process. The limit can be changed
+// while it's in use. A Memory limiter is safe for concurrent use.
+type Memory struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	cfg       Config
+	buckets   map[string]*bucket
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type window struct {
+	start time.Time
+	prev  int
+	curr  int
+}
+
+// NewMemory constructs an in-memory limiter.
+func NewMemory(cfg Config) *Memory {
+	m := Memory{
+		now:     time.Now,
+		cfg:     cfg.withDefaults(),
+		buckets: make(map[string]*bucket),
+		windows: make(map[string]*window),
+	}
+	m.lastSweep = m.now()
+
+	return &m
+}
+
+// SetLimit changes the requests allowed per window. The burst follows the
+// limit unless it was configured explicitly.
+func (m *Memory) SetLimit(limit int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.Burst == m.cfg.Limit {
+		m.cfg.Burst = limit
+	}
+	m.cfg.Limit = limit
+}
+
+// Allow implements the Limiter interface.
+func (m *Memory) Allow(ctx context.Context, key string) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.Limit <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	now := m.now()
+	m.sweep(now)
+
+	if m.cfg.Algorithm == SlidingWindow {
+		return m.allowWindow(key, now), nil
+	}
+
+	return m.allowBucket(key, now), nil
+}
+
+// =============================================================================
+
+func (m *Memory) allowBucket(key string, now time.Time) Result {
+	rate := float64(m.cfg.Limit) / float64(m.cfg.Window)
+	burst := float64(m.cfg.Burst)
+
+	b, exists := m.buckets[key]
+	if !exists {
+		b = &bucket{tokens: burst, last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = min(burst, b.tokens+float64(now.Sub(b.last))*rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return Result{
+			Limit:      m.cfg.Limit,
+			RetryAfter: time.Duration(math.Ceil((1 - b.tokens) / rate)),
+		}
+	}
+
+	b.tokens--
+
+	return Result{
+		Allowed:   true,
+		Limit:     m.cfg.Limit,
+		Remaining: int(b.tokens),
+	}
+}
+
+func (m *Memory) allowWindow(key string, now time.Time) Result {
+	start := now.Truncate(m.cfg.Window)
+
+	w, exists := m.windows[key]
+	switch {
+	case !exists:
+		w = &window{start: start}
+		m.windows[key] = w
+
+	case start.Sub(w.start) == m.cfg.Window:
+		w.prev, w.curr, w.start = w.curr, 0, start
+
+	case start.After(w.start):
+		w.prev, w.curr, w.start = 0, 0, start
+	}
+
+	elapsed := float64(now.Sub(start)) / float64(m.cfg.Window)
+	count := float64(w.prev)*(1-elapsed) + float64(w.curr)
+
+	if count >= float64(m.cfg.Limit) {
+		return Result{
+			Limit:      m.cfg.Limit,
+			RetryAfter: slidingRetryAfter(m.cfg.Limit, float64(w.prev), float64(w.curr), elapsed, m.cfg.Window),
+		}
+	}
+
+	w.curr++
+
+	return Result{
+		Allowed:   true,
+		Limit:     m.cfg.Limit,
+		Remaining: max(0, m.cfg.Limit-int(math.Ceil(count))-1),
+	}
+}
+
+// sweep drops the state of keys that have been idle long enough to be back
+// at their full allowance. It runs at most once per window. The lock must be
+// held.
+func (m *Memory) sweep(now time.Time) {
+	idle := max(2*m.cfg.Window, time.Duration(float64(m.cfg.Window)*float64(m.cfg.Burst)/float64(m.cfg.Limit)))
+	if now.Sub(m.lastSweep) < idle {
+		return
+	}
+	m.lastSweep = now
+
+	for k, b := range m.buckets {
+		if now.Sub(b.last) >= idle {
+			delete(m.buckets, k)
+		}
+	}
+
+	for k, w := range m.windows {
+		if now.Sub(w.start) >= 2*m.cfg.Window {
+			delete(m.windows, k)
+		}
+	}
+}
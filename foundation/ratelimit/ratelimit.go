@@ -0,0 +1,117 @@
+// Package ratelimit limits how often a key, like a client address or an
+// outbound dependency, may perform an operation. Token bucket and sliding
+// window algorithms are provided with in-memory state for a single process
+// and Redis state shared between replicas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Algorithm selects how requests are counted.
+type Algorithm int
+
+// Set of algorithms.
+const (
+	// TokenBucket refills Limit tokens per Window up to Burst, so short
+	// bursts are allowed as long as the average rate holds.
+	TokenBucket Algorithm = iota
+
+	// SlidingWindow allows Limit requests in any Window, weighting the
+	// previous fixed window by how much of it still overlaps.
+	SlidingWindow
+)
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case SlidingWindow:
+		return "sliding-window"
+	default:
+		return "token-bucket"
+	}
+}
+
+// ParseAlgorithm parses the name of an algorithm.
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch s {
+	case "token-bucket", "":
+		return TokenBucket, nil
+	case "sliding-window":
+		return SlidingWindow, nil
+	}
+	return 0, fmt.Errorf("unknown rate limit algorithm %q", s)
+}
+
+// Config contains the settings for a limiter.
+type Config struct {
+	Algorithm Algorithm
+	Limit     int           // Requests allowed per Window, zero or less disables limiting
+	Window    time.Duration // Defaults to 1s
+	Burst     int           // Token bucket capacity, defaults to Limit
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Second
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.Limit
+	}
+	return cfg
+}
+
+// Result is the outcome of a request to the limiter.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Requests left right now
+	RetryAfter time.Duration // Wait before the next request is allowed, when rejected
+}
+
+// Limiter is the behavior a rate limiter provides.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Wait blocks until the limiter allows a request for the key or the context
+// is done.
+func Wait(ctx context.Context, l Limiter, key string) error {
+	for {
+		res, err := l.Allow(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if res.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(max(res.RetryAfter, time.Millisecond))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// =============================================================================
+
+// slidingRetryAfter returns how long until the weighted count of a sliding
+// window drops below the limit, given the counts of the previous and current
+// fixed windows and the fraction of the current window that has elapsed.
+func slidingRetryAfter(limit int, prev float64, curr float64, elapsed float64, window time.Duration) time.Duration {
+	remaining := (1 - elapsed) * float64(window)
+
+	if curr >= float64(limit) || prev == 0 {
+		return time.Duration(remaining)
+	}
+
+	// The previous window's weight must fall until prev*(1-e) + curr < limit.
+	target := 1 - (float64(limit)-curr)/prev
+	return time.Duration(max(0, target-elapsed) * float64(window))
+}
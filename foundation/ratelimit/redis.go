@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Limiter keeping its state in Redis so every replica shares the
+// same allowance. The token bucket uses the server clock, so replicas with
+// skewed clocks still agree.
+type Redis struct {
+	client redis.UniversalClient
+	cfg    Config
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedis constructs a Redis limiter. The prefix is added to every key so
+// limiters can share a server.
+func NewRedis(client redis.UniversalClient, prefix string, cfg Config) *Redis {
+	return &Redis{
+		client: client,
+		cfg:    cfg.withDefaults(),
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Allow implements the Limiter interface.
+func (r *Redis) Allow(ctx context.Context, key string) (Result, error) {
+	if r.cfg.Limit <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	if r.cfg.Algorithm == SlidingWindow {
+		return r.allowWindow(ctx, key)
+	}
+
+	return r.allowBucket(ctx, key)
+}
+
+// =============================================================================
+
+// bucketScript refills and takes a token from the bucket in KEYS[1]. ARGV
+// holds the tokens per millisecond, the burst and the key expiry in
+// milliseconds. It returns whether the request is allowed and the tokens
+// left as a string, since Lua numbers are truncated to integers on return.
+var bucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local b = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+
+tokens = math.min(burst, tokens + (now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+
+return {allowed, tostring(tokens)}`)
+
+func (r *Redis) allowBucket(ctx context.Context, key string) (Result, error) {
+	rate := float64(r.cfg.Limit) / float64(r.cfg.Window.Milliseconds())
+	expiry := int64(math.Ceil(float64(r.cfg.Burst)/rate)) + 1000
+
+	res, err := bucketScript.Run(ctx, r.client, []string{r.prefix + key},
+		strconv.FormatFloat(rate, 'f', -1, 64), r.cfg.Burst, expiry).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("token bucket: %w", err)
+	}
+
+	allowed, tokens, err := parseReply(res)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !allowed {
+		return Result{
+			Limit:      r.cfg.Limit,
+			RetryAfter: time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond,
+		}, nil
+	}
+
+	return Result{
+		Allowed:   true,
+		Limit:     r.cfg.Limit,
+		Remaining: int(tokens),
+	}, nil
+}
+
+// windowScript counts the request in the current fixed window KEYS[2] if
+// the weighted count with the previous window KEYS[1] is under the limit.
+// ARGV holds the limit, the elapsed fraction of the current window and the
+// key expiry in milliseconds. It returns whether the request is allowed and
+// both counts.
+var windowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local elapsed = tonumber(ARGV[2])
+
+local prev = tonumber(redis.call("GET", KEYS[1]) or "0")
+local curr = tonumber(redis.call("GET", KEYS[2]) or "0")
+
+if prev * (1 - elapsed) + curr >= limit then
+	return {0, prev, curr}
+end
+
+curr = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+
+return {1, prev, curr}`)
+
+func (r *Redis) allowWindow(ctx context.Context, key string) (Result, error) {
+	now := r.now()
+	window := r.cfg.Window
+	start := now.Truncate(window)
+	elapsed := float64(now.Sub(start)) / float64(window)
+
+	idx := start.UnixMilli() / window.Milliseconds()
+	base := r.prefix + "{" + key + "}:"
+	keys := []string{base + strconv.FormatInt(idx-1, 10), base + strconv.FormatInt(idx, 10)}
+
+	res, err := windowScript.Run(ctx, r.client, keys,
+		r.cfg.Limit, strconv.FormatFloat(elapsed, 'f', -1, 64), 2*window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("sliding window: %w", err)
+	}
+
+	prev, curr := float64(res[1]), float64(res[2])
+
+	if res[0] == 0 {
+		return Result{
+			Limit:      r.cfg.Limit,
+			RetryAfter: slidingRetryAfter(r.cfg.Limit, prev, curr, elapsed, window),
+		}, nil
+	}
+
+	count := prev*(1-elapsed) + curr
+
+	return Result{
+		Allowed:   true,
+		Limit:     r.cfg.Limit,
+		Remaining: max(0, r.cfg.Limit-int(math.Ceil(count))),
+	}, nil
+}
+
+// parseReply decodes the allowed flag and the tokens left returned by the
+// token bucket script.
+func parseReply(res []any) (bool, float64, error) {
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("token bucket: unexpected reply %v", res)
+	}
+
+	allowed, ok := res[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("token bucket: unexpected allowed %v", res[0])
+	}
+
+	s, ok := res[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("token bucket: unexpected tokens %v", res[1])
+	}
+
+	tokens, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("token bucket: tokens: %w", err)
+	}
+
+	return allowed == 1, tokens, nil
+}
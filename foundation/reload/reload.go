@@ -0,0 +1,161 @@
+// Package reload provides a watcher that re-reads a set of settings on SIGHUP
+// or when a backing file changes, and publishes the new values atomically.
+package reload
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// LoadFunc reads a fresh copy of the settings from their sources.
+type LoadFunc[T any] func() (T, error)
+
+// ApplyFunc is called with the new settings after a successful reload.
+type ApplyFunc[T any] func(ctx context.Context, settings T)
+
+// Config holds the options for constructing a Watcher.
+type Config[T any] struct {
+	Log      *logger.Logger
+	Load     LoadFunc[T]
+	File     string        // Optional file to poll for modifications
+	Interval time.Duration // How often the file is polled, defaults to 5s
+}
+
+// Watcher keeps the current settings and swaps them atomically on reload.
+type Watcher[T any] struct {
+	log      *logger.Logger
+	load     LoadFunc[T]
+	file     string
+	interval time.Duration
+	current  atomic.Pointer[T]
+
+	mu      sync.Mutex
+	applies []ApplyFunc[T]
+	modTime time.Time
+}
+
+// New constructs a Watcher and performs the initial load. An error is
+// returned if the settings can't be loaded the first time.
+func New[T any](cfg Config[T]) (*Watcher[T], error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+
+	w := Watcher[T]{
+		log:      cfg.Log,
+		load:     cfg.Load,
+		file:     cfg.File,
+		interval: cfg.Interval,
+	}
+
+	settings, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(&settings)
+	w.modTime = w.fileModTime()
+
+	return &w, nil
+}
+
+// Current returns the settings in effect. The returned value must be treated
+// as read-only since it's shared between goroutines.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// OnChange registers a function to apply new settings. It is called once
+// immediately with the current settings and then after every reload.
+func (w *Watcher[T]) OnChange(ctx context.Context, fn ApplyFunc[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.applies = append(w.applies, fn)
+	fn(ctx, *w.current.Load())
+}
+
+// Reload re-reads the settings and applies them. When loading fails the
+// previous settings remain in effect.
+func (w *Watcher[T]) Reload(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	settings, err := w.load()
+	if err != nil {
+		return err
+	}
+
+	w.current.Store(&settings)
+
+	for _, fn := range w.applies {
+		fn(ctx, settings)
+	}
+
+	return nil
+}
+
+// Run blocks until the context is cancelled, reloading the settings when a
+// SIGHUP arrives or the watched file's modification time changes.
+func (w *Watcher[T]) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// A nil channel blocks forever, which disables polling without a file.
+	var tick <-chan time.Time
+	if w.file != "" {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-hup:
+			w.reload(ctx, "signal")
+
+		case <-tick:
+			mt := w.fileModTime()
+			if mt.Equal(w.modTime) {
+				continue
+			}
+			w.modTime = mt
+			w.reload(ctx, "file")
+		}
+	}
+}
+
+// reload performs a reload and logs the outcome.
+func (w *Watcher[T]) reload(ctx context.Context, trigger string) {
+	if err := w.Reload(ctx); err != nil {
+		w.log.Error(ctx, "reload", "status", "failed, keeping previous settings", "trigger", trigger, "error", err)
+		return
+	}
+
+	w.log.Info(ctx, "reload", "status", "settings applied", "trigger", trigger)
+}
+
+// fileModTime returns the modification time of the watched file or the zero
+// time when there is no file or it can't be read.
+func (w *Watcher[T]) fileModTime() time.Time {
+	if w.file == "" {
+		return time.Time{}
+	}
+
+	fi, err := os.Stat(w.file)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return fi.ModTime()
+}
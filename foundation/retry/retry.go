@@ -0,0 +1,129 @@
+// Package retry runs an operation until it succeeds, backing off
+// exponentially with jitter between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Config controls how an operation is retried. The zero value retries three
+// times starting at 100ms and doubling up to 5s, with 20% jitter.
+type Config struct {
+	Name         string        // Used in logs to identify the operation
+	MaxAttempts  int           // Defaults to 3, a negative value retries until the context is done
+	InitialDelay time.Duration // Delay after the first failure, defaults to 100ms
+	MaxDelay     time.Duration // Upper bound for the delay, defaults to 5s
+	Multiplier   float64       // Growth of the delay per attempt, defaults to 2
+	Jitter       float64       // Fraction of the delay randomized, 0 to 1, defaults to 0.2
+	RetryIf      func(err error) bool
+	Log          *logger.Logger // Optional, logs every failed attempt
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.InitialDelay <= 0 {
+		cfg.InitialDelay = 100 * time.Millisecond
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 5 * time.Second
+	}
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = 2
+	}
+	if cfg.Jitter <= 0 || cfg.Jitter > 1 {
+		cfg.Jitter = 0.2
+	}
+	return cfg
+}
+
+// =============================================================================
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (pe *permanentError) Error() string { return pe.err.Error() }
+func (pe *permanentError) Unwrap() error { return pe.err }
+
+// Permanent wraps the error so Do stops retrying and returns it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsPermanent checks if the error was marked with Permanent.
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// =============================================================================
+
+// Do calls fn until it returns nil, the attempts are exhausted, the error is
+// permanent or rejected by RetryIf, or the context is done. The last error
+// from fn is returned.
+func Do(ctx context.Context, cfg Config, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, cfg, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is Do for operations that return a value.
+func DoValue[T any](ctx context.Context, cfg Config, fn func(ctx context.Context) (T, error)) (T, error) {
+	cfg = cfg.withDefaults()
+
+	delay := cfg.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		if IsPermanent(err) || (cfg.RetryIf != nil && !cfg.RetryIf(err)) {
+			var zero T
+			return zero, err
+		}
+
+		if cfg.MaxAttempts > 0 && attempt >= cfg.MaxAttempts {
+			var zero T
+			return zero, fmt.Errorf("%d attempts: %w", attempt, err)
+		}
+
+		wait := jitter(delay, cfg.Jitter)
+
+		if cfg.Log != nil {
+			cfg.Log.Warn(ctx, "retry", "operation", cfg.Name, "attempt", attempt, "wait", wait.String(), "error", err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, fmt.Errorf("%d attempts: %w", attempt, errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+
+		delay = min(time.Duration(float64(delay)*cfg.Multiplier), cfg.MaxDelay)
+	}
+}
+
+// jitter randomizes the delay by up to the given fraction in either
+// direction so callers that failed together don't retry together.
+func jitter(d time.Duration, fraction float64) time.Duration {
+	spread := float64(d) * fraction
+	return time.Duration(float64(d) - spread + rand.Float64()*2*spread)
+}
@@ -0,0 +1,112 @@
+// Package safego launches goroutines that recover from panics, so a bug in
+// a background task is logged instead of taking the whole process down,
+// and tracks them so shutdown can wait for them to finish.
+package safego
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Go runs fn in a goroutine that logs and swallows a panic. Use it for
+// goroutines nobody waits for; use a Group for the ones shutdown waits for.
+func Go(ctx context.Context, log *logger.Logger, name string, fn func(ctx context.Context)) {
+	go func() {
+		defer recoverPanic(ctx, log, name)
+		fn(ctx)
+	}()
+}
+
+// recoverPanic logs a panic of the goroutine. It must be called directly by
+// a deferred statement.
+func recoverPanic(ctx context.Context, log *logger.Logger, name string) {
+	if rec := recover(); rec != nil {
+		log.LogPanic(ctx, rec, "safego", "status", "goroutine panicked", "name", name)
+	}
+}
+
+// =============================================================================
+
+// Group runs named goroutines under a shared context that's cancelled when
+// the group stops.
+type Group struct {
+	log     *logger.Logger
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// New constructs a group whose goroutines run with a context derived from
+// the parent.
+func New(parent context.Context, log *logger.Logger) *Group {
+	ctx, cancel := context.WithCancel(parent)
+
+	return &Group{
+		log:     log,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[string]int),
+	}
+}
+
+// Context returns the context the goroutines of the group run with.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn in a goroutine of the group. A panic is logged and ends only
+// that goroutine.
+func (g *Group) Go(name string, fn func(ctx context.Context)) {
+	g.mu.Lock()
+	g.running[name]++
+	g.mu.Unlock()
+
+	g.wg.Go(func() {
+		defer func() {
+			g.mu.Lock()
+			if g.running[name]--; g.running[name] == 0 {
+				delete(g.running, name)
+			}
+			g.mu.Unlock()
+		}()
+
+		defer recoverPanic(g.ctx, g.log, name)
+
+		fn(g.ctx)
+	})
+}
+
+// Running returns how many goroutines of every name are still running.
+func (g *Group) Running() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return maps.Clone(g.running)
+}
+
+// Stop cancels the context of the group and waits for its goroutines to
+// return or for ctx to end. The goroutines still running when ctx ends are
+// logged by name.
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		g.log.Warn(ctx, "safego", "status", "goroutines still running", "running", g.Running())
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,86 @@
+// Package scheduler runs recurring background jobs on fixed intervals.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// JobFunc is the work performed on every run of a job.
+type JobFunc func(ctx context.Context) error
+
+// Job describes a unit of recurring work.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Fn       JobFunc
+}
+
+// Scheduler runs a set of jobs until its context is cancelled.
+type Scheduler struct {
+	log *logger.Logger
+
+	mu   sync.Mutex
+	jobs []Job
+}
+
+// New constructs a Scheduler with no jobs.
+func New(log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		log: log,
+	}
+}
+
+// Add registers a job. Jobs added after Run has started are not picked up.
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+}
+
+// Run starts every job on its own goroutine and blocks until the context is
+// cancelled and all in-flight runs have returned. A job never overlaps with
+// itself; a run that takes longer than the interval delays the next one.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Go(func() {
+			s.run(ctx, job)
+		})
+	}
+
+	wg.Wait()
+}
+
+// run executes a single job on its interval.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.log.Info(ctx, "scheduler", "status", "job started", "job", job.Name, "interval", job.Interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info(ctx, "scheduler", "status", "job stopped", "job", job.Name)
+			return
+
+		case <-ticker.C:
+			start := time.Now()
+			if err := job.Fn(ctx); err != nil {
+				s.log.Error(ctx, "scheduler", "status", "job failed", "job", job.Name, "error", err)
+				continue
+			}
+
+			s.log.Debug(ctx, "scheduler", "status", "job completed", "job", job.Name, "took", time.Since(start))
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/httpclient"
+)
+
+// AWSConfig contains the settings for the AWS Secrets Manager provider.
+// Credentials default to the standard AWS environment variables.
+type AWSConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // Overrides the regional endpoint, for tests and VPC endpoints
+	Client          *http.Client
+}
+
+// AWS provides secrets from AWS Secrets Manager. Names are a secret id,
+// optionally followed by #key to pick a key from a JSON secret.
+type AWS struct {
+	cfg AWSConfig
+	now func() time.Time
+}
+
+// NewAWS constructs an AWS Secrets Manager provider.
+func NewAWS(cfg AWSConfig) *AWS {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region)
+	}
+	if cfg.Client == nil {
+		cfg.Client = httpclient.New(httpclient.Config{Name: "secretsmanager"}).Client
+	}
+
+	return &AWS{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Secret implements the Provider interface.
+func (a *AWS) Secret(ctx context.Context, name string) (string, error) {
+	secretID, key, _ := strings.Cut(name, "#")
+
+	body, err := json.Marshal(struct {
+		SecretID string `json:"SecretId"`
+	}{
+		SecretID: secretID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	a.sign(req, body)
+
+	resp, err := a.cfg.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secretsmanager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secretsmanager: %s: %s %s", resp.Status, apiErr.Type, apiErr.Message)
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("secretsmanager: decode: %w", err)
+	}
+
+	if key == "" {
+		return out.SecretString, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(out.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secretsmanager: secret %s isn't a JSON object", secretID)
+	}
+
+	val, exists := fields[key]
+	if !exists {
+		return "", ErrNotFound
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("secretsmanager: key %s isn't a string", key)
+	}
+
+	return s, nil
+}
+
+// sign adds the Signature Version 4 headers to the request.
+func (a *AWS) sign(req *http.Request, body []byte) {
+	const service = "secretsmanager"
+
+	now := a.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.cfg.SessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if a.cfg.SessionToken != "" {
+		headers = []string{"content-type", "host", "x-amz-date", "x-amz-security-token", "x-amz-target"}
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + a.cfg.Region + "/" + service + "/aws4_request"
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, a.cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		a.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
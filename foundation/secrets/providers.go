@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Env provides secrets from environment variables. The name is upper cased
+// and appended to the prefix, so env/db_password with a prefix of SALES_
+// reads SALES_DB_PASSWORD.
+type Env struct {
+	Prefix string
+}
+
+// Secret implements the Provider interface.
+func (e Env) Secret(ctx context.Context, name string) (string, error) {
+	s, ok := os.LookupEnv(e.Prefix + strings.ToUpper(name))
+	if !ok {
+		return "", ErrNotFound
+	}
+	return s, nil
+}
+
+// File provides secrets from files in a folder, such as a mounted
+// Kubernetes secret. Trailing newlines are trimmed.
+type File struct {
+	FS fs.FS
+}
+
+// NewFile constructs a File provider reading from the folder.
+func NewFile(folder string) File {
+	return File{
+		FS: os.DirFS(folder),
+	}
+}
+
+// Secret implements the Provider interface.
+func (f File) Secret(ctx context.Context, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("invalid secret file name %q", name)
+	}
+
+	data, err := fs.ReadFile(f.FS, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("read: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,248 @@
+// Package secrets resolves secret values from pluggable providers such as
+// the environment, mounted files, Vault and AWS Secrets Manager. Values are
+// cached and refreshed in the background, and the Value type redacts itself
+// whenever it's printed, logged or encoded.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Scheme prefixes a config value that holds a reference to a secret instead
+// of the secret itself, like secret://vault/database#password.
+const Scheme = "secret://"
+
+// ErrNotFound is returned by a provider that has no secret with the name.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider is the behavior a secret store provides.
+type Provider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// =============================================================================
+
+const redacted = "[REDACTED]"
+
+// Value holds a secret. The secret is only returned by Reveal, every other
+// representation is redacted.
+type Value struct {
+	s string
+}
+
+// Reveal returns the secret.
+func (v Value) Reveal() string {
+	return v.s
+}
+
+// String implements the fmt.Stringer interface.
+func (v Value) String() string {
+	return redacted
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (v Value) GoString() string {
+	return redacted
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (v Value) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v Value) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// =============================================================================
+
+// Config contains the settings for a Manager.
+type Config struct {
+	Log *logger.Logger
+	TTL time.Duration // Time a value is served from the cache, defaults to 5m
+}
+
+type entry struct {
+	value   string
+	fetched time.Time
+}
+
+// Manager routes references to the registered providers and caches the
+// values they return. A Manager is safe for concurrent use.
+type Manager struct {
+	log       *logger.Logger
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.RWMutex
+	providers map[string]Provider
+	cache     map[string]entry
+}
+
+// New constructs a Manager without providers.
+func New(cfg Config) *Manager {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+
+	return &Manager{
+		log:       cfg.Log,
+		ttl:       cfg.TTL,
+		now:       time.Now,
+		providers: make(map[string]Provider),
+		cache:     make(map[string]entry),
+	}
+}
+
+// Register adds the provider under the name used in references.
+func (m *Manager) Register(name string, p Provider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.providers[name] = p
+}
+
+// Get returns the secret for a reference of the form provider/name, with or
+// without the Scheme prefix. A cached value is returned until its TTL has
+// passed. When a refresh fails the stale value is kept, so a provider outage
+// doesn't take the secret away from a running service.
+func (m *Manager) Get(ctx context.Context, ref string) (Value, error) {
+	ref = strings.TrimPrefix(ref, Scheme)
+
+	m.mu.RLock()
+	e, cached := m.cache[ref]
+	m.mu.RUnlock()
+
+	if cached && m.now().Sub(e.fetched) < m.ttl {
+		return Value{e.value}, nil
+	}
+
+	s, err := m.fetch(ctx, ref)
+	if err != nil {
+		if cached {
+			m.log.Warn(ctx, "secrets", "status", "refresh failed, using cached value", "ref", ref, "error", err)
+			return Value{e.value}, nil
+		}
+		return Value{}, err
+	}
+
+	return Value{s}, nil
+}
+
+// Refresh fetches every cached secret again, keeping the cached value of
+// those that fail.
+func (m *Manager) Refresh(ctx context.Context) {
+	m.mu.RLock()
+	refs := make([]string, 0, len(m.cache))
+	for ref := range m.cache {
+		refs = append(refs, ref)
+	}
+	m.mu.RUnlock()
+
+	for _, ref := range refs {
+		if _, err := m.fetch(ctx, ref); err != nil {
+			m.log.Warn(ctx, "secrets", "status", "refresh failed", "ref", ref, "error", err)
+		}
+	}
+}
+
+// Run refreshes the cached secrets every TTL until the context is done.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
+
+// Resolve walks the struct pointed to by v and replaces every string field
+// holding a reference with the secret. Nested structs are walked as well,
+// so a whole config struct can be resolved after parsing and logging it.
+func (m *Manager) Resolve(ctx context.Context, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("resolve: expected pointer to struct, got %T", v)
+	}
+
+	return m.resolve(ctx, rv.Elem(), "")
+}
+
+// =============================================================================
+
+func (m *Manager) resolve(ctx context.Context, rv reflect.Value, path string) error {
+	for i := range rv.NumField() {
+		f := rv.Field(i)
+		sf := rv.Type().Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Name
+		if path != "" {
+			name = path + "." + sf.Name
+		}
+
+		switch f.Kind() {
+		case reflect.Struct:
+			if err := m.resolve(ctx, f, name); err != nil {
+				return err
+			}
+
+		case reflect.String:
+			if !strings.HasPrefix(f.String(), Scheme) {
+				continue
+			}
+
+			v, err := m.Get(ctx, f.String())
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", name, err)
+			}
+			f.SetString(v.Reveal())
+		}
+	}
+
+	return nil
+}
+
+// fetch reads the secret from its provider and caches it.
+func (m *Manager) fetch(ctx context.Context, ref string) (string, error) {
+	provider, name, ok := strings.Cut(ref, "/")
+	if !ok || name == "" {
+		return "", fmt.Errorf("secret reference %q must have the form provider/name", ref)
+	}
+
+	m.mu.RLock()
+	p, exists := m.providers[provider]
+	m.mu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("secret provider %q isn't registered", provider)
+	}
+
+	s, err := p.Secret(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	m.mu.Lock()
+	m.cache[ref] = entry{value: s, fetched: m.now()}
+	m.mu.Unlock()
+
+	return s, nil
+}
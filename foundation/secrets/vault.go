@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/httpclient"
+)
+
+// VaultConfig contains the settings for the Vault provider.
+type VaultConfig struct {
+	Addr   string // Base URL like https://vault:8200
+	Token  string
+	Mount  string // KV version 2 mount, defaults to secret
+	Client *http.Client
+}
+
+// Vault provides secrets from a Vault KV version 2 engine. Names have the
+// form path#key, like database#password.
+type Vault struct {
+	cfg VaultConfig
+}
+
+// NewVault constructs a Vault provider.
+func NewVault(cfg VaultConfig) *Vault {
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Client == nil {
+		cfg.Client = httpclient.New(httpclient.Config{Name: "vault"}).Client
+	}
+
+	return &Vault{
+		cfg: cfg,
+	}
+}
+
+// Secret implements the Provider interface.
+func (v *Vault) Secret(ctx context.Context, name string) (string, error) {
+	path, key, ok := strings.Cut(name, "#")
+	if !ok || key == "" {
+		return "", fmt.Errorf("vault secret %q must have the form path#key", name)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(v.cfg.Addr, "/"), v.cfg.Mount, strings.TrimPrefix(path, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+
+	resp, err := v.cfg.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrNotFound
+
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var doc struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", fmt.Errorf("vault: decode: %w", err)
+	}
+
+	val, exists := doc.Data.Data[key]
+	if !exists {
+		return "", ErrNotFound
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("vault: key %s isn't a string", key)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,192 @@
+// Package shutdown coordinates a graceful shutdown. Subsystems register the
+// functions that stop them, grouped in phases and each with its own
+// timeout, and a single signal handler runs them in order: servers stop
+// taking traffic first, then workers finish, then resources like the
+// database are closed and finally buffered telemetry is flushed.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// Phase groups shutdown functions. Phases run in the order declared here.
+type Phase int
+
+// Set of shutdown phases.
+const (
+	PhaseServers Phase = iota
+	PhaseWorkers
+	PhaseResources
+	PhaseFlush
+)
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseServers:
+		return "servers"
+	case PhaseWorkers:
+		return "workers"
+	case PhaseResources:
+		return "resources"
+	default:
+		return "flush"
+	}
+}
+
+// Func stops a subsystem. It should return once the subsystem has stopped
+// or the context is done.
+type Func func(ctx context.Context) error
+
+// Config contains the settings for a Manager.
+type Config struct {
+	Log            *logger.Logger
+	Timeout        time.Duration // Bound on the whole shutdown, defaults to 30s
+	DefaultTimeout time.Duration // Per function timeout when none is registered, defaults to 10s
+}
+
+type step struct {
+	name    string
+	phase   Phase
+	timeout time.Duration
+	fn      Func
+}
+
+// Manager holds the registered shutdown functions and listens for the
+// termination signals.
+type Manager struct {
+	cfg     Config
+	signals chan os.Signal
+
+	mu    sync.Mutex
+	steps []step
+	once  sync.Once
+	err   error
+}
+
+// New constructs a Manager listening for SIGINT and SIGTERM.
+func New(cfg Config) *Manager {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.DefaultTimeout <= 0 {
+		cfg.DefaultTimeout = 10 * time.Second
+	}
+
+	m := Manager{
+		cfg:     cfg,
+		signals: make(chan os.Signal, 1),
+	}
+
+	signal.Notify(m.signals, syscall.SIGINT, syscall.SIGTERM)
+
+	return &m
+}
+
+// Signals returns the channel the termination signals arrive on. Components
+// that detect an integrity problem can send to it to request a shutdown.
+func (m *Manager) Signals() chan os.Signal {
+	return m.signals
+}
+
+// Register adds a function to the phase. Within a phase, functions run in
+// the reverse order of registration like deferred calls. A timeout of zero
+// uses the default timeout.
+func (m *Manager) Register(phase Phase, name string, timeout time.Duration, fn Func) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if timeout <= 0 {
+		timeout = m.cfg.DefaultTimeout
+	}
+
+	m.steps = append(m.steps, step{
+		name:    name,
+		phase:   phase,
+		timeout: timeout,
+		fn:      fn,
+	})
+}
+
+// Wait blocks until a termination signal arrives or a server reports an
+// error, then runs the shutdown. The server error, if any, is returned
+// along with the errors of the shutdown functions.
+func (m *Manager) Wait(serverErrors <-chan error) error {
+	var serverErr error
+
+	select {
+	case err := <-serverErrors:
+		serverErr = fmt.Errorf("server error: %w", err)
+		m.cfg.Log.Error(context.Background(), "shutdown", "status", "shutdown started", "error", err)
+
+	case sig := <-m.signals:
+		m.cfg.Log.Info(context.Background(), "shutdown", "status", "shutdown started", "signal", sig)
+	}
+
+	return errors.Join(serverErr, m.Shutdown(context.Background()))
+}
+
+// Shutdown runs the registered functions phase by phase. A function that
+// fails or runs out of time is logged and the shutdown carries on, so one
+// stuck subsystem doesn't keep the others from stopping. It's safe to call
+// more than once, only the first call runs the functions.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() {
+		signal.Stop(m.signals)
+
+		ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		steps := slices.Clone(m.steps)
+		m.mu.Unlock()
+
+		slices.Reverse(steps)
+		slices.SortStableFunc(steps, func(a, b step) int {
+			return int(a.phase) - int(b.phase)
+		})
+
+		var errs []error
+		for _, s := range steps {
+			if err := m.run(ctx, s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			}
+		}
+
+		m.err = errors.Join(errs...)
+
+		m.cfg.Log.Info(ctx, "shutdown", "status", "shutdown completed")
+	})
+
+	return m.err
+}
+
+// run calls a single function with its timeout.
+func (m *Manager) run(ctx context.Context, s step) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	err := s.fn(ctx)
+	if err != nil {
+		m.cfg.Log.Error(ctx, "shutdown", "phase", s.phase.String(), "name", s.name,
+			"duration", time.Since(start).String(), "error", err)
+		return err
+	}
+
+	m.cfg.Log.Info(ctx, "shutdown", "phase", s.phase.String(), "name", s.name,
+		"duration", time.Since(start).String())
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package startup runs the dependency checks a service needs to pass before it
+// reports itself ready, and keeps retrying until the critical ones succeed.
+package startup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/health"
+	"github.com/AlmirSai/service/foundation/logger"
+)
+
+// ErrNotReady is returned by Ready until every critical check has passed.
+var ErrNotReady = errors.New("startup checks have not passed")
+
+// Gate runs the checks of a health registry at startup and holds the
+// readiness state.
+type Gate struct {
+	log      *logger.Logger
+	registry *health.Registry
+	ready    atomic.Bool
+}
+
+// New constructs a Gate over the checks of the registry.
+func New(log *logger.Logger, registry *health.Registry) *Gate {
+	return &Gate{
+		log:      log,
+		registry: registry,
+	}
+}
+
+// Ready returns ErrNotReady until the startup checks have passed. From then
+// on it follows the critical checks of the registry, so a dependency lost
+// later takes the service out of rotation until it's back.
+func (g *Gate) Ready(ctx context.Context) error {
+	if !g.ready.Load() {
+		return ErrNotReady
+	}
+	return g.registry.Ready(ctx)
+}
+
+// Run executes every check in parallel once, logs a structured summary and
+// marks the gate ready when all critical checks pass.
+func (g *Gate) Run(ctx context.Context) (health.Report, error) {
+	report := g.registry.Run(ctx)
+
+	var failed int
+	for _, r := range report.Results {
+		status := "passed"
+		if r.Err != nil {
+			status = "failed"
+			if r.Level == health.Critical {
+				failed++
+			}
+		}
+
+		g.log.Info(ctx, "startup check", "check", r.Name, "level", r.Level.String(), "status", status,
+			"duration", r.Duration.String(), "error", r.Err)
+	}
+
+	if err := report.Err(); err != nil {
+		g.log.Warn(ctx, "startup checks", "status", "not ready", "checks", len(report.Results), "failed", failed)
+		return report, err
+	}
+
+	g.ready.Store(true)
+	g.log.Info(ctx, "startup checks", "status", "ready", "checks", len(report.Results))
+
+	return report, nil
+}
+
+// RunUntilReady repeats Run with the given interval between attempts until
+// the critical checks pass or the context is cancelled.
+func (g *Gate) RunUntilReady(ctx context.Context, interval time.Duration) error {
+	for {
+		if _, err := g.Run(ctx); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package testutil provides golden file and snapshot support for tests.
+// Expected output lives in testdata/<name>.golden next to the test and is
+// rewritten from the actual output when the tests run with -update.
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// update is registered on the flag set of every test binary importing the
+// package, so `go test ./... -update` rewrites the golden files.
+var update = flag.Bool("update", false, "rewrite golden files with the actual output")
+
+// GoldenPath returns the path of the golden file for the name.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Golden compares got against the golden file for the name and fails the
+// test with a line diff when they differ. With -update the golden file is
+// written instead.
+func Golden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	path := GoldenPath(name)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("should be able to create the golden directory: %s", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("should be able to write the golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("should be able to read the golden file, run with -update to create it: %s", err)
+	}
+
+	if diff := Diff(want, got); diff != "" {
+		t.Fatalf("%s: should match the golden file (-want +got):\n%s", path, diff)
+	}
+}
+
+// GoldenJSON normalizes the JSON document with NormalizeJSON and compares it
+// against the golden file for the name.
+func GoldenJSON(t testing.TB, name string, got []byte, ignore ...string) {
+	t.Helper()
+
+	norm, err := NormalizeJSON(got, ignore...)
+	if err != nil {
+		t.Fatalf("should be able to normalize the JSON: %s: %s", err, got)
+	}
+
+	Golden(t, name, norm)
+}
+
+// Diff returns a line by line diff of want and got, or an empty string when
+// they are equal. Windows line endings are ignored.
+func Diff(want []byte, got []byte) string {
+	w := strings.ReplaceAll(string(want), "\r\n", "\n")
+	g := strings.ReplaceAll(string(got), "\r\n", "\n")
+
+	if w == g {
+		return ""
+	}
+
+	return cmp.Diff(strings.Split(w, "\n"), strings.Split(g, "\n"))
+}
@@ -0,0 +1,90 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Volatile lists the keys that differ between runs in the documents and logs
+// of the service. Pass it to NormalizeJSON to mask them.
+var Volatile = []string{"time", "trace_id", "traceID", "id", "dateCreated", "dateUpdated", "file"}
+
+// placeholder replaces the value of an ignored key.
+const placeholder = "<ignored>"
+
+// NormalizeJSON re-encodes the document or the newline separated documents
+// with sorted keys and indentation, and replaces the value of every key in
+// ignore, at any depth, with a placeholder. A key can be a dotted path
+// from the root like "items.id" to only match there.
+func NormalizeJSON(data []byte, ignore ...string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+
+	for dec.More() {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("decode: %w", err)
+		}
+
+		v = mask(v, "", ignore)
+
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode: %w", err)
+		}
+
+		out.Write(b)
+		out.WriteByte('\n')
+	}
+
+	return out.Bytes(), nil
+}
+
+// mask walks the value replacing the ignored keys. Arrays don't add to the
+// path, so "items.id" matches the id of every item.
+func mask(v any, path string, ignore []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if ignored(k, p, ignore) {
+				v[k] = placeholder
+				continue
+			}
+
+			v[k] = mask(child, p, ignore)
+		}
+		return v
+
+	case []any:
+		for i, child := range v {
+			v[i] = mask(child, path, ignore)
+		}
+		return v
+	}
+
+	return v
+}
+
+func ignored(key string, path string, ignore []string) bool {
+	for _, ig := range ignore {
+		if strings.Contains(ig, ".") {
+			if ig == path {
+				return true
+			}
+			continue
+		}
+		if ig == key {
+			return true
+		}
+	}
+	return false
+}
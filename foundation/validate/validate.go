@@ -0,0 +1,105 @@
+// Package validate contains the support for validating models.
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/AlmirSai/service/foundation/web"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+)
+
+// validate holds the settings and caches for validating request struct values.
+var validate *validator.Validate
+
+// translator is a cache of locale and translation information.
+var translator ut.Translator
+
+func init() {
+
+	// Instantiate a validator.
+	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// Create a translator for english so the error messages are
+	// more human-readable than technical.
+	translator, _ = ut.New(en.New(), en.New()).GetTranslator("en")
+
+	// Register the english error messages for use.
+	en_translations.RegisterDefaultTranslations(validate, translator)
+
+	// Cover the tags the default set has no message for.
+	for tag, msg := range map[string]string{
+		"iso4217":          "{0} must be a valid ISO 4217 currency code",
+		"iso3166_1_alpha2": "{0} must be a valid ISO 3166-1 alpha-2 country code",
+	} {
+		validate.RegisterTranslation(tag, translator,
+			func(ut ut.Translator) error {
+				return ut.Add(tag, msg, false)
+			},
+			func(ut ut.Translator, fe validator.FieldError) string {
+				t, _ := ut.T(tag, fe.Field())
+				return t
+			},
+		)
+	}
+
+	// Use JSON tag names for errors instead of Go struct names.
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+}
+
+// Check validates the provided model against it's declared tags. The errors
+// are returned as web.FieldErrors keyed by the JSON path of the field, like
+// "items[0].sku".
+func Check(val any) error {
+	if err := validate.Struct(val); err != nil {
+
+		// Use a type assertion to get the real error value.
+		var verrors validator.ValidationErrors
+		if !errors.As(err, &verrors) {
+			return err
+		}
+
+		fields := make(web.FieldErrors, 0, len(verrors))
+		for _, verror := range verrors {
+			fields = append(fields, web.FieldError{
+				Field: fieldPath(verror.Namespace()),
+				Err:   message(verror),
+				Rule:  verror.Tag(),
+				Param: verror.Param(),
+			})
+		}
+
+		return fields
+	}
+
+	return nil
+}
+
+// fieldPath drops the struct name that leads the namespace of an error, so
+// "NewOrder.items[0].sku" becomes "items[0].sku".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// message returns the translated message of a validation error. Tags without
+// a translation would otherwise leak the raw validator text.
+func message(fe validator.FieldError) string {
+	msg := fe.Translate(translator)
+	if msg == fe.Error() {
+		return fe.Field() + " failed the " + fe.Tag() + " rule"
+	}
+	return msg
+}
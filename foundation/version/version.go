@@ -0,0 +1,114 @@
+// Package version exposes the build metadata of the running binary. It's
+// read once from the Go build information, where the toolchain records the
+// VCS revision and commit time, and can be overridden at link time:
+//
+//	go build -ldflags "-X github.com/AlmirSai/service/foundation/version.version=v1.2.3"
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// version is set by the linker. When empty the version is derived from the
+// build information.
+var version string
+
+// Info is the build metadata of the binary.
+type Info struct {
+	Version   string
+	Revision  string
+	BuildTime time.Time // Time of the commit the binary was built from
+	Dirty     bool      // The working tree had uncommitted changes
+	GoVersion string
+}
+
+// Get returns the build metadata, reading the build information the first
+// time it's called.
+var Get = sync.OnceValue(func() Info {
+	info := Info{
+		Version: version,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		if info.Version == "" {
+			info.Version = "develop"
+		}
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.BuildTime, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			info.Dirty = s.Value == "true"
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = derive(bi.Main.Version, info.Revision, info.Dirty)
+	}
+
+	return info
+})
+
+// derive picks the module version when the binary was built from a tagged
+// module, else the short revision, else "develop".
+func derive(modVersion string, revision string, dirty bool) string {
+	if modVersion != "" && modVersion != "(devel)" {
+		return modVersion
+	}
+
+	if revision == "" {
+		return "develop"
+	}
+
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if dirty {
+		revision += "-dirty"
+	}
+
+	return revision
+}
+
+// Version returns the version of the binary.
+func Version() string {
+	return Get().Version
+}
+
+// Revision returns the VCS revision the binary was built from, or an empty
+// string when it wasn't recorded.
+func Revision() string {
+	return Get().Revision
+}
+
+// BuildTime returns the commit time of the revision, or the zero time when
+// it wasn't recorded.
+func BuildTime() time.Time {
+	return Get().BuildTime
+}
+
+// Dirty reports whether the binary was built with uncommitted changes.
+func Dirty() bool {
+	return Get().Dirty
+}
+
+// UserAgent returns a User-Agent value for the product, like
+// "sales/v1.2.3 (go1.26.0)".
+func UserAgent(product string) string {
+	info := Get()
+	if info.GoVersion == "" {
+		return fmt.Sprintf("%s/%s", product, info.Version)
+	}
+	return fmt.Sprintf("%s/%s (%s)", product, info.Version, info.GoVersion)
+}
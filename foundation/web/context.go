@@ -0,0 +1,63 @@
+package web
+
+import (
+	"context"
+	"time"
+)
+
+type ctxKey int
+
+const key ctxKey = 1
+
+// Values represent state for each request.
+type Values struct {
+	TraceID    string
+	Now        time.Time
+	StatusCode int
+}
+
+// GetValues returns the values from the context.
+func GetValues(ctx context.Context) *Values {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return &Values{
+			TraceID: "00000000-0000-0000-0000-000000000000",
+			Now:     time.Now(),
+		}
+	}
+
+	return v
+}
+
+// GetTraceID returns the trace id from the context.
+func GetTraceID(ctx context.Context) string {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	return v.TraceID
+}
+
+// GetTime returns the time from the context.
+func GetTime(ctx context.Context) time.Time {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return time.Now()
+	}
+	return v.Now
+}
+
+// setStatusCode sets the status code back into the context.
+func setStatusCode(ctx context.Context, statusCode int) {
+	v, ok := ctx.Value(key).(*Values)
+	if !ok {
+		return
+	}
+
+	v.StatusCode = statusCode
+}
+
+// setValues stores the request values into the context.
+func setValues(ctx context.Context, v *Values) context.Context {
+	return context.WithValue(ctx, key, v)
+}
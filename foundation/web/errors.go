@@ -0,0 +1,135 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Error is used to pass an error during the request through the application
+// with web specific context. Errors of this type are considered trusted and
+// their message is returned to the client.
+type Error struct {
+	Err    error
+	Status int
+}
+
+// NewError wraps a provided error with an HTTP status code. This function
+// should be used when handlers encounter expected errors.
+func NewError(err error, status int) error {
+	return &Error{err, status}
+}
+
+// Error implements the error interface. It uses the default message of the
+// wrapped error. This is what will be shown in the services' logs.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap provides access to the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsError checks if an error of type Error exists.
+func IsError(err error) bool {
+	var re *Error
+	return errors.As(err, &re)
+}
+
+// GetError returns a copy of the Error pointer.
+func GetError(err error) *Error {
+	var re *Error
+	if !errors.As(err, &re) {
+		return nil
+	}
+	return re
+}
+
+// ErrorDocument is the form used for API responses from failures in the API.
+type ErrorDocument struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// =============================================================================
+
+// FieldError is used to indicate an error with a specific request field.
+// Rule and Param name the failed validation rule, like "max" and "32", so
+// the message can be localized; they are empty for other errors.
+type FieldError struct {
+	Field string `json:"field"`
+	Err   string `json:"error"`
+	Rule  string `json:"-"`
+	Param string `json:"-"`
+}
+
+// FieldErrors represents a collection of field errors.
+type FieldErrors []FieldError
+
+// NewFieldsError creates a fields error.
+func NewFieldsError(field string, err error) error {
+	return FieldErrors{
+		{
+			Field: field,
+			Err:   err.Error(),
+		},
+	}
+}
+
+// Error implements the error interface.
+func (fe FieldErrors) Error() string {
+	d, err := json.Marshal(fe)
+	if err != nil {
+		return err.Error()
+	}
+	return string(d)
+}
+
+// Fields returns the fields that failed validation.
+func (fe FieldErrors) Fields() map[string]string {
+	m := make(map[string]string, len(fe))
+	for _, fld := range fe {
+		m[fld.Field] = fld.Err
+	}
+	return m
+}
+
+// IsFieldErrors checks if an error of type FieldErrors exists.
+func IsFieldErrors(err error) bool {
+	var fe FieldErrors
+	return errors.As(err, &fe)
+}
+
+// GetFieldErrors returns a copy of the FieldErrors.
+func GetFieldErrors(err error) FieldErrors {
+	var fe FieldErrors
+	if !errors.As(err, &fe) {
+		return nil
+	}
+	return fe
+}
+
+// =============================================================================
+
+// shutdownError is a type used to help with the graceful termination of the service.
+type shutdownError struct {
+	Message string
+}
+
+// NewShutdownError returns an error that causes the framework to signal
+// a graceful shutdown.
+func NewShutdownError(message string) error {
+	return &shutdownError{message}
+}
+
+// Error is the implementation of the error interface.
+func (se *shutdownError) Error() string {
+	return se.Message
+}
+
+// IsShutdown checks to see if the shutdown error is contained
+// in the specified error value.
+func IsShutdown(err error) bool {
+	var se *shutdownError
+	return errors.As(err, &se)
+}
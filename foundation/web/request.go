@@ -0,0 +1,37 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// validator is implemented by request models that can check themselves.
+type validator interface {
+	Validate() error
+}
+
+// Param returns the web call parameters from the request.
+func Param(r *http.Request, key string) string {
+	return r.PathValue(key)
+}
+
+// Decode reads the body of an HTTP request looking for a JSON document. The
+// body is decoded into the provided value. If the provided value is a struct
+// implementing a Validate method, it is called after decoding.
+func Decode(r *http.Request, val any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(val); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if v, ok := val.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package web
+
+// Route describes a single route registered with the App.
+type Route struct {
+	Method string
+	Path   string
+	Doc    RouteDoc
+}
+
+// RouteDoc carries the documentation for a route. The Request and Response
+// fields hold zero values of the models exchanged by the handler, so the
+// shape of the payloads can be derived through reflection.
+type RouteDoc struct {
+	Summary  string
+	Tags     []string
+	Request  any
+	Response any
+	Status   int  // Status code of a successful response, defaults to 200
+	Auth     bool // Whether the route requires a bearer token
+	Hidden   bool // Whether the route is left out of generated documentation
+}
+
+// Describe attaches documentation to the route.
+func (r *Route) Describe(doc RouteDoc) *Route {
+	r.Doc = doc
+	return r
+}
@@ -0,0 +1,195 @@
+// Package web contains a small web framework extension.
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/AlmirSai/service/foundation/otel"
+	"github.com/google/uuid"
+	otelglobal "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Handler is the signature used by all application handlers in this service.
+// Returning an error hands it to the error middleware for a proper response.
+type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+
+// App is the entrypoint into our application and what configures our context
+// object for each of our http handlers. It also keeps a table of every route
+// registered so tooling like documentation generators can introspect it.
+type App struct {
+	mux      *http.ServeMux
+	shutdown chan os.Signal
+	mw       []Middleware
+	tracer   trace.Tracer
+	routes   []*Route
+}
+
+// NewApp creates an App value that handles a set of routes for the application.
+// Every request is traced with the tracer, which may be nil to disable tracing.
+func NewApp(shutdown chan os.Signal, tracer trace.Tracer, mw ...Middleware) *App {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
+
+	return &App{
+		mux:      http.NewServeMux(),
+		shutdown: shutdown,
+		mw:       mw,
+		tracer:   tracer,
+	}
+}
+
+// SignalShutdown is used to gracefully shut down the app when an integrity
+// issue is identified.
+func (a *App) SignalShutdown() {
+	if a.shutdown == nil {
+		return
+	}
+	a.shutdown <- syscall.SIGTERM
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// Routes returns the table of routes registered with the app, in the order
+// they were added.
+func (a *App) Routes() []Route {
+	routes := make([]Route, len(a.routes))
+	for i, r := range a.routes {
+		routes[i] = *r
+	}
+	return routes
+}
+
+// Handle sets a handler function for a given HTTP method and path pair
+// to the application server mux. The route specific middleware is executed
+// first, followed by the application wide middleware.
+func (a *App) Handle(method string, group string, path string, handler Handler, mw ...Middleware) *Route {
+	handler = wrapMiddleware(mw, handler)
+	handler = wrapMiddleware(a.mw, handler)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := a.startSpan(w, r)
+		defer span.End()
+
+		v := Values{
+			TraceID: traceID(span),
+			Now:     time.Now().UTC(),
+		}
+		ctx = setValues(ctx, &v)
+
+		if err := handler(ctx, w, r); err != nil {
+			if validateError(err) {
+				a.SignalShutdown()
+				return
+			}
+		}
+	}
+
+	return a.register(method, group, path, h)
+}
+
+// HandleNoMiddleware sets a handler function for a given HTTP method and path
+// pair to the application server mux without any application wide middleware.
+func (a *App) HandleNoMiddleware(method string, group string, path string, handler Handler) *Route {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := a.startSpan(w, r)
+		defer span.End()
+
+		v := Values{
+			TraceID: traceID(span),
+			Now:     time.Now().UTC(),
+		}
+		ctx = setValues(ctx, &v)
+
+		if err := handler(ctx, w, r); err != nil {
+			if validateError(err) {
+				a.SignalShutdown()
+				return
+			}
+		}
+	}
+
+	return a.register(method, group, path, h)
+}
+
+// HandleRaw registers a standard library handler, for serving things like
+// static assets that don't fit the application handler signature.
+func (a *App) HandleRaw(method string, group string, path string, handler http.Handler) *Route {
+	return a.register(method, group, path, handler.ServeHTTP)
+}
+
+// register adds the handler to the mux and records the route in the table.
+func (a *App) register(method string, group string, path string, h http.HandlerFunc) *Route {
+	finalPath := path
+	if group != "" {
+		finalPath = "/" + group + path
+	}
+
+	a.mux.HandleFunc(fmt.Sprintf("%s %s", method, finalPath), h)
+
+	route := Route{
+		Method: method,
+		Path:   finalPath,
+	}
+	a.routes = append(a.routes, &route)
+
+	return &route
+}
+
+// startSpan initializes the request by adding a span and writing otel
+// related information into the response writer for the response.
+func (a *App) startSpan(w http.ResponseWriter, r *http.Request) (context.Context, trace.Span) {
+	ctx := otelglobal.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx = otel.InjectTracing(ctx, a.tracer)
+
+	ctx, span := a.tracer.Start(ctx, r.Pattern,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("url.path", r.URL.Path)),
+	)
+
+	// Inject the trace information into the response.
+	otelglobal.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+	return ctx, span
+}
+
+// traceID returns the otel trace id of the span, falling back to a random id
+// when tracing isn't enabled so log lines can still be correlated.
+func traceID(span trace.Span) string {
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return uuid.NewString()
+}
+
+// validateError validates the error for special conditions that do not
+// warrant an actual shutdown by the system.
+func validateError(err error) bool {
+	// Ignore syscall.EPIPE and syscall.ECONNRESET errors which occurs
+	// when a write operation happens on the http.ResponseWriter that
+	// has simultaneously been disconnected by the client (TCP
+	// connections is broken). For instance, when large amounts of
+	// data is being written or streamed to the client.
+	switch {
+	case errors.Is(err, syscall.EPIPE):
+		return false
+
+	case errors.Is(err, syscall.ECONNRESET):
+		return false
+	}
+
+	return true
+}